@@ -2,6 +2,7 @@ package main
 
 import (
 	"math/rand/v2"
+	"sync/atomic"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
@@ -12,18 +13,33 @@ const (
 	// Audio output sample rate (Hz).
 	sampleRate = 48000
 
-	// Audio buffer size in samples (not bytes).
+	// Default internal audio buffer length and the supported --audio-buffer-ms range.
 	// Larger buffer = more latency but less chance of underrun.
-	// ~0.1 seconds of audio buffering (48000 samples/sec * 0.1 sec * 2 channels).
-	audioBufferSize = 9600
+	defaultAudioBufferMS = 100
+	minAudioBufferMS     = 10
+	maxAudioBufferMS     = 1000
 )
 
+// audioBufferSamples returns the internal buffer size in samples (not bytes)
+// for a buffer of ms milliseconds, counting both stereo channels.
+func audioBufferSamples(ms int) int {
+	return sampleRate * ms / 1000 * 2
+}
+
 // AudioOptions configures which audio filters are enabled.
 type AudioOptions struct {
 	EnableLowPass  bool // Low-pass filter for anti-aliasing
 	EnableHighPass bool // High-pass filter for DC offset removal
 	EnableSoftClip bool // Soft clipping (vs hard clipping)
 	EnableDither   bool // Triangular dithering
+
+	// DitherSeed seeds the dither RNG so output is reproducible.
+	// Zero selects a time-based seed.
+	DitherSeed uint64
+
+	// BufferMS is the internal sample buffer length in milliseconds.
+	// Zero selects defaultAudioBufferMS.
+	BufferMS int
 }
 
 // AudioPlayer manages audio output for the emulator.
@@ -32,7 +48,13 @@ type AudioPlayer struct {
 	audioContext *audio.Context
 	audioPlayer  *audio.Player
 	sampleBuffer []float32
+	bufferSize   int // Target buffer size in samples; trimmed beyond twice this
 	options      AudioOptions
+	ditherRNG    *rand.Rand
+
+	// Telemetry, read by the display while the audio goroutine calls Read
+	underruns atomic.Uint64 // Reads padded with silence
+	overruns  atomic.Uint64 // Updates that dropped old samples
 
 	// High-pass filter for DC offset removal (single pole)
 	hpFilterLeft  float32
@@ -47,12 +69,20 @@ type AudioPlayer struct {
 func NewAudioPlayer(apuInstance *apu.APU, opts AudioOptions) (*AudioPlayer, error) {
 	audioContext := audio.NewContext(sampleRate)
 
+	bufferMS := opts.BufferMS
+	if bufferMS == 0 {
+		bufferMS = defaultAudioBufferMS
+	}
+	bufferSize := audioBufferSamples(bufferMS)
+
 	// Create the AudioPlayer instance first
 	ap := &AudioPlayer{
 		apu:          apuInstance,
 		audioContext: audioContext,
-		sampleBuffer: make([]float32, 0, audioBufferSize),
+		sampleBuffer: make([]float32, 0, bufferSize),
+		bufferSize:   bufferSize,
 		options:      opts,
+		ditherRNG:    newDitherRNG(opts.DitherSeed),
 	}
 
 	// Create the player using the same AudioPlayer instance
@@ -76,6 +106,19 @@ func NewAudioPlayer(apuInstance *apu.APU, opts AudioOptions) (*AudioPlayer, erro
 	return ap, nil
 }
 
+// newDitherRNG creates the dither RNG for seed, using a time-based seed if seed is 0.
+func newDitherRNG(seed uint64) *rand.Rand {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano()) //nolint:gosec // G115: Any bits make a fine seed
+	}
+	return rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // Weak random is fine for audio dithering
+}
+
+// dither returns triangular dither noise of about one 16-bit LSB.
+func (ap *AudioPlayer) dither() float32 {
+	return (ap.ditherRNG.Float32() + ap.ditherRNG.Float32() - 1.0) / 32768.0
+}
+
 // Start starts audio playback.
 func (ap *AudioPlayer) Start() {
 	if ap.audioPlayer != nil {
@@ -90,6 +133,16 @@ func (ap *AudioPlayer) Stop() {
 	}
 }
 
+// Underruns returns how many reads had too few samples and were padded with silence.
+func (ap *AudioPlayer) Underruns() uint64 {
+	return ap.underruns.Load()
+}
+
+// Overruns returns how many updates overflowed the buffer and dropped old samples.
+func (ap *AudioPlayer) Overruns() uint64 {
+	return ap.overruns.Load()
+}
+
 // Update updates the audio player with samples from the APU.
 func (ap *AudioPlayer) Update() {
 	// Get samples from APU
@@ -100,11 +153,12 @@ func (ap *AudioPlayer) Update() {
 
 	// Limit buffer size to prevent unbounded growth
 	// Allow buffer to grow to 2x target size before trimming
-	maxBufferSize := audioBufferSize * 2
+	maxBufferSize := ap.bufferSize * 2
 	if len(ap.sampleBuffer) > maxBufferSize {
 		// Drop oldest samples to maintain buffer size
-		excess := len(ap.sampleBuffer) - audioBufferSize
+		excess := len(ap.sampleBuffer) - ap.bufferSize
 		ap.sampleBuffer = ap.sampleBuffer[excess:]
+		ap.overruns.Add(1)
 	}
 }
 
@@ -120,6 +174,7 @@ func (ap *AudioPlayer) Read(buf []byte) (int, error) {
 	samplesToWrite := numSamples
 	if availableSamples < numSamples {
 		samplesToWrite = availableSamples
+		ap.underruns.Add(1)
 	}
 
 	// Convert float32 samples to int16 for audio output with optional filtering
@@ -163,8 +218,7 @@ func (ap *AudioPlayer) Read(buf []byte) (int, error) {
 
 		// Apply triangular dithering (if enabled)
 		if ap.options.EnableDither {
-			dither := (rand.Float32() + rand.Float32() - 1.0) / 32768.0 //nolint:gosec // Weak random is fine for audio dithering
-			left += dither
+			left += ap.dither()
 		}
 
 		leftInt16 := int16(left * 32767.0)
@@ -207,8 +261,7 @@ func (ap *AudioPlayer) Read(buf []byte) (int, error) {
 
 		// Apply triangular dithering (if enabled)
 		if ap.options.EnableDither {
-			dither := (rand.Float32() + rand.Float32() - 1.0) / 32768.0 //nolint:gosec // Weak random is fine for audio dithering
-			right += dither
+			right += ap.dither()
 		}
 
 		rightInt16 := int16(right * 32767.0)
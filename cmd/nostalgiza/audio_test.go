@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/richardwooding/nostalgiza/internal/apu"
+)
+
+// newTestAudioPlayer creates an audio player without an audio context,
+// holding the given stereo samples.
+func newTestAudioPlayer(opts AudioOptions, samples []float32) *AudioPlayer {
+	return &AudioPlayer{
+		apu:          apu.New(),
+		sampleBuffer: append([]float32(nil), samples...),
+		bufferSize:   audioBufferSamples(defaultAudioBufferMS),
+		options:      opts,
+		ditherRNG:    newDitherRNG(opts.DitherSeed),
+	}
+}
+
+func TestAudioDitherSeed(t *testing.T) {
+	samples := make([]float32, 512)
+	for i := range samples {
+		samples[i] = float32(i%64)/64 - 0.5
+	}
+
+	read := func(seed uint64) []byte {
+		ap := newTestAudioPlayer(AudioOptions{EnableDither: true, DitherSeed: seed}, samples)
+		buf := make([]byte, len(samples)*2)
+		if _, err := ap.Read(buf); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		return buf
+	}
+
+	if a, b := read(42), read(42); !bytes.Equal(a, b) {
+		t.Error("same seed produced different dithered output")
+	}
+	if a, b := read(42), read(43); bytes.Equal(a, b) {
+		t.Error("different seeds produced identical dithered output")
+	}
+}
+
+func TestAudioUnderrun(t *testing.T) {
+	ap := newTestAudioPlayer(AudioOptions{}, []float32{0.5, 0.5})
+
+	buf := bytes.Repeat([]byte{0xAA}, 16) // 4 stereo samples
+	if _, err := ap.Read(buf); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := ap.Underruns(); got != 1 {
+		t.Errorf("Underruns() = %d, want 1", got)
+	}
+
+	// The one available sample is played, the rest is silence
+	if buf[0] == 0 && buf[1] == 0 {
+		t.Error("first sample is silent, want the buffered sample")
+	}
+	if !bytes.Equal(buf[4:], make([]byte, 12)) {
+		t.Errorf("padding = % X, want silence", buf[4:])
+	}
+
+	// An empty buffer underruns again
+	if _, err := ap.Read(buf); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := ap.Underruns(); got != 2 {
+		t.Errorf("Underruns() = %d, want 2", got)
+	}
+	if !bytes.Equal(buf, make([]byte, 16)) {
+		t.Errorf("buf = % X, want silence", buf)
+	}
+	if got := ap.Overruns(); got != 0 {
+		t.Errorf("Overruns() = %d, want 0", got)
+	}
+}
+
+func TestAudioOverrun(t *testing.T) {
+	ap := newTestAudioPlayer(AudioOptions{}, nil)
+	ap.bufferSize = audioBufferSamples(10)
+
+	ap.sampleBuffer = make([]float32, ap.bufferSize*2)
+	ap.Update()
+	if got := ap.Overruns(); got != 0 {
+		t.Errorf("Overruns() at 2x buffer = %d, want 0", got)
+	}
+
+	ap.sampleBuffer = append(ap.sampleBuffer, 0, 0)
+	ap.Update()
+	if got := ap.Overruns(); got != 1 {
+		t.Errorf("Overruns() = %d, want 1", got)
+	}
+	if got := len(ap.sampleBuffer); got != ap.bufferSize {
+		t.Errorf("buffer length after trim = %d, want %d", got, ap.bufferSize)
+	}
+}
+
+func TestAudioBufferSamples(t *testing.T) {
+	if got := audioBufferSamples(defaultAudioBufferMS); got != 9600 {
+		t.Errorf("audioBufferSamples(%d) = %d, want 9600", defaultAudioBufferMS, got)
+	}
+	if got := audioBufferSamples(20); got != 1920 {
+		t.Errorf("audioBufferSamples(20) = %d, want 1920", got)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"image/color"
+	"math"
+)
+
+// colorCorrection selects how the palette is adjusted to look like a DMG LCD.
+type colorCorrection string
+
+// Color correction modes.
+const (
+	correctionNone  colorCorrection = "none"  // Palette colors as defined
+	correctionLCD   colorCorrection = "lcd"   // Washed-out contrast and brighter midtones
+	correctionGreen colorCorrection = "green" // LCD correction tinted toward green-gray
+)
+
+// LCD correction curve: channels are raised to lcdGamma and compressed into
+// [lcdBlack, lcdWhite], since a DMG LCD never reaches full black or white.
+const (
+	lcdGamma = 0.8
+	lcdBlack = 0.1
+	lcdWhite = 0.9
+)
+
+// greenTint is the green-gray blended in by correctionGreen, with strength greenTintMix.
+var greenTint = color.RGBA{0x9B, 0xBC, 0x0F, 0xFF}
+
+const greenTintMix = 0.4
+
+// correctedPalette returns palette with mode applied to each color.
+// It is computed once when the display is created, not per pixel.
+func correctedPalette(palette [4]color.RGBA, mode colorCorrection) [4]color.RGBA {
+	var corrected [4]color.RGBA
+	for i, c := range palette {
+		corrected[i] = correctColor(c, mode)
+	}
+	return corrected
+}
+
+// correctColor applies mode to c. Unknown modes behave like correctionNone.
+func correctColor(c color.RGBA, mode colorCorrection) color.RGBA {
+	if mode != correctionLCD && mode != correctionGreen {
+		return c
+	}
+
+	rgb := [3]float64{lcdCurve(c.R), lcdCurve(c.G), lcdCurve(c.B)}
+
+	if mode == correctionGreen {
+		// Blend toward the tint, scaled by the corrected luminance
+		luma := 0.299*rgb[0] + 0.587*rgb[1] + 0.114*rgb[2]
+		tint := [3]uint8{greenTint.R, greenTint.G, greenTint.B}
+		for i := range rgb {
+			tinted := luma * float64(tint[i]) / 255
+			rgb[i] = rgb[i]*(1-greenTintMix) + tinted*greenTintMix
+		}
+	}
+
+	return color.RGBA{toChannel(rgb[0]), toChannel(rgb[1]), toChannel(rgb[2]), c.A}
+}
+
+// lcdCurve maps a color channel through the LCD correction curve to 0.0-1.0.
+func lcdCurve(v uint8) float64 {
+	return lcdBlack + (lcdWhite-lcdBlack)*math.Pow(float64(v)/255, lcdGamma)
+}
+
+// toChannel converts a 0.0-1.0 channel value to 0-255.
+func toChannel(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
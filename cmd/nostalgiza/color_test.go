@@ -0,0 +1,47 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestCorrectColor(t *testing.T) {
+	tests := []struct {
+		name string
+		in   color.RGBA
+		mode colorCorrection
+		want color.RGBA
+	}{
+		{"None", color.RGBA{0x88, 0xC0, 0x70, 0xFF}, correctionNone, color.RGBA{0x88, 0xC0, 0x70, 0xFF}},
+		{"Unknown mode", color.RGBA{0x88, 0xC0, 0x70, 0xFF}, "sepia", color.RGBA{0x88, 0xC0, 0x70, 0xFF}},
+		{"LCD black", color.RGBA{0x00, 0x00, 0x00, 0xFF}, correctionLCD, color.RGBA{0x1A, 0x1A, 0x1A, 0xFF}},
+		{"LCD white", color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, correctionLCD, color.RGBA{0xE6, 0xE6, 0xE6, 0xFF}},
+		{"LCD gray", color.RGBA{0x80, 0x80, 0x80, 0xFF}, correctionLCD, color.RGBA{0x8F, 0x8F, 0x8F, 0xFF}},
+		{"LCD palette color", color.RGBA{0x88, 0xC0, 0x70, 0xFF}, correctionLCD, color.RGBA{0x95, 0xBC, 0x83, 0xFF}},
+		{"Green black", color.RGBA{0x00, 0x00, 0x00, 0xFF}, correctionGreen, color.RGBA{0x16, 0x17, 0x10, 0xFF}},
+		{"Green white", color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}, correctionGreen, color.RGBA{0xC2, 0xCD, 0x8F, 0xFF}},
+		{"Green gray", color.RGBA{0x80, 0x80, 0x80, 0xFF}, correctionGreen, color.RGBA{0x79, 0x80, 0x59, 0xFF}},
+		{"Alpha kept", color.RGBA{0xFF, 0xFF, 0xFF, 0x80}, correctionLCD, color.RGBA{0xE6, 0xE6, 0xE6, 0x80}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := correctColor(tt.in, tt.mode); got != tt.want {
+				t.Errorf("correctColor(%v, %q) = %v, want %v", tt.in, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorrectedPalette(t *testing.T) {
+	if got := correctedPalette(dmgPalette, correctionNone); got != dmgPalette {
+		t.Errorf("correctedPalette(none) = %v, want %v", got, dmgPalette)
+	}
+
+	got := correctedPalette(dmgPalette, correctionLCD)
+	for i := range got {
+		if want := correctColor(dmgPalette[i], correctionLCD); got[i] != want {
+			t.Errorf("correctedPalette(lcd)[%d] = %v, want %v", i, got[i], want)
+		}
+	}
+}
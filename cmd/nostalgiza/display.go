@@ -4,7 +4,9 @@ import (
 	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/richardwooding/nostalgiza/internal/emulator"
+	"github.com/richardwooding/nostalgiza/internal/input"
 	"github.com/richardwooding/nostalgiza/internal/ppu"
 )
 
@@ -22,83 +24,294 @@ type Display struct {
 	screen      *ebiten.Image
 	pixels      []byte // Pre-allocated pixel buffer to avoid GC pressure
 	audioPlayer *AudioPlayer
+	scaleMode   scaleMode
+	palette     [4]color.RGBA // dmgPalette with color correction applied
+
+	// Frame pacing: cycles are budgeted per tick and whole frames are run
+	// once enough have accumulated
+	cyclesPerTick float64
+	cycleBudget   float64
+
+	// Frame skip: only every (frameSkip+1)th Draw presents a new screen
+	frameSkip int
+	drawCount uint64
+
+	// lcdOffBlank shows a blank screen while the LCD is off instead of the
+	// last frame
+	lcdOffBlank bool
+
+	// pauseOnUnfocus stops emulation and audio while the window is not
+	// focused; paused is true while it is stopped
+	pauseOnUnfocus bool
+	paused         bool
+
+	// recorder captures emulated frames into a GIF (nil = not recording)
+	recorder *gifRecorder
+
+	// Debug overlay (toggled with overlayKey)
+	overlay       bool
+	overlayToggle keyToggle
+
+	fullscreenToggle keyToggle
+
+	// Save state slots, stored next to romPath (empty = disabled)
+	romPath     string
+	slotToggles [stateSlots]keyToggle
+
+	// Status message shown at the bottom of the screen for statusTicks more ticks
+	status         string
+	statusTicks    int
+	statusDuration int
+}
+
+// keyToggle detects key presses, so that holding a key toggles only once.
+type keyToggle struct {
+	down bool
+}
+
+// pressed reports whether key was pressed since the last call.
+func (k *keyToggle) pressed(key ebiten.Key) bool {
+	down := ebiten.IsKeyPressed(key)
+	pressed := down && !k.down
+	k.down = down
+	return pressed
+}
+
+// DisplayOptions configures the display.
+type DisplayOptions struct {
+	Audio           AudioOptions
+	ScaleMode       scaleMode
+	ColorCorrection colorCorrection
+
+	// NoAudio skips creating the audio player (and its audio context).
+	NoAudio bool
+
+	// FPS is the emulated frame rate in frames per real second.
+	// Ebiten must be set to tickRate(FPS) ticks per second.
+	FPS float64
+
+	// FrameSkip skips drawing this many frames after each drawn one.
+	// Ebiten must not clear the screen every frame when it is set.
+	FrameSkip int
+
+	// LCDOffBlank shows a blank white screen while LCDC bit 7 is clear,
+	// as real hardware does, instead of the last frame drawn.
+	LCDOffBlank bool
+
+	// PauseOnUnfocus pauses emulation and audio while the window does not
+	// have focus.
+	PauseOnUnfocus bool
+
+	// ROMPath is the ROM file. Save state slots are stored next to it;
+	// empty disables them.
+	ROMPath string
+
+	// Recorder, if set, captures emulated frames into an animated GIF.
+	// The caller must call its finish method when the game exits.
+	Recorder *gifRecorder
 }
 
 // NewDisplay creates a new display for the emulator.
-func NewDisplay(emu *emulator.Emulator, audioOpts AudioOptions) *Display {
-	// Create audio player
-	audioPlayer, err := NewAudioPlayer(emu.APU, audioOpts)
-	if err != nil {
-		// Audio is optional - continue without it if initialization fails
-		audioPlayer = nil
-	} else {
-		// Start audio playback
-		audioPlayer.Start()
+func NewDisplay(emu *emulator.Emulator, opts DisplayOptions) *Display {
+	return &Display{
+		emulator:       emu,
+		screen:         ebiten.NewImage(ppu.ScreenWidth, ppu.ScreenHeight),
+		pixels:         make([]byte, ppu.ScreenWidth*ppu.ScreenHeight*4), // RGBA format
+		audioPlayer:    startAudio(emu, opts),
+		scaleMode:      opts.ScaleMode,
+		palette:        correctedPalette(dmgPalette, opts.ColorCorrection),
+		cyclesPerTick:  cyclesPerTick(opts.FPS, tickRate(opts.FPS)),
+		frameSkip:      opts.FrameSkip,
+		lcdOffBlank:    opts.LCDOffBlank,
+		pauseOnUnfocus: opts.PauseOnUnfocus,
+		recorder:       opts.Recorder,
+		romPath:        opts.ROMPath,
+		statusDuration: 2 * tickRate(opts.FPS), // 2 seconds
 	}
+}
 
-	return &Display{
-		emulator:    emu,
-		screen:      ebiten.NewImage(ppu.ScreenWidth, ppu.ScreenHeight),
-		pixels:      make([]byte, ppu.ScreenWidth*ppu.ScreenHeight*4), // RGBA format
-		audioPlayer: audioPlayer,
+// screenBlank reports whether the screen should be drawn blank for the given
+// LCDC value: the LCD is off and blanking is enabled.
+func screenBlank(lcdc uint8, lcdOffBlank bool) bool {
+	return lcdOffBlank && lcdc&ppu.LCDCLCDEnable == 0
+}
+
+// newAudioPlayer creates the audio player; tests replace it.
+var newAudioPlayer = NewAudioPlayer
+
+// startAudio creates and starts the audio player, or returns nil if audio is
+// disabled or unavailable.
+func startAudio(emu *emulator.Emulator, opts DisplayOptions) *AudioPlayer {
+	if opts.NoAudio {
+		return nil
 	}
+
+	audioPlayer, err := newAudioPlayer(emu.APU, opts.Audio)
+	if err != nil {
+		// Audio is optional - continue without it if initialization fails
+		return nil
+	}
+	audioPlayer.Start()
+	return audioPlayer
 }
 
-// Update updates the game logic (runs one frame worth of cycles).
-// This is called 60 times per second by Ebiten.
+// Update updates the game logic (runs the frames due this tick).
+// This is called tickRate(FPS) times per second by Ebiten.
 func (d *Display) Update() error {
+	// Neither input nor emulation runs while paused
+	d.setPaused(pausedForFocus(ebiten.IsFocused(), d.pauseOnUnfocus))
+	if d.paused {
+		return nil
+	}
+
+	if d.statusTicks > 0 {
+		d.statusTicks--
+	}
+
 	// Handle keyboard input
 	d.handleInput()
 
-	// Game Boy runs at ~59.73 Hz, which is close to 60 Hz
-	// One frame = 70,224 cycles
-	d.emulator.RunCycles(ppu.DotsPerFrame)
+	d.tick()
+
+	// Write the GIF as soon as the recording is complete
+	if d.recorder != nil && d.recorder.complete() {
+		if err := d.recorder.finish(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pausedForFocus reports whether emulation should be paused: the window is
+// not focused and pausing on unfocus is enabled.
+func pausedForFocus(focused, pauseOnUnfocus bool) bool {
+	return pauseOnUnfocus && !focused
+}
+
+// setPaused pauses or resumes emulation, stopping the audio player while
+// paused so it does not play out stale samples.
+func (d *Display) setPaused(paused bool) {
+	if paused == d.paused {
+		return
+	}
+	d.paused = paused
+	if d.audioPlayer == nil {
+		return
+	}
+	if paused {
+		d.audioPlayer.Stop()
+	} else {
+		d.audioPlayer.Start()
+	}
+}
+
+// tick runs the frames due this tick and feeds new samples to the audio player.
+func (d *Display) tick() {
+	// Ebiten ticks at a whole number of times per second, but the Game Boy
+	// runs at ~59.73 Hz. Budget cycles per tick and run whole frames
+	// (70,224 cycles each), so the occasional tick runs no frame.
+	d.cycleBudget += d.cyclesPerTick
+	for d.cycleBudget >= ppu.DotsPerFrame {
+		d.emulator.RunFrame()
+		d.cycleBudget -= ppu.DotsPerFrame
+
+		if d.recorder != nil {
+			d.recordFrame()
+		}
+	}
 
 	// Update audio player with new samples
 	if d.audioPlayer != nil {
 		d.audioPlayer.Update()
 	}
+}
 
-	return nil
+// recordFrame captures the frame just emulated into the GIF recording.
+func (d *Display) recordFrame() {
+	p := d.emulator.PPU
+	if screenBlank(p.LCDC(), d.lcdOffBlank) {
+		var blank [ppu.ScreenWidth * ppu.ScreenHeight]uint8
+		d.recorder.capture(&blank)
+		return
+	}
+	d.recorder.capture(p.GetFramebuffer())
+}
+
+// keyMap maps keyboard keys to Game Boy buttons, checked in order.
+var keyMap = [...]struct {
+	key    ebiten.Key
+	button input.Button
+}{
+	{ebiten.KeyArrowUp, input.ButtonUp},
+	{ebiten.KeyArrowDown, input.ButtonDown},
+	{ebiten.KeyArrowLeft, input.ButtonLeft},
+	{ebiten.KeyArrowRight, input.ButtonRight},
+	{ebiten.KeyZ, input.ButtonA},
+	{ebiten.KeyX, input.ButtonB},
+	{ebiten.KeyEnter, input.ButtonStart},
+	{ebiten.KeyShift, input.ButtonSelect},
 }
 
 // handleInput processes keyboard input and updates joypad state.
 func (d *Display) handleInput() {
-	// Map keyboard keys to Game Boy buttons
-	keyMap := map[ebiten.Key]string{
-		ebiten.KeyArrowUp:    "Up",
-		ebiten.KeyArrowDown:  "Down",
-		ebiten.KeyArrowLeft:  "Left",
-		ebiten.KeyArrowRight: "Right",
-		ebiten.KeyZ:          "A",
-		ebiten.KeyX:          "B",
-		ebiten.KeyEnter:      "Start",
-		ebiten.KeyShift:      "Select",
-	}
-
 	// Check each key and update joypad state
-	for key, button := range keyMap {
-		if ebiten.IsKeyPressed(key) {
-			d.emulator.Joypad.PressButton(button)
+	for _, m := range keyMap {
+		if ebiten.IsKeyPressed(m.key) {
+			d.emulator.Press(m.button)
 		} else {
-			d.emulator.Joypad.ReleaseButton(button)
+			d.emulator.Release(m.button)
+		}
+	}
+
+	// Toggle the debug overlay and fullscreen on key press (not while held)
+	if d.overlayToggle.pressed(overlayKey) {
+		d.overlay = !d.overlay
+	}
+	if d.fullscreenToggle.pressed(fullscreenKey) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+
+	// Save a state slot, or load it with Shift held
+	if d.romPath != "" {
+		load := ebiten.IsKeyPressed(ebiten.KeyShift)
+		for i, key := range stateSlotKeys {
+			if d.slotToggles[i].pressed(key) {
+				d.showStatus(stateSlotMessage(d.emulator, d.romPath, i+1, load))
+			}
 		}
 	}
 }
 
+// showStatus shows a brief message at the bottom of the screen.
+func (d *Display) showStatus(message string) {
+	d.status = message
+	d.statusTicks = d.statusDuration
+}
+
 // Draw draws the game screen.
 // This is called after Update.
 func (d *Display) Draw(screen *ebiten.Image) {
+	// Keep the previous screen on skipped frames
+	present := presentFrame(d.drawCount, d.frameSkip)
+	d.drawCount++
+	if !present {
+		return
+	}
+
 	// Get framebuffer from PPU
 	framebuffer := d.emulator.PPU.GetFramebuffer()
+	blank := screenBlank(d.emulator.PPU.LCDC(), d.lcdOffBlank)
 
 	// Convert framebuffer to RGBA image using bulk pixel update
 	// This is much faster than individual Set() calls per pixel
 	// Reuse pre-allocated pixel buffer to avoid GC pressure
 
 	for i, colorIndex := range framebuffer {
-		// Map to DMG palette
-		c := dmgPalette[colorIndex&0x03]
+		// Map to DMG palette; a disabled LCD shows the lightest shade
+		if blank {
+			colorIndex = 0
+		}
+		c := d.palette[colorIndex&0x03]
 
 		// Write RGBA values
 		offset := i * 4
@@ -108,14 +321,49 @@ func (d *Display) Draw(screen *ebiten.Image) {
 		d.pixels[offset+3] = c.A
 	}
 
+	// Outline sprites for the debug overlay
+	p := d.emulator.PPU
+	if d.overlay {
+		oam := p.OAMSnapshot()
+		drawSpriteBoxes(d.pixels, &oam, spriteHeight(p.LCDC()))
+	}
+
 	// Write all pixels at once (much faster than 23,040 individual Set() calls)
 	d.screen.WritePixels(d.pixels)
 
-	// Draw the screen to the window
-	screen.DrawImage(d.screen, nil)
+	// Scale the screen to the window according to the scale mode
+	bounds := screen.Bounds()
+	dst := destRect(d.scaleMode, bounds.Dx(), bounds.Dy(), ppu.ScreenWidth, ppu.ScreenHeight)
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterNearest}
+	op.GeoM.Scale(float64(dst.Dx())/ppu.ScreenWidth, float64(dst.Dy())/ppu.ScreenHeight)
+	op.GeoM.Translate(float64(dst.Min.X), float64(dst.Min.Y))
+
+	// Draw the screen to the window, with black bars around it
+	screen.Fill(color.Black)
+	screen.DrawImage(d.screen, op)
+
+	if d.overlay {
+		text := overlayText(p) + "\n" + apuStateText(d.emulator.APU.DebugState())
+		if d.audioPlayer != nil {
+			text += "\n" + audioStatsText(d.audioPlayer.Underruns(), d.audioPlayer.Overruns())
+		}
+		ebitenutil.DebugPrint(screen, text)
+	}
+
+	if d.statusTicks > 0 {
+		ebitenutil.DebugPrintAt(screen, d.status, 0, bounds.Dy()-16) // One debug text line
+	}
 }
 
-// Layout returns the game screen size.
-func (d *Display) Layout(_, _ int) (int, int) {
-	return ppu.ScreenWidth, ppu.ScreenHeight
+// Layout returns the window size in device pixels, so that Draw controls
+// scaling and stays sharp on HiDPI displays.
+func (d *Display) Layout(outsideWidth, outsideHeight int) (int, int) {
+	if outsideWidth <= 0 || outsideHeight <= 0 {
+		return ppu.ScreenWidth, ppu.ScreenHeight
+	}
+	factor := 1.0
+	if m := ebiten.Monitor(); m != nil {
+		factor = m.DeviceScaleFactor()
+	}
+	return devicePixels(outsideWidth, factor), devicePixels(outsideHeight, factor)
 }
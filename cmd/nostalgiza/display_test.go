@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/richardwooding/nostalgiza/internal/apu"
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+)
+
+// newTestEmulator creates an emulator running a ROM that loops forever.
+func newTestEmulator(t *testing.T) *emulator.Emulator {
+	t.Helper()
+
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0134:], "TEST")
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+	copy(rom[0x0100:], []byte{0x18, 0xFE}) // JR -2
+
+	emu, err := emulator.New(rom)
+	if err != nil {
+		t.Fatalf("emulator.New() error = %v", err)
+	}
+	return emu
+}
+
+func TestStartAudioDisabled(t *testing.T) {
+	saved := newAudioPlayer
+	defer func() { newAudioPlayer = saved }()
+	newAudioPlayer = func(*apu.APU, AudioOptions) (*AudioPlayer, error) {
+		t.Error("audio player created with audio disabled")
+		return nil, nil
+	}
+
+	emu := newTestEmulator(t)
+	if ap := startAudio(emu, DisplayOptions{NoAudio: true}); ap != nil {
+		t.Fatalf("startAudio() = %v, want nil", ap)
+	}
+
+	// The display runs frames without an audio player
+	d := &Display{emulator: emu, cyclesPerTick: cyclesPerTick(60, 60)}
+	for range 3 {
+		d.tick()
+	}
+	if got := emu.PPU.FrameCount(); got < 2 {
+		t.Errorf("FrameCount() = %d, want at least 2", got)
+	}
+}
+
+func TestScreenBlank(t *testing.T) {
+	tests := []struct {
+		name        string
+		lcdc        uint8
+		lcdOffBlank bool
+		want        bool
+	}{
+		{"LCD on", 0x91, true, false},
+		{"LCD off", 0x11, true, true},
+		{"LCD off, all bits clear", 0x00, true, true},
+		{"LCD off, blanking disabled", 0x11, false, false},
+		{"LCD on, blanking disabled", 0x91, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := screenBlank(tt.lcdc, tt.lcdOffBlank); got != tt.want {
+				t.Errorf("screenBlank(0x%02X, %v) = %v, want %v", tt.lcdc, tt.lcdOffBlank, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPausedForFocus(t *testing.T) {
+	tests := []struct {
+		name           string
+		focused        bool
+		pauseOnUnfocus bool
+		want           bool
+	}{
+		{"focused", true, true, false},
+		{"unfocused", false, true, true},
+		{"focused, pausing disabled", true, false, false},
+		{"unfocused, pausing disabled", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pausedForFocus(tt.focused, tt.pauseOnUnfocus); got != tt.want {
+				t.Errorf("pausedForFocus(%v, %v) = %v, want %v", tt.focused, tt.pauseOnUnfocus, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"math"
+	"os"
+
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+// Maximum --record-every value.
+const maxRecordEvery = 10
+
+// gifRecorder captures emulated frames and encodes them as an animated GIF.
+type gifRecorder struct {
+	path      string
+	palette   color.Palette
+	every     int     // Capture every Nth frame
+	maxFrames int     // Frames captured before the recording is complete
+	frameTime float64 // Duration of one emulated frame in 1/100 s
+
+	frames  uint64  // Emulated frames seen
+	elapsed float64 // Time covered by captured frames in 1/100 s
+	anim    gif.GIF
+	done    bool
+}
+
+// newGIFRecorder creates a recorder that writes path after capturing seconds
+// of gameplay at fps emulated frames per second, keeping every Nth frame.
+func newGIFRecorder(path string, seconds int, every int, fps float64, palette [4]color.RGBA) *gifRecorder {
+	pal := make(color.Palette, len(palette))
+	for i, c := range palette {
+		pal[i] = c
+	}
+	return &gifRecorder{
+		path:      path,
+		palette:   pal,
+		every:     every,
+		maxFrames: max(1, int(float64(seconds)*fps)/every),
+		frameTime: 100 / fps,
+	}
+}
+
+// capture records an emulated frame if it is due. It returns true once the
+// recording has all its frames; later frames are ignored.
+func (r *gifRecorder) capture(framebuffer *[ppu.ScreenWidth * ppu.ScreenHeight]uint8) bool {
+	if r.complete() {
+		return true
+	}
+	r.frames++
+	if (r.frames-1)%uint64(r.every) != 0 { //nolint:gosec // every is validated positive
+		return false
+	}
+
+	// GIF delays are whole hundredths of a second, so round the running
+	// total rather than each frame to keep the clip at the right speed
+	before := math.Round(r.elapsed)
+	r.elapsed += r.frameTime * float64(r.every)
+	delay := int(math.Round(r.elapsed) - before)
+
+	r.anim.Image = append(r.anim.Image, palettedFrame(framebuffer, r.palette))
+	r.anim.Delay = append(r.anim.Delay, delay)
+	return r.complete()
+}
+
+// complete reports whether all frames have been captured.
+func (r *gifRecorder) complete() bool {
+	return len(r.anim.Image) >= r.maxFrames
+}
+
+// finish encodes the captured frames to the output file. It is safe to call
+// more than once, for example when the window is closed before the recording
+// is complete; only the first call writes the file.
+func (r *gifRecorder) finish() (err error) {
+	if r.done {
+		return nil
+	}
+	r.done = true
+	if len(r.anim.Image) == 0 {
+		return nil
+	}
+
+	f, err := os.Create(r.path) // #nosec G304 - path is provided by the user via CLI argument
+	if err != nil {
+		return fmt.Errorf("failed to create GIF: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	if err := gif.EncodeAll(f, &r.anim); err != nil {
+		return fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return nil
+}
+
+// palettedFrame converts a framebuffer of shade indices (0-3) into a paletted
+// image using palette.
+func palettedFrame(framebuffer *[ppu.ScreenWidth * ppu.ScreenHeight]uint8, palette color.Palette) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, ppu.ScreenWidth, ppu.ScreenHeight), palette)
+	for i, colorIndex := range framebuffer {
+		img.Pix[i] = colorIndex & 0x03
+	}
+	return img
+}
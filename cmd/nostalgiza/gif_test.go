@@ -0,0 +1,116 @@
+package main
+
+import (
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+func TestPalettedFrame(t *testing.T) {
+	var framebuffer [ppu.ScreenWidth * ppu.ScreenHeight]uint8
+	for i := range framebuffer {
+		framebuffer[i] = uint8(i % 4) //nolint:gosec // G115: i%4 fits in a byte
+	}
+	framebuffer[5] = 0xFE // Only the low 2 bits are a shade
+
+	palette := newGIFRecorder("", 1, 1, 60, dmgPalette).palette
+	img := palettedFrame(&framebuffer, palette)
+
+	if got := img.Bounds(); got.Dx() != ppu.ScreenWidth || got.Dy() != ppu.ScreenHeight {
+		t.Fatalf("bounds = %v, want %dx%d", got, ppu.ScreenWidth, ppu.ScreenHeight)
+	}
+	if len(img.Palette) != 4 {
+		t.Fatalf("palette has %d colors, want 4", len(img.Palette))
+	}
+	for i, want := range dmgPalette {
+		if img.Palette[i] != want {
+			t.Errorf("palette[%d] = %v, want %v", i, img.Palette[i], want)
+		}
+	}
+
+	tests := []struct {
+		x, y int
+		want uint8
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{3, 0, 3},
+		{5, 0, 2},
+		{0, 1, 0}, // 160 % 4 == 0
+		{ppu.ScreenWidth - 1, ppu.ScreenHeight - 1, 3},
+	}
+	for _, tt := range tests {
+		if got := img.ColorIndexAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("ColorIndexAt(%d, %d) = %d, want %d", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestGIFRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+
+	// 1 second at 60 fps, keeping every other frame: 30 frames
+	r := newGIFRecorder(path, 1, 2, 60, dmgPalette)
+
+	var framebuffer [ppu.ScreenWidth * ppu.ScreenHeight]uint8
+	frames := 0
+	for !r.capture(&framebuffer) {
+		frames++
+		if frames > 1000 {
+			t.Fatal("recording never completed")
+		}
+	}
+	if frames != 58 {
+		t.Errorf("emulated frames before completion = %d, want 58", frames)
+	}
+
+	// Frames after completion are ignored
+	r.capture(&framebuffer)
+	if len(r.anim.Image) != 30 {
+		t.Errorf("captured %d frames, want 30", len(r.anim.Image))
+	}
+
+	// The delays add up to the recorded duration
+	total := 0
+	for _, delay := range r.anim.Delay {
+		total += delay
+	}
+	if total != 100 {
+		t.Errorf("total delay = %d hundredths, want 100", total)
+	}
+
+	if err := r.finish(); err != nil {
+		t.Fatalf("finish() error = %v", err)
+	}
+	if err := r.finish(); err != nil {
+		t.Fatalf("second finish() error = %v", err)
+	}
+
+	f, err := os.Open(path) // #nosec G304 - path is a test temp file
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	decoded, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("DecodeAll() error = %v", err)
+	}
+	if len(decoded.Image) != 30 {
+		t.Errorf("decoded %d frames, want 30", len(decoded.Image))
+	}
+}
+
+func TestGIFRecorderNoFrames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.gif")
+	r := newGIFRecorder(path, 1, 1, 60, dmgPalette)
+
+	if err := r.finish(); err != nil {
+		t.Fatalf("finish() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Stat() error = %v, want not exist (no file without frames)", err)
+	}
+}
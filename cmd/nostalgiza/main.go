@@ -2,15 +2,29 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/richardwooding/nostalgiza/internal/cartridge"
+	"github.com/richardwooding/nostalgiza/internal/debugger"
 	"github.com/richardwooding/nostalgiza/internal/emulator"
+	"github.com/richardwooding/nostalgiza/internal/gbs"
+	"github.com/richardwooding/nostalgiza/internal/memory"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+	"github.com/richardwooding/nostalgiza/internal/romdb"
 	"github.com/richardwooding/nostalgiza/internal/testrom"
 )
 
@@ -23,18 +37,60 @@ var (
 
 	// ErrInvalidScale indicates the scale factor is out of valid range.
 	ErrInvalidScale = errors.New("scale must be between 1 and 10")
+
+	// ErrInvalidWindowHeight indicates the target window height is negative.
+	ErrInvalidWindowHeight = errors.New("window height must not be negative")
+
+	// ErrInvalidFPS indicates the target frame rate is out of valid range.
+	ErrInvalidFPS = errors.New("fps must be between 1 and 240")
+
+	// ErrInvalidAudioBuffer indicates the audio buffer length is out of valid range.
+	ErrInvalidAudioBuffer = errors.New("audio buffer must be between 10 and 1000 ms")
+
+	// ErrInvalidFrameSkip indicates the frame skip is out of valid range.
+	ErrInvalidFrameSkip = errors.New("frame skip must be between 0 and 9")
+
+	// ErrInvalidRecordEvery indicates the GIF frame capture interval is out of valid range.
+	ErrInvalidRecordEvery = errors.New("record every must be between 1 and 10")
+
+	// ErrInvalidStartPC indicates the start address is not a 16-bit address.
+	ErrInvalidStartPC = errors.New("start PC must be an address between 0x0000 and 0xFFFF")
+
+	// ErrInvalidSeconds indicates the benchmark duration is not positive.
+	ErrInvalidSeconds = errors.New("seconds must be positive")
+
+	// ErrNoTestROMs indicates a directory contains no test ROMs to verify.
+	ErrNoTestROMs = errors.New("no .gb files found")
+
+	// ErrInvalidFrames indicates the number of frames to run is not positive.
+	ErrInvalidFrames = errors.New("frames must be positive")
+
+	// ErrNoDumpFiles indicates dump-mem was given nothing to write.
+	ErrNoDumpFiles = errors.New("at least one of --vram or --sram is required")
 )
 
+// defaultConfigPath is the JSON config file read for flag defaults, if present.
+const defaultConfigPath = "~/.config/nostalgiza/config.json"
+
 // CLI represents the command-line interface structure.
 type CLI struct {
-	Info InfoCmd `cmd:"" help:"Display cartridge information."`
-	Run  RunCmd  `cmd:"" help:"Run a Game Boy ROM."`
-	Test TestCmd `cmd:"" help:"Run a test ROM and report results."`
+	Config   kong.ConfigFlag `name:"config" help:"Read flag defaults from this JSON file instead of ~/.config/nostalgiza/config.json." type:"path"`
+	LogLevel string          `name:"log-level" enum:"debug,info,warn,error" default:"warn" help:"Minimum level of log messages written to stderr: debug, info, warn or error."`
+
+	Info    InfoCmd    `cmd:"" help:"Display cartridge information."`
+	Run     RunCmd     `cmd:"" help:"Run a Game Boy ROM."`
+	Test    TestCmd    `cmd:"" help:"Run a test ROM and report results."`
+	Verify  VerifyCmd  `cmd:"" help:"Run every test ROM in a directory and report a summary."`
+	Debug   DebugCmd   `cmd:"" help:"Debug a ROM in an interactive command-line debugger."`
+	Bench   BenchCmd   `cmd:"" help:"Measure headless emulation speed."`
+	DumpMem DumpMemCmd `cmd:"" name:"dump-mem" help:"Run a ROM headlessly, then write VRAM and cartridge RAM to files."`
+	GBS     GBSCmd     `cmd:"" name:"gbs" help:"Play a GBS music file."`
 }
 
 // InfoCmd displays cartridge header information.
 type InfoCmd struct {
-	ROM string `arg:"" type:"existingfile" help:"Path to ROM file."`
+	ROM    string `arg:"" type:"existingfile" help:"Path to ROM file (.gb or .gbc)."`
+	Lookup bool   `help:"Look up the canonical game name in the ROM database even if the header has a title."`
 }
 
 // Run executes the info command.
@@ -55,6 +111,11 @@ func (c *InfoCmd) Run() error {
 	header := cart.Header()
 	fmt.Printf("ROM Information:\n")
 	fmt.Printf("  Title:          %s\n", header.GetTitle())
+	if header.GetTitle() == "" || c.Lookup {
+		if name, ok := lookupGame(header, len(data)); ok {
+			fmt.Printf("  Known As:       %s\n", name)
+		}
+	}
 	fmt.Printf("  Cartridge Type: %s (0x%02X)\n", cartridge.CartridgeType(header.CartridgeType), header.CartridgeType)
 	fmt.Printf("  ROM Size:       %d KiB (%d banks)\n", header.GetROMSizeBytes()/1024, header.GetROMBanks())
 	fmt.Printf("  RAM Size:       %d KiB (%d banks)\n", header.GetRAMSizeBytes()/1024, header.GetRAMBanks())
@@ -67,21 +128,129 @@ func (c *InfoCmd) Run() error {
 
 // RunCmd runs a Game Boy ROM.
 type RunCmd struct {
-	ROM   string `arg:"" type:"existingfile" help:"Path to ROM file."`
-	Scale int    `help:"Display scale factor (1-10)." default:"3"`
+	ROM             string  `arg:"" type:"existingfile" help:"Path to ROM file (.gb or .gbc)."`
+	Scale           *int    `help:"Display scale factor (1-10). Defaults to 3, or the scale nearest --window-height."`
+	WindowHeight    int     `name:"window-height" help:"Pick the integer scale whose window height is closest to this many logical pixels (--scale overrides it)."`
+	Fullscreen      bool    `help:"Start in fullscreen mode (toggle with F11)."`
+	FPS             float64 `name:"fps" default:"59.7275" help:"Emulated frames per second (the Game Boy runs at 59.7275)."`
+	VSync           bool    `name:"vsync" default:"true" negatable:"" help:"Synchronize drawing with the display refresh."`
+	FrameSkip       int     `name:"frame-skip" default:"0" help:"Draw only every (N+1)th frame to save time on slow machines (0-9); emulation and audio run at full speed."`
+	ScaleMode       string  `name:"scale-mode" enum:"stretch,integer,fit" default:"fit" help:"How the screen scales to the window: stretch, integer or fit (aspect-preserving)."`
+	ColorCorrection string  `name:"color-correction" enum:"none,lcd,green" default:"none" help:"Palette color correction: none, lcd (washed-out DMG LCD) or green (lcd tinted green-gray)."`
+	LCDOffBlank     bool    `name:"lcd-off-blank" default:"true" negatable:"" help:"Show a blank white screen while the game turns the LCD off, instead of the last frame."`
+	PauseOnUnfocus  bool    `name:"pause-on-unfocus" default:"true" negatable:"" help:"Pause emulation and audio while the window does not have focus."`
 
 	// Audio filter flags for debugging audio quality issues
-	NoLowPass  bool `help:"Disable low-pass filter (anti-aliasing)."`
-	NoHighPass bool `help:"Disable high-pass filter (DC offset removal)."`
-	NoSoftClip bool `help:"Disable soft clipping (use hard clipping instead)."`
-	NoDither   bool `help:"Disable triangular dithering."`
+	NoLowPass     bool   `help:"Disable low-pass filter (anti-aliasing)."`
+	NoHighPass    bool   `help:"Disable high-pass filter (DC offset removal)."`
+	NoSoftClip    bool   `help:"Disable soft clipping (use hard clipping instead)."`
+	NoDither      bool   `help:"Disable triangular dithering."`
+	AudioSeed     uint64 `name:"audio-seed" help:"Seed for the audio dither RNG, for reproducible output (0 = time-based)."`
+	Mono          bool   `help:"Downmix audio to mono (average of left and right)."`
+	NoAudio       bool   `name:"no-audio" help:"Disable audio output (the APU still runs)."`
+	NoAPU         bool   `name:"no-apu" help:"Disable audio output and skip APU emulation for speed."`
+	AudioBufferMS int    `name:"audio-buffer-ms" default:"100" help:"Internal audio buffer length in milliseconds (10-1000); larger is more latency but fewer underruns."`
+
+	// Cartridge flags
+	MBC1M          bool   `name:"mbc1m" help:"Force MBC1 multicart (MBC1M) bank wiring."`
+	Mode           string `enum:"auto,dmg,cgb" default:"auto" help:"Hardware to emulate: auto (Game Boy Color for CGB-only ROMs, DMG otherwise), dmg or cgb."`
+	ForceDMG       bool   `name:"force-dmg" help:"Run Game Boy Color-only ROMs in DMG mode anyway (same as --mode=dmg)."`
+	ForceMBC       bool   `name:"force-mbc" help:"Load unsupported cartridge types as the closest supported controller (ROM only or MBC1)."`
+	IgnoreChecksum bool   `name:"ignore-checksum" help:"Load ROMs with an invalid header checksum (common in homebrew and hacks) with a warning."`
+	PadROM         bool   `name:"pad-rom" help:"Load ROM dumps whose size does not match the header, padding undersized ones with 0xFF and truncating oversized ones."`
+
+	// Enhancement flags (diverge from hardware behavior)
+	NoSpriteLimit bool `help:"Draw all sprites on a scanline instead of the hardware limit of 10."`
+
+	// Accuracy flags
+	FIFO             bool `name:"fifo" help:"Use the pixel FIFO renderer (slower, accurate mid-scanline timing)."`
+	NoBootROMSkip    bool `name:"no-bootrom-skip" help:"Start with WRAM, VRAM and OAM holding a non-zero power-on pattern, as on hardware without a boot ROM, instead of zeros."`
+	WaveRAMConflicts bool `name:"wave-ram-conflicts" help:"While channel 3 plays, restrict wave RAM access to the byte being played, as on DMG (can break games that rewrite wave RAM during playback)."`
+
+	// Recording flags
+	RecordGIF     string `name:"record-gif" type:"path" help:"Record gameplay to this animated GIF file (written when the recording ends or the window closes)."`
+	RecordSeconds int    `name:"record-seconds" default:"10" help:"Length of the GIF recording in seconds."`
+	RecordEvery   int    `name:"record-every" default:"2" help:"Capture every Nth emulated frame into the GIF (1-10); higher values make smaller files."`
+
+	// Debugging flags
+	Trace   string `help:"Write an instruction trace to this file." type:"path"`
+	Doctor  string `help:"Write a Gameboy Doctor compatible log to this file." type:"path"`
+	Render  string `enum:"all,bg-only,no-window,no-sprites" default:"all" help:"Layers to draw: all, bg-only, no-window or no-sprites (scanline renderer only)."`
+	StartPC string `name:"start-pc" help:"Start executing at this hex address (e.g. 0x0200 or $0200) instead of the 0x0100 entry point."`
+}
+
+// resolveMode reports whether to emulate Game Boy Color hardware for a --mode
+// value and a ROM's CGB flag. In auto mode only CGB-only ROMs (flag 0xC0) get
+// CGB hardware: CGB support is incomplete, and CGB-enhanced ROMs (flag 0x80)
+// also run on a DMG.
+func resolveMode(mode string, cgbFlag byte) bool {
+	switch mode {
+	case "cgb":
+		return true
+	case "dmg":
+		return false
+	default:
+		return cgbFlag == 0xC0
+	}
+}
+
+// romCGBFlag returns the CGB flag (header byte 0x0143) of a ROM image, or 0
+// if the image is too short to have one.
+func romCGBFlag(data []byte) byte {
+	if len(data) <= 0x0143 {
+		return 0
+	}
+	return data[0x0143]
+}
+
+// layerMask returns which of the background, window and sprite layers a
+// --render value draws.
+func layerMask(render string) (bg, window, sprites bool) {
+	switch render {
+	case "bg-only":
+		return true, false, false
+	case "no-window":
+		return true, false, true
+	case "no-sprites":
+		return true, true, false
+	default:
+		return true, true, true
+	}
 }
 
 // Run executes the run command.
 func (c *RunCmd) Run() error {
-	// Validate scale factor
-	if c.Scale < 1 || c.Scale > 10 {
-		return fmt.Errorf("%w: got %d", ErrInvalidScale, c.Scale)
+	// Validate scale factor; nil means unset
+	if c.Scale != nil && (*c.Scale < minScale || *c.Scale > maxScale) {
+		return fmt.Errorf("%w: got %d", ErrInvalidScale, *c.Scale)
+	}
+	if c.WindowHeight < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidWindowHeight, c.WindowHeight)
+	}
+	if c.FPS < minFPS || c.FPS > maxFPS {
+		return fmt.Errorf("%w: got %g", ErrInvalidFPS, c.FPS)
+	}
+	if c.AudioBufferMS < minAudioBufferMS || c.AudioBufferMS > maxAudioBufferMS {
+		return fmt.Errorf("%w: got %d", ErrInvalidAudioBuffer, c.AudioBufferMS)
+	}
+	if c.FrameSkip < 0 || c.FrameSkip > maxFrameSkip {
+		return fmt.Errorf("%w: got %d", ErrInvalidFrameSkip, c.FrameSkip)
+	}
+	if c.RecordGIF != "" {
+		if c.RecordSeconds <= 0 {
+			return fmt.Errorf("record %w: got %d", ErrInvalidSeconds, c.RecordSeconds)
+		}
+		if c.RecordEvery < 1 || c.RecordEvery > maxRecordEvery {
+			return fmt.Errorf("%w: got %d", ErrInvalidRecordEvery, c.RecordEvery)
+		}
+	}
+	var startPC uint16
+	if c.StartPC != "" {
+		pc, err := parseAddress(c.StartPC)
+		if err != nil {
+			return err
+		}
+		startPC = pc
 	}
 
 	// Read ROM file
@@ -90,32 +259,127 @@ func (c *RunCmd) Run() error {
 		return fmt.Errorf("failed to read ROM: %w", err)
 	}
 
+	// Pick DMG or CGB hardware from the mode and the ROM's CGB flag
+	mode := c.Mode
+	if c.ForceDMG {
+		mode = "dmg"
+	}
+	cgb := resolveMode(mode, romCGBFlag(data))
+
 	// Create emulator instance
-	emu, err := emulator.New(data)
+	emu, err := emulator.NewWithOptions(data, emulator.Options{
+		Cartridge:  cartridge.Options{MBC1M: c.MBC1M, AllowFallback: c.ForceMBC, IgnoreHeaderChecksum: c.IgnoreChecksum, PadROM: c.PadROM},
+		CGB:        cgb,
+		ForceDMG:   !cgb,
+		PowerOnRAM: c.NoBootROMSkip,
+	})
+	if errors.Is(err, cartridge.ErrInvalidCartridgeType) {
+		return fmt.Errorf("%w; use --force-mbc to load it as the closest supported controller", err)
+	}
+	if errors.Is(err, cartridge.ErrInvalidHeaderChecksum) {
+		return fmt.Errorf("%w; use --ignore-checksum to load it anyway", err)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create emulator: %w", err)
 	}
+	if cartType := cartridge.CartridgeType(emu.Cart.Header().CartridgeType); !cartType.Supported() {
+		slog.Warn("unsupported cartridge type; it may not run correctly",
+			"type", cartType, "loaded_as", cartridge.FallbackType(cartType, len(data)))
+	}
+	switch {
+	case cgb:
+		slog.Warn("Game Boy Color support is incomplete; this ROM may not run correctly")
+	case emu.Cart.Header().IsCGBOnly():
+		slog.Warn("this ROM requires a Game Boy Color and may not run correctly in DMG mode")
+	}
+
+	if c.NoSpriteLimit {
+		emu.PPU.SetSpriteLimit(0)
+	}
+	emu.PPU.SetFIFORenderer(c.FIFO)
+	emu.PPU.SetLayerMask(layerMask(c.Render))
+	emu.APU.SetMono(c.Mono)
+	emu.APU.SetWaveRAMConflicts(c.WaveRAMConflicts)
+	emu.SetAPUEnabled(!c.NoAPU)
+	if c.StartPC != "" {
+		emu.SetPC(startPC)
+	}
+
+	// Enable instruction tracing if requested
+	if c.Trace != "" {
+		traceWriter, closeTrace, err := createLogFile(c.Trace)
+		if err != nil {
+			return err
+		}
+		defer closeTrace()
+		emu.CPU.SetTracer(traceWriter)
+	}
+
+	// Enable Gameboy Doctor logging if requested
+	if c.Doctor != "" {
+		doctorWriter, closeDoctor, err := createLogFile(c.Doctor)
+		if err != nil {
+			return err
+		}
+		defer closeDoctor()
+		emu.SetDoctorLog(doctorWriter)
+	}
+
+	// Log serviced interrupts at debug level (not installed otherwise, to keep it free)
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		emu.CPU.OnInterrupt = logInterrupt
+	}
+
+	// Record a GIF of the session if requested
+	var recorder *gifRecorder
+	if c.RecordGIF != "" {
+		palette := correctedPalette(dmgPalette, colorCorrection(c.ColorCorrection))
+		recorder = newGIFRecorder(c.RecordGIF, c.RecordSeconds, c.RecordEvery, c.FPS, palette)
+	}
 
-	// Create display with audio filter options
-	display := NewDisplay(emu, AudioOptions{
-		EnableLowPass:  !c.NoLowPass,
-		EnableHighPass: !c.NoHighPass,
-		EnableSoftClip: !c.NoSoftClip,
-		EnableDither:   !c.NoDither,
+	// Create display with audio filter, scaling and frame rate options
+	display := NewDisplay(emu, DisplayOptions{
+		Audio: AudioOptions{
+			EnableLowPass:  !c.NoLowPass,
+			EnableHighPass: !c.NoHighPass,
+			EnableSoftClip: !c.NoSoftClip,
+			EnableDither:   !c.NoDither,
+			DitherSeed:     c.AudioSeed,
+			BufferMS:       c.AudioBufferMS,
+		},
+		ScaleMode:       scaleMode(c.ScaleMode),
+		ColorCorrection: colorCorrection(c.ColorCorrection),
+		FPS:             c.FPS,
+		FrameSkip:       c.FrameSkip,
+		LCDOffBlank:     c.LCDOffBlank,
+		PauseOnUnfocus:  c.PauseOnUnfocus,
+		ROMPath:         c.ROM,
+		NoAudio:         c.NoAudio || c.NoAPU,
+		Recorder:        recorder,
 	})
 
 	// Configure Ebiten window
-	ebiten.SetWindowTitle("NostalgiZA - Game Boy Emulator")
-	ebiten.SetWindowSize(160*c.Scale, 144*c.Scale)
+	ebiten.SetWindowTitle(windowTitle(emu.Cart.Header(), len(data), c.ROM))
+	scale := windowScale(c.Scale, c.WindowHeight)
+	ebiten.SetWindowSize(ppu.ScreenWidth*scale, ppu.ScreenHeight*scale)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
-	ebiten.SetTPS(60) // Set to 60 ticks per second (matching Game Boy ~59.73 Hz)
+	ebiten.SetFullscreen(c.Fullscreen)
+	ebiten.SetTPS(tickRate(c.FPS)) // Nearest whole rate; the display budgets cycles per tick
+	ebiten.SetVsyncEnabled(c.VSync)
+	ebiten.SetScreenClearedEveryFrame(c.FrameSkip == 0) // Skipped frames keep the last screen
 
 	// Run the emulator
+	var runErr error
 	if err := ebiten.RunGame(display); err != nil {
-		return fmt.Errorf("emulator error: %w", err)
+		runErr = fmt.Errorf("emulator error: %w", err)
 	}
 
-	return nil
+	// Save a recording cut short by closing the window or by an error
+	if recorder != nil {
+		runErr = errors.Join(runErr, recorder.finish())
+	}
+
+	return runErr
 }
 
 // TestCmd runs a test ROM and reports results.
@@ -123,18 +387,51 @@ type TestCmd struct {
 	ROM     string `arg:"" type:"existingfile" help:"Path to test ROM file."`
 	Timeout int    `default:"30" help:"Timeout in seconds."`
 	Verbose bool   `short:"v" help:"Show detailed output."`
+	JSON    bool   `name:"json" help:"Print the result as JSON."`
+	Doctor  string `help:"Write a Gameboy Doctor compatible log to this file." type:"path"`
+
+	// Completion detection flags
+	Marker     []string `help:"Serial output substring that ends the run and counts as a pass (repeatable)."`
+	StableMS   int      `name:"stable-ms" default:"3000" help:"Milliseconds of unchanged serial output before the run ends."`
+	StopOnHalt bool     `help:"End the run when the CPU halts."`
+
+	// Accuracy flags
+	FIFO bool `name:"fifo" help:"Use the pixel FIFO renderer (slower, accurate mid-scanline timing)."`
 }
 
 // Run executes the test command.
 func (c *TestCmd) Run() error {
-	fmt.Printf("Running test ROM: %s\n", c.ROM)
+	if !c.JSON {
+		fmt.Printf("Running test ROM: %s\n", c.ROM)
+	}
 
 	// Run the test ROM
 	timeout := time.Duration(c.Timeout) * time.Second
-	result := testrom.Run(c.ROM, timeout)
+	opts := testrom.Options{
+		Markers:        c.Marker,
+		StableDuration: time.Duration(c.StableMS) * time.Millisecond,
+		StopOnHalt:     c.StopOnHalt,
+		FIFORenderer:   c.FIFO,
+	}
+	if c.Doctor != "" {
+		doctorWriter, closeDoctor, err := createLogFile(c.Doctor)
+		if err != nil {
+			return err
+		}
+		defer closeDoctor()
+		opts.DoctorLog = doctorWriter
+	}
+	result := testrom.RunWithOptions(c.ROM, timeout, opts)
+
+	if c.JSON {
+		return writeJSONResult(os.Stdout, c.ROM, result)
+	}
 
 	// Display results
 	fmt.Printf("Result: %s\n", result.String())
+	if failed := result.FailedSubtests(); len(failed) > 0 {
+		fmt.Printf("Failed subtests: %s\n", strings.Join(failed, ", "))
+	}
 
 	if c.Verbose || !result.IsSuccess() {
 		fmt.Printf("\nOutput:\n%s\n", result.Output)
@@ -147,17 +444,430 @@ func (c *TestCmd) Run() error {
 	return nil
 }
 
-func main() {
-	cli := &CLI{}
-	ctx := kong.Parse(cli,
+// VerifyCmd runs every test ROM in a directory tree and reports a summary.
+type VerifyCmd struct {
+	Dir     string `arg:"" type:"existingdir" help:"Directory to search for .gb test ROMs (recursively)."`
+	Timeout int    `default:"30" help:"Timeout per ROM in seconds."`
+	JSON    bool   `name:"json" help:"Print the results as JSON."`
+	FIFO    bool   `name:"fifo" help:"Use the pixel FIFO renderer (slower, accurate mid-scanline timing)."`
+}
+
+// verifySummary is the JSON output of the verify command.
+type verifySummary struct {
+	Passed  int              `json:"passed"`
+	Failed  int              `json:"failed"`
+	Results []testrom.Report `json:"results"`
+}
+
+// runTestROM runs a single test ROM; tests replace it.
+var runTestROM = testrom.RunWithOptions
+
+// Run executes the verify command.
+func (c *VerifyCmd) Run() error {
+	return c.verify(os.Stdout)
+}
+
+// verify runs the test ROMs under c.Dir and writes per-ROM results and a
+// summary to w. It returns ErrTestFailed if any ROM did not pass.
+func (c *VerifyCmd) verify(w io.Writer) error {
+	roms, err := findTestROMs(c.Dir)
+	if err != nil {
+		return err
+	}
+	if len(roms) == 0 {
+		return fmt.Errorf("%w in %s", ErrNoTestROMs, c.Dir)
+	}
+
+	timeout := time.Duration(c.Timeout) * time.Second
+	opts := testrom.Options{FIFORenderer: c.FIFO}
+
+	summary := verifySummary{Results: make([]testrom.Report, 0, len(roms))}
+	for _, rom := range roms {
+		result := runTestROM(rom, timeout, opts)
+		if result.IsSuccess() {
+			summary.Passed++
+		} else {
+			summary.Failed++
+		}
+		summary.Results = append(summary.Results, result.Report(rom))
+
+		if !c.JSON {
+			fmt.Fprintf(w, "%-8s %s\n", result.String(), rom)
+		}
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(summary); err != nil {
+			return fmt.Errorf("failed to write JSON results: %w", err)
+		}
+	} else {
+		fmt.Fprintf(w, "\n%d passed / %d failed\n", summary.Passed, summary.Failed)
+	}
+
+	if summary.Failed > 0 {
+		return fmt.Errorf("%w: %d of %d ROMs", ErrTestFailed, summary.Failed, len(roms))
+	}
+
+	return nil
+}
+
+// findTestROMs returns the paths of all .gb files under dir, in lexical order.
+// Other files are skipped.
+func findTestROMs(dir string) ([]string, error) {
+	var roms []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".gb") {
+			roms = append(roms, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for test ROMs: %w", err)
+	}
+	return roms, nil
+}
+
+// DebugCmd runs a ROM under the interactive debugger.
+type DebugCmd struct {
+	ROM   string `arg:"" type:"existingfile" help:"Path to ROM file."`
+	MBC1M bool   `name:"mbc1m" help:"Force MBC1 multicart (MBC1M) bank wiring."`
+}
+
+// Run executes the debug command.
+func (c *DebugCmd) Run() error {
+	// Read ROM file
+	data, err := os.ReadFile(c.ROM)
+	if err != nil {
+		return fmt.Errorf("failed to read ROM: %w", err)
+	}
+
+	// Debugging tools run CGB-only ROMs in DMG mode rather than refusing them
+	emu, err := emulator.NewWithOptions(data, emulator.Options{
+		Cartridge: cartridge.Options{MBC1M: c.MBC1M},
+		ForceDMG:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create emulator: %w", err)
+	}
+
+	fmt.Println("NostalgiZA debugger - type 'h' for help")
+	return debugger.New(emu, os.Stdout).Run(os.Stdin)
+}
+
+// BenchCmd runs a ROM headlessly as fast as possible and reports throughput.
+type BenchCmd struct {
+	ROM      string `arg:"" type:"existingfile" help:"Path to ROM file."`
+	Seconds  int    `default:"5" help:"How long to run, in seconds."`
+	FIFO     bool   `name:"fifo" help:"Use the pixel FIFO renderer."`
+	MemStats bool   `name:"mem-stats" help:"Count CPU memory accesses per region and report them (slows emulation slightly)."`
+}
+
+// Run executes the bench command.
+func (c *BenchCmd) Run() error {
+	if c.Seconds <= 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidSeconds, c.Seconds)
+	}
+
+	// Read ROM file
+	data, err := os.ReadFile(c.ROM)
+	if err != nil {
+		return fmt.Errorf("failed to read ROM: %w", err)
+	}
+
+	emu, err := emulator.NewWithOptions(data, emulator.Options{ForceDMG: true})
+	if err != nil {
+		return fmt.Errorf("failed to create emulator: %w", err)
+	}
+	emu.PPU.SetFIFORenderer(c.FIFO)
+	emu.Memory.SetAccessStats(c.MemStats)
+
+	fmt.Printf("Benchmarking %s for %ds...\n", c.ROM, c.Seconds)
+	result := emu.RunFor(time.Duration(c.Seconds) * time.Second)
+
+	fmt.Printf("Emulated %d cycles (%d frames) in %.2fs\n", result.Cycles, result.Frames, result.Elapsed.Seconds())
+	fmt.Printf("  Cycles/sec: %.0f\n", result.CyclesPerSecond())
+	fmt.Printf("  Frames/sec: %.1f\n", result.FramesPerSecond())
+	fmt.Printf("  Speed:      %.2fx real time\n", result.RealTimeRatio())
+
+	if stats := emu.Memory.AccessStats(); stats != nil {
+		fmt.Println("Memory accesses:")
+		for _, region := range memory.Regions {
+			fmt.Printf("  %-7s %12d reads %12d writes\n", region+":", stats[region].Reads, stats[region].Writes)
+		}
+	}
+
+	return nil
+}
+
+// DumpMemCmd runs a ROM headlessly and dumps raw memory for inspection.
+type DumpMemCmd struct {
+	ROM    string `arg:"" type:"existingfile" help:"Path to ROM file."`
+	Frames int    `default:"60" help:"Number of frames to run before dumping."`
+	VRAM   string `name:"vram" type:"path" help:"Write raw VRAM to this file."`
+	SRAM   string `name:"sram" type:"path" help:"Write raw cartridge RAM to this file."`
+}
+
+// Run executes the dump-mem command.
+func (c *DumpMemCmd) Run() error {
+	return c.dump(os.Stdout)
+}
+
+// dump runs the ROM for c.Frames frames and writes the requested memory
+// dumps, reporting each file to w.
+func (c *DumpMemCmd) dump(w io.Writer) error {
+	if c.Frames <= 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidFrames, c.Frames)
+	}
+	if c.VRAM == "" && c.SRAM == "" {
+		return ErrNoDumpFiles
+	}
+
+	// Read ROM file
+	data, err := os.ReadFile(c.ROM)
+	if err != nil {
+		return fmt.Errorf("failed to read ROM: %w", err)
+	}
+
+	emu, err := emulator.NewWithOptions(data, emulator.Options{ForceDMG: true})
+	if err != nil {
+		return fmt.Errorf("failed to create emulator: %w", err)
+	}
+	for range c.Frames {
+		emu.RunFrame()
+	}
+
+	if c.VRAM != "" {
+		vram := emu.PPU.VRAMSnapshot()
+		if err := os.WriteFile(c.VRAM, vram, 0o600); err != nil {
+			return fmt.Errorf("failed to write VRAM: %w", err)
+		}
+		fmt.Fprintf(w, "Wrote %d bytes of VRAM to %s\n", len(vram), c.VRAM)
+	}
+
+	if c.SRAM != "" {
+		sram := emu.Cart.GetRAM()
+		if len(sram) == 0 {
+			fmt.Fprintln(w, "Cartridge has no RAM, skipping --sram")
+			return nil
+		}
+		if err := os.WriteFile(c.SRAM, sram, 0o600); err != nil {
+			return fmt.Errorf("failed to write cartridge RAM: %w", err)
+		}
+		fmt.Fprintf(w, "Wrote %d bytes of cartridge RAM to %s\n", len(sram), c.SRAM)
+	}
+
+	return nil
+}
+
+// GBSCmd plays a GBS music rip.
+type GBSCmd struct {
+	File          string `arg:"" type:"existingfile" help:"Path to GBS file (.gbs)."`
+	Track         int    `help:"Track to play, from 1 (default: the file's first track)."`
+	Seconds       int    `default:"0" help:"Stop after this many seconds (0 = until interrupted)."`
+	AudioBufferMS int    `name:"audio-buffer-ms" default:"100" help:"Audio buffer length in milliseconds (10-1000); larger values add latency but reduce crackling."`
+}
+
+// gbsTickRate is how many times per second the gbs command runs the player
+// and feeds the audio output.
+const gbsTickRate = 60
+
+// Run executes the gbs command.
+func (c *GBSCmd) Run() error {
+	if c.Seconds < 0 {
+		return fmt.Errorf("%w: got %d", ErrInvalidSeconds, c.Seconds)
+	}
+	if c.AudioBufferMS < minAudioBufferMS || c.AudioBufferMS > maxAudioBufferMS {
+		return fmt.Errorf("%w: got %d", ErrInvalidAudioBuffer, c.AudioBufferMS)
+	}
+
+	data, err := os.ReadFile(c.File)
+	if err != nil {
+		return fmt.Errorf("failed to read GBS file: %w", err)
+	}
+	file, err := gbs.Load(data)
+	if err != nil {
+		return fmt.Errorf("failed to load GBS file: %w", err)
+	}
+	player, err := gbs.NewPlayer(file)
+	if err != nil {
+		return err
+	}
+
+	track := c.Track
+	if track == 0 {
+		track = max(1, int(file.Header.FirstSong))
+	}
+	if err := player.Start(track); err != nil {
+		return err
+	}
+
+	audioPlayer, err := newAudioPlayer(player.Emulator().APU, AudioOptions{
+		EnableLowPass:  true,
+		EnableHighPass: true,
+		EnableSoftClip: true,
+		EnableDither:   true,
+		BufferMS:       c.AudioBufferMS,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start audio: %w", err)
+	}
+	audioPlayer.Start()
+	defer audioPlayer.Stop()
+
+	fmt.Printf("Playing track %d/%d: %s - %s\n", track, file.Header.Songs, file.Header.Title, file.Header.Author)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if c.Seconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(c.Seconds)*time.Second)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(time.Second / gbsTickRate)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			player.Run(emulator.ClockSpeed / gbsTickRate)
+			audioPlayer.Update()
+		}
+	}
+}
+
+// lookupGame looks up a ROM's canonical name in the ROM database by its
+// global checksum and size.
+func lookupGame(header *cartridge.Header, size int) (string, bool) {
+	checksum := uint16(header.GlobalChecksum[0])<<8 | uint16(header.GlobalChecksum[1])
+	return romdb.Lookup(checksum, size)
+}
+
+// windowTitle returns the window title for a ROM. It uses the header title,
+// falling back to the ROM database and then the ROM file name when the
+// title is blank.
+func windowTitle(header *cartridge.Header, size int, romPath string) string {
+	const base = "NostalgiZA - Game Boy Emulator"
+
+	title := sanitizeTitle(header.GetTitle())
+	if title == "" {
+		if name, ok := lookupGame(header, size); ok {
+			title = name
+		} else {
+			title = strings.TrimSuffix(filepath.Base(romPath), filepath.Ext(romPath))
+		}
+	}
+	if title == "" || title == "." {
+		return base
+	}
+	return base + " - " + title
+}
+
+// sanitizeTitle drops non-printable characters from a header title, which
+// homebrew and corrupt ROMs often contain, and trims surrounding spaces.
+func sanitizeTitle(title string) string {
+	title = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7E {
+			return -1
+		}
+		return r
+	}, title)
+	return strings.TrimSpace(title)
+}
+
+// writeJSONResult writes a test result as JSON to w.
+// It returns ErrTestFailed if the test did not pass so the exit code reflects the result.
+func writeJSONResult(w io.Writer, romPath string, result *testrom.Result) error {
+	if err := json.NewEncoder(w).Encode(result.Report(romPath)); err != nil {
+		return fmt.Errorf("failed to write JSON result: %w", err)
+	}
+
+	if !result.IsSuccess() {
+		return ErrTestFailed
+	}
+
+	return nil
+}
+
+// createLogFile creates a buffered log file for debug output.
+// The returned function flushes and closes the file.
+func createLogFile(path string) (io.Writer, func(), error) {
+	f, err := os.Create(path) // #nosec G304 - path is provided by the user via CLI argument
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	return w, func() {
+		_ = w.Flush()
+		_ = f.Close()
+	}, nil
+}
+
+// parseAddress parses a --start-pc address in hexadecimal, with an optional
+// "0x" or "$" prefix (0x0200, $0200 or 0200).
+func parseAddress(s string) (uint16, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(s), "0x"), "$")
+	addr, err := strconv.ParseUint(digits, 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w: got %q", ErrInvalidStartPC, s)
+	}
+	return uint16(addr), nil
+}
+
+// logInterrupt logs a serviced interrupt at debug level.
+func logInterrupt(bit uint8, fromPC, toPC uint16) {
+	slog.Debug("interrupt", "bit", bit,
+		"from", fmt.Sprintf("0x%04X", fromPC), "handler", fmt.Sprintf("0x%04X", toPC))
+}
+
+// newLogger returns a text logger writing messages at level (debug, info,
+// warn or error) and above to w.
+func newLogger(w io.Writer, level string) *slog.Logger {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		l = slog.LevelWarn
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: l}))
+}
+
+// newParser creates the command-line parser for cli. Flag defaults are read
+// from the JSON config files in configPaths (missing files are skipped) or the
+// file given with --config, keyed by flag name (e.g. "color_correction").
+// Command-line flags override config file values, which override built-in
+// defaults.
+func newParser(cli *CLI, configPaths ...string) (*kong.Kong, error) {
+	parser, err := kong.New(cli,
 		kong.Name("nostalgiza"),
 		kong.Description("A Game Boy (DMG) emulator written in Go."),
 		kong.UsageOnError(),
+		kong.Configuration(kong.JSON, configPaths...),
 	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return parser, nil
+}
 
-	err := ctx.Run()
+func main() {
+	cli := &CLI{}
+	parser, err := newParser(cli, defaultConfigPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	ctx, err := parser.Parse(os.Args[1:])
+	parser.FatalIfErrorf(err)
+
+	slog.SetDefault(newLogger(os.Stderr, cli.LogLevel))
+
+	if err := ctx.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/richardwooding/nostalgiza/internal/cartridge"
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+	"github.com/richardwooding/nostalgiza/internal/testrom"
+)
+
+func TestWriteJSONResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     *testrom.Result
+		wantResult string
+		wantErr    error
+	}{
+		{
+			name:       "Passed",
+			result:     &testrom.Result{Output: "cpu_instrs\n\nPassed\n", Passed: true, Duration: 1500 * time.Millisecond},
+			wantResult: "PASSED",
+		},
+		{
+			name:       "Failed",
+			result:     &testrom.Result{Output: "Failed #2\n", Failed: true, Duration: 250 * time.Millisecond},
+			wantResult: "FAILED",
+			wantErr:    ErrTestFailed,
+		},
+		{
+			name:       "Timeout",
+			result:     &testrom.Result{Timeout: true, Error: emulator.ErrTimeout, Duration: 30 * time.Second},
+			wantResult: "TIMEOUT",
+			wantErr:    ErrTestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeJSONResult(&buf, "test.gb", tt.result)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("writeJSONResult() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// Validate the exact set of fields
+			var fields map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			for _, key := range []string{"rom", "result", "output", "duration_ms"} {
+				if _, ok := fields[key]; !ok {
+					t.Errorf("JSON missing %q field: %s", key, buf.String())
+				}
+			}
+			if len(fields) != 4 {
+				t.Errorf("JSON has %d fields, want 4: %s", len(fields), buf.String())
+			}
+
+			var report testrom.Report
+			if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+				t.Fatalf("invalid report JSON: %v", err)
+			}
+			if report.ROM != "test.gb" {
+				t.Errorf("rom = %q, want %q", report.ROM, "test.gb")
+			}
+			if report.Result != tt.wantResult {
+				t.Errorf("result = %q, want %q", report.Result, tt.wantResult)
+			}
+			if report.Output != tt.result.Output {
+				t.Errorf("output = %q, want %q", report.Output, tt.result.Output)
+			}
+			if report.DurationMS != tt.result.Duration.Milliseconds() {
+				t.Errorf("duration_ms = %d, want %d", report.DurationMS, tt.result.Duration.Milliseconds())
+			}
+		})
+	}
+}
+
+func TestWindowTitle(t *testing.T) {
+	tetris := &cartridge.Header{GlobalChecksum: [2]byte{0x16, 0xBF}}
+	titled := &cartridge.Header{GlobalChecksum: [2]byte{0x16, 0xBF}}
+	copy(titled.Title[:], "HACK")
+	control := &cartridge.Header{}
+	copy(control.Title[:], "\x01DEMO\x7F\x1B ")
+	blank := &cartridge.Header{}
+	copy(blank.Title[:], "\x01\x02 ")
+
+	tests := []struct {
+		name   string
+		header *cartridge.Header
+		size   int
+		want   string
+	}{
+		{"Header title", titled, 32 * 1024, "NostalgiZA - Game Boy Emulator - HACK"},
+		{"Control characters", control, 32 * 1024, "NostalgiZA - Game Boy Emulator - DEMO"},
+		{"Empty title, known ROM", tetris, 32 * 1024, "NostalgiZA - Game Boy Emulator - Tetris (World)"},
+		{"Empty title, unknown ROM", tetris, 64 * 1024, "NostalgiZA - Game Boy Emulator - game"},
+		{"Blank title, unknown ROM", blank, 32 * 1024, "NostalgiZA - Game Boy Emulator - game"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := windowTitle(tt.header, tt.size, "roms/game.gb"); got != tt.want {
+				t.Errorf("windowTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayerMask(t *testing.T) {
+	tests := []struct {
+		render              string
+		bg, window, sprites bool
+	}{
+		{"all", true, true, true},
+		{"bg-only", true, false, false},
+		{"no-window", true, false, true},
+		{"no-sprites", true, true, false},
+	}
+
+	for _, tt := range tests {
+		bg, window, sprites := layerMask(tt.render)
+		if bg != tt.bg || window != tt.window || sprites != tt.sprites {
+			t.Errorf("layerMask(%q) = %v, %v, %v, want %v, %v, %v",
+				tt.render, bg, window, sprites, tt.bg, tt.window, tt.sprites)
+		}
+	}
+}
+
+func TestLogLevelChecksumWarning(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0134:], "TEST")
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+	rom[0x014E] = 0x12 // Global checksum that does not match
+	rom[0x014F] = 0x34
+
+	defer slog.SetDefault(slog.Default())
+
+	tests := []struct {
+		level    string
+		wantWarn bool
+	}{
+		{"debug", true},
+		{"warn", true},
+		{"error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.SetDefault(newLogger(&buf, tt.level))
+
+			if _, err := cartridge.New(rom); err != nil {
+				t.Fatalf("cartridge.New() error = %v", err)
+			}
+
+			got := strings.Contains(buf.String(), "level=WARN msg=\"invalid global checksum\"")
+			if got != tt.wantWarn {
+				t.Errorf("checksum warning logged = %v, want %v (log %q)", got, tt.wantWarn, buf.String())
+			}
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint16
+		wantErr bool
+	}{
+		{"0x0200", 0x0200, false},
+		{"0x0000", 0x0000, false},
+		{"0xFFFF", 0xFFFF, false},
+		{"0200", 0x0200, false},
+		{"C000", 0xC000, false},
+		{"$FF40", 0xFF40, false},
+		{"256", 0x0256, false},
+		{"0x10000", 0, true},
+		{"-1", 0, true},
+		{"start", 0, true},
+		{"", 0, true},
+		{"0x", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAddress(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidStartPC) {
+				t.Errorf("parseAddress(%q) error = %v, want ErrInvalidStartPC", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAddress(%q) = 0x%04X, %v, want 0x%04X", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pass.gb":          "PASS",
+		"sub/fail.gb":      "FAIL",
+		"sub/deep/pass.GB": "PASS",
+		"README.md":        "not a ROM",
+		"sub/notes.txt":    "not a ROM",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved := runTestROM
+	defer func() { runTestROM = saved }()
+	var ran []string
+	runTestROM = func(romPath string, _ time.Duration, _ testrom.Options) *testrom.Result {
+		ran = append(ran, romPath)
+		data, err := os.ReadFile(romPath) // #nosec G304 - path is a test fixture
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", romPath, err)
+		}
+		if string(data) == "PASS" {
+			return &testrom.Result{Passed: true}
+		}
+		return &testrom.Result{Failed: true}
+	}
+
+	var buf bytes.Buffer
+	err := (&VerifyCmd{Dir: dir}).verify(&buf)
+	if !errors.Is(err, ErrTestFailed) {
+		t.Errorf("verify() error = %v, want ErrTestFailed", err)
+	}
+	if len(ran) != 3 {
+		t.Errorf("ran %d ROMs (%v), want 3", len(ran), ran)
+	}
+	if !strings.Contains(buf.String(), "2 passed / 1 failed") {
+		t.Errorf("output missing summary:\n%s", buf.String())
+	}
+
+	// JSON output carries the same counts
+	buf.Reset()
+	if err := (&VerifyCmd{Dir: dir, JSON: true}).verify(&buf); !errors.Is(err, ErrTestFailed) {
+		t.Errorf("verify() JSON error = %v, want ErrTestFailed", err)
+	}
+	var summary verifySummary
+	if err := json.Unmarshal(buf.Bytes(), &summary); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if summary.Passed != 2 || summary.Failed != 1 || len(summary.Results) != 3 {
+		t.Errorf("summary = %d passed / %d failed / %d results, want 2/1/3",
+			summary.Passed, summary.Failed, len(summary.Results))
+	}
+
+	// A directory of passing ROMs succeeds
+	if err := (&VerifyCmd{Dir: filepath.Join(dir, "sub", "deep")}).verify(&buf); err != nil {
+		t.Errorf("verify() passing dir error = %v, want nil", err)
+	}
+
+	// A directory without ROMs is an error
+	if err := (&VerifyCmd{Dir: t.TempDir()}).verify(&buf); !errors.Is(err, ErrNoTestROMs) {
+		t.Errorf("verify() empty dir error = %v, want ErrNoTestROMs", err)
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	rom := filepath.Join(dir, "game.gb")
+	if err := os.WriteFile(rom, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(config, []byte(`{"scale": 4, "color_correction": "lcd", "no_dither": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.json")
+
+	tests := []struct {
+		name           string
+		configPaths    []string
+		args           []string
+		wantScale      int
+		wantCorrection string
+		wantNoDither   bool
+	}{
+		// A wantScale of 0 means unset; windowScale picks the default
+		{"defaults", nil, []string{"run", rom}, 0, "none", false},
+		{"missing config file", []string{missing}, []string{"run", rom}, 0, "none", false},
+		{"config file", []string{config}, []string{"run", rom}, 4, "lcd", true},
+		{"flag overrides file", []string{config}, []string{"run", rom, "--scale", "5"}, 5, "lcd", true},
+		{"--config flag", []string{missing}, []string{"--config", config, "run", rom}, 4, "lcd", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := &CLI{}
+			parser, err := newParser(cli, tt.configPaths...)
+			if err != nil {
+				t.Fatalf("newParser() error = %v", err)
+			}
+			if _, err := parser.Parse(tt.args); err != nil {
+				t.Fatalf("Parse(%v) error = %v", tt.args, err)
+			}
+
+			scale := 0
+			if cli.Run.Scale != nil {
+				scale = *cli.Run.Scale
+			}
+			if scale != tt.wantScale {
+				t.Errorf("scale = %d, want %d", scale, tt.wantScale)
+			}
+			if cli.Run.ColorCorrection != tt.wantCorrection {
+				t.Errorf("color correction = %q, want %q", cli.Run.ColorCorrection, tt.wantCorrection)
+			}
+			if cli.Run.NoDither != tt.wantNoDither {
+				t.Errorf("no dither = %v, want %v", cli.Run.NoDither, tt.wantNoDither)
+			}
+			if cli.Run.AudioBufferMS != 100 {
+				t.Errorf("audio buffer = %d, want default 100", cli.Run.AudioBufferMS)
+			}
+		})
+	}
+}
+
+func TestRunInvalidScale(t *testing.T) {
+	rom := filepath.Join(t.TempDir(), "test.gb")
+	if err := os.WriteFile(rom, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scale := range []string{"0", "11"} {
+		cli := &CLI{}
+		parser, err := newParser(cli)
+		if err != nil {
+			t.Fatalf("newParser() error = %v", err)
+		}
+		if _, err := parser.Parse([]string{"run", rom, "--scale", scale}); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if err := cli.Run.Run(); !errors.Is(err, ErrInvalidScale) {
+			t.Errorf("Run() with --scale %s error = %v, want ErrInvalidScale", scale, err)
+		}
+	}
+}
+
+func TestConfigInvalidJSON(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(config, []byte(`{"scale": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newParser(&CLI{}, config); err == nil {
+		t.Error("newParser() error = nil, want error for invalid JSON")
+	}
+}
+
+func TestResolveMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		cgbFlag byte
+		want    bool
+	}{
+		{"auto", 0x00, false},
+		{"auto", 0x80, false}, // CGB-enhanced ROMs run on a DMG
+		{"auto", 0xC0, true},
+		{"dmg", 0x00, false},
+		{"dmg", 0x80, false},
+		{"dmg", 0xC0, false},
+		{"cgb", 0x00, true},
+		{"cgb", 0x80, true},
+		{"cgb", 0xC0, true},
+	}
+
+	for _, tt := range tests {
+		if got := resolveMode(tt.mode, tt.cgbFlag); got != tt.want {
+			t.Errorf("resolveMode(%q, 0x%02X) = %v, want %v", tt.mode, tt.cgbFlag, got, tt.want)
+		}
+	}
+}
+
+func TestROMCGBFlag(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	rom[0x0143] = 0xC0
+	if got := romCGBFlag(rom); got != 0xC0 {
+		t.Errorf("romCGBFlag() = 0x%02X, want 0xC0", got)
+	}
+	if got := romCGBFlag(rom[:0x0143]); got != 0x00 {
+		t.Errorf("romCGBFlag() of a truncated ROM = 0x%02X, want 0x00", got)
+	}
+}
+
+func TestDumpMem(t *testing.T) {
+	// An MBC1+RAM+BATTERY cartridge that writes 0x5A to cartridge RAM
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0134:], "TEST")
+	rom[0x0147] = 0x03 // MBC1+RAM+BATTERY
+	rom[0x0149] = 0x02 // 8 KiB RAM
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+	copy(rom[0x0100:], []byte{
+		0x3E, 0x0A, // LD A,$0A
+		0xEA, 0x00, 0x00, // LD ($0000),A (enable RAM)
+		0x3E, 0x5A, // LD A,$5A
+		0xEA, 0x00, 0xA0, // LD ($A000),A
+		0x18, 0xFE, // JR -2
+	})
+
+	dir := t.TempDir()
+	romPath := filepath.Join(dir, "ram.gb")
+	if err := os.WriteFile(romPath, rom, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &DumpMemCmd{
+		ROM:    romPath,
+		Frames: 1,
+		VRAM:   filepath.Join(dir, "vram.bin"),
+		SRAM:   filepath.Join(dir, "out.sav"),
+	}
+	var buf bytes.Buffer
+	if err := cmd.dump(&buf); err != nil {
+		t.Fatalf("dump() error = %v", err)
+	}
+
+	vram, err := os.ReadFile(cmd.VRAM)
+	if err != nil {
+		t.Fatalf("ReadFile(vram) error = %v", err)
+	}
+	if len(vram) != 0x2000 {
+		t.Errorf("VRAM dump is %d bytes, want 8192", len(vram))
+	}
+	sram, err := os.ReadFile(cmd.SRAM)
+	if err != nil {
+		t.Fatalf("ReadFile(sram) error = %v", err)
+	}
+	if len(sram) != 0x2000 || sram[0] != 0x5A {
+		t.Errorf("SRAM dump is %d bytes starting 0x%02X, want 8192 starting 0x5A", len(sram), sram[0])
+	}
+
+	// A cartridge without RAM skips the SRAM dump
+	rom[0x0147] = 0x00
+	rom[0x0149] = 0x00
+	checksum = 0
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+	if err := os.WriteFile(romPath, rom, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd.SRAM = filepath.Join(dir, "none.sav")
+	buf.Reset()
+	if err := cmd.dump(&buf); err != nil {
+		t.Fatalf("dump() without RAM error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "no RAM") {
+		t.Errorf("output missing notice:\n%s", buf.String())
+	}
+	if _, err := os.Stat(cmd.SRAM); !os.IsNotExist(err) {
+		t.Errorf("Stat(sram) error = %v, want not exist", err)
+	}
+
+	// Invalid arguments
+	if err := (&DumpMemCmd{ROM: romPath, Frames: 0, VRAM: cmd.VRAM}).dump(&buf); !errors.Is(err, ErrInvalidFrames) {
+		t.Errorf("dump() with 0 frames error = %v, want ErrInvalidFrames", err)
+	}
+	if err := (&DumpMemCmd{ROM: romPath, Frames: 1}).dump(&buf); !errors.Is(err, ErrNoDumpFiles) {
+		t.Errorf("dump() without outputs error = %v, want ErrNoDumpFiles", err)
+	}
+}
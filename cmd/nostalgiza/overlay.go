@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/richardwooding/nostalgiza/internal/apu"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+// overlayKey toggles the debug overlay.
+const overlayKey = ebiten.KeyF1
+
+// overlayBoxColor is the outline color of sprite boxes in the debug overlay.
+var overlayBoxColor = color.RGBA{0xFF, 0x00, 0x00, 0xFF}
+
+// drawSpriteBoxes outlines each OAM sprite in an RGBA pixel buffer of the
+// Game Boy screen size. Sprites are clipped to the screen.
+func drawSpriteBoxes(pixels []byte, oam *[ppu.OAMSize]uint8, spriteHeight int) {
+	for i := 0; i < ppu.OAMSize; i += 4 {
+		// OAM positions are offset by 16 (Y) and 8 (X)
+		top := int(oam[i]) - 16
+		left := int(oam[i+1]) - 8
+		bottom := top + spriteHeight - 1
+		right := left + 7
+
+		for x := left; x <= right; x++ {
+			setOverlayPixel(pixels, x, top)
+			setOverlayPixel(pixels, x, bottom)
+		}
+		for y := top; y <= bottom; y++ {
+			setOverlayPixel(pixels, left, y)
+			setOverlayPixel(pixels, right, y)
+		}
+	}
+}
+
+// setOverlayPixel sets one pixel to the overlay color, ignoring off-screen pixels.
+func setOverlayPixel(pixels []byte, x, y int) {
+	if x < 0 || x >= ppu.ScreenWidth || y < 0 || y >= ppu.ScreenHeight {
+		return
+	}
+	offset := (y*ppu.ScreenWidth + x) * 4
+	pixels[offset] = overlayBoxColor.R
+	pixels[offset+1] = overlayBoxColor.G
+	pixels[offset+2] = overlayBoxColor.B
+	pixels[offset+3] = overlayBoxColor.A
+}
+
+// overlayText formats the PPU registers shown by the debug overlay.
+func overlayText(p *ppu.PPU) string {
+	return fmt.Sprintf("LCDC:%02X STAT:%02X LY:%02X", p.LCDC(), p.STAT(), p.LY())
+}
+
+// audioStatsText formats the audio buffer telemetry shown by the debug overlay.
+func audioStatsText(underruns, overruns uint64) string {
+	return fmt.Sprintf("AUDIO UNDER:%d OVER:%d", underruns, overruns)
+}
+
+// apuStateText formats the APU channel state shown by the debug overlay,
+// one line for the master controls and one per channel.
+func apuStateText(s apu.APUState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "APU:%s VOL:%d/%d PAN:%02X", onOff(s.Enabled), s.LeftVolume, s.RightVolume, s.Panning)
+	for i, ch := range s.Channels {
+		fmt.Fprintf(&b, "\nCH%d:%s DAC:%s VOL:%X FREQ:%03X LEN:%d", i+1,
+			onOff(ch.Enabled), onOff(ch.DACEnabled), ch.Volume, ch.Frequency, ch.Length)
+	}
+	return b.String()
+}
+
+// onOff formats a flag for the debug overlay.
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// spriteHeight returns the sprite height selected by LCDC.
+func spriteHeight(lcdc uint8) int {
+	if lcdc&ppu.LCDCOBJSize != 0 {
+		return 16
+	}
+	return 8
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/richardwooding/nostalgiza/internal/apu"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+func TestDrawSpriteBoxes(t *testing.T) {
+	pixels := make([]byte, ppu.ScreenWidth*ppu.ScreenHeight*4)
+	var oam [ppu.OAMSize]uint8
+	oam[0], oam[1] = 16+10, 8+20 // Sprite 0 at (20, 10)
+	// Remaining sprites are at OAM (0, 0), entirely off-screen
+
+	drawSpriteBoxes(pixels, &oam, 8)
+
+	isBox := func(x, y int) bool {
+		return pixels[(y*ppu.ScreenWidth+x)*4] == overlayBoxColor.R
+	}
+	for _, pt := range [][2]int{{20, 10}, {27, 10}, {20, 17}, {27, 17}, {23, 10}, {20, 14}} {
+		if !isBox(pt[0], pt[1]) {
+			t.Errorf("pixel (%d, %d) not outlined", pt[0], pt[1])
+		}
+	}
+	for _, pt := range [][2]int{{23, 13}, {19, 10}, {20, 18}, {0, 0}} {
+		if isBox(pt[0], pt[1]) {
+			t.Errorf("pixel (%d, %d) outlined, want untouched", pt[0], pt[1])
+		}
+	}
+}
+
+func TestOverlayText(t *testing.T) {
+	p := ppu.New(nil)
+	if got, want := overlayText(p), "LCDC:91 STAT:00 LY:00"; got != want {
+		t.Errorf("overlayText() = %q, want %q", got, want)
+	}
+}
+
+func TestAPUStateText(t *testing.T) {
+	a := apu.New()
+	a.Write(0xFF26, 0x80) // Enable APU
+	a.Write(0xFF24, 0x77) // NR50: full volume
+	a.Write(0xFF12, 0xF0) // CH1 volume 15
+	a.Write(0xFF13, 0x00)
+	a.Write(0xFF14, 0x87) // CH1 trigger, frequency 0x700
+
+	want := "APU:ON VOL:7/7 PAN:00\n" +
+		"CH1:ON DAC:ON VOL:F FREQ:700 LEN:64\n" +
+		"CH2:OFF DAC:OFF VOL:0 FREQ:000 LEN:0\n" +
+		"CH3:OFF DAC:OFF VOL:0 FREQ:000 LEN:0\n" +
+		"CH4:OFF DAC:OFF VOL:0 FREQ:000 LEN:0"
+	if got := apuStateText(a.DebugState()); got != want {
+		t.Errorf("apuStateText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+// fullscreenKey toggles fullscreen mode.
+const fullscreenKey = ebiten.KeyF11
+
+// Window scale factors accepted by --scale.
+const (
+	minScale     = 1
+	maxScale     = 10
+	defaultScale = 3
+)
+
+// scaleForHeight returns the integer scale whose window height is closest to
+// height logical pixels, within minScale and maxScale.
+func scaleForHeight(height int) int {
+	scale := int(math.Round(float64(height) / ppu.ScreenHeight))
+	return max(minScale, min(maxScale, scale))
+}
+
+// windowScale returns the window scale factor: scale if given (non-nil),
+// otherwise the scale nearest windowHeight if given, otherwise defaultScale.
+func windowScale(scale *int, windowHeight int) int {
+	switch {
+	case scale != nil:
+		return *scale
+	case windowHeight > 0:
+		return scaleForHeight(windowHeight)
+	default:
+		return defaultScale
+	}
+}
+
+// scaleMode selects how the Game Boy screen is scaled to the window.
+type scaleMode string
+
+// Scale modes.
+const (
+	scaleStretch scaleMode = "stretch" // Fill the window, ignoring the aspect ratio
+	scaleInteger scaleMode = "integer" // Largest whole multiple that fits, centered
+	scaleFit     scaleMode = "fit"     // Largest size that fits with the aspect ratio, centered
+)
+
+// devicePixels converts a length in logical pixels to device pixels for a
+// device scale factor. Factors below 1 are treated as 1.
+func devicePixels(logical int, factor float64) int {
+	if factor <= 1 {
+		return logical
+	}
+	return int(math.Ceil(float64(logical) * factor))
+}
+
+// destRect returns where an image of the native size is drawn in a window of
+// the given size for mode. Modes other than stretch center the image between
+// black bars. Unknown modes behave like scaleFit.
+func destRect(mode scaleMode, windowWidth, windowHeight, nativeWidth, nativeHeight int) image.Rectangle {
+	if windowWidth <= 0 || windowHeight <= 0 || nativeWidth <= 0 || nativeHeight <= 0 {
+		return image.Rect(0, 0, nativeWidth, nativeHeight)
+	}
+
+	var width, height int
+	switch mode {
+	case scaleStretch:
+		return image.Rect(0, 0, windowWidth, windowHeight)
+
+	case scaleInteger:
+		// Never scale below 1x, even if the window is smaller than the screen
+		scale := max(1, min(windowWidth/nativeWidth, windowHeight/nativeHeight))
+		width, height = nativeWidth*scale, nativeHeight*scale
+
+	default: // scaleFit
+		scale := min(float64(windowWidth)/float64(nativeWidth), float64(windowHeight)/float64(nativeHeight))
+		width = int(math.Round(float64(nativeWidth) * scale))
+		height = int(math.Round(float64(nativeHeight) * scale))
+	}
+
+	x := (windowWidth - width) / 2
+	y := (windowHeight - height) / 2
+	return image.Rect(x, y, x+width, y+height)
+}
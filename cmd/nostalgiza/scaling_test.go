@@ -0,0 +1,103 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+func TestDestRect(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          scaleMode
+		width, height int
+		want          image.Rectangle
+	}{
+		{"Stretch native", scaleStretch, 160, 144, image.Rect(0, 0, 160, 144)},
+		{"Stretch wide", scaleStretch, 1920, 1080, image.Rect(0, 0, 1920, 1080)},
+		{"Stretch tall", scaleStretch, 320, 600, image.Rect(0, 0, 320, 600)},
+
+		{"Integer native", scaleInteger, 160, 144, image.Rect(0, 0, 160, 144)},
+		{"Integer 3x", scaleInteger, 480, 432, image.Rect(0, 0, 480, 432)},
+		{"Integer 1080p", scaleInteger, 1920, 1080, image.Rect(400, 36, 1520, 1044)},
+		{"Integer between sizes", scaleInteger, 500, 450, image.Rect(10, 9, 490, 441)},
+		{"Integer too small", scaleInteger, 100, 100, image.Rect(-30, -22, 130, 122)},
+
+		{"Fit native", scaleFit, 160, 144, image.Rect(0, 0, 160, 144)},
+		{"Fit 1080p", scaleFit, 1920, 1080, image.Rect(360, 0, 1560, 1080)},
+		{"Fit tall", scaleFit, 320, 600, image.Rect(0, 156, 320, 444)},
+		{"Fit between sizes", scaleFit, 500, 450, image.Rect(0, 0, 500, 450)},
+
+		{"Unknown mode fits", scaleMode(""), 1920, 1080, image.Rect(360, 0, 1560, 1080)},
+		{"Zero window", scaleFit, 0, 0, image.Rect(0, 0, 160, 144)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := destRect(tt.mode, tt.width, tt.height, 160, 144)
+			if got != tt.want {
+				t.Errorf("destRect(%q, %d, %d) = %v, want %v", tt.mode, tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleForHeight(t *testing.T) {
+	tests := []struct {
+		height int
+		want   int
+	}{
+		{0, 1},
+		{100, 1},
+		{144, 1},
+		{215, 1},
+		{216, 2}, // 1.5x rounds up
+		{432, 3},
+		{720, 5},
+		{1080, 8},
+		{1440, 10},
+		{2160, 10}, // Clamped to the largest scale
+	}
+	for _, tt := range tests {
+		if got := scaleForHeight(tt.height); got != tt.want {
+			t.Errorf("scaleForHeight(%d) = %d, want %d", tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestWindowScale(t *testing.T) {
+	scale := func(n int) *int { return &n }
+	tests := []struct {
+		name         string
+		scale        *int
+		windowHeight int
+		want         int
+	}{
+		{"Default", nil, 0, defaultScale},
+		{"Height", nil, 720, 5},
+		{"Scale overrides height", scale(2), 720, 2},
+		{"Scale", scale(4), 0, 4},
+	}
+	for _, tt := range tests {
+		if got := windowScale(tt.scale, tt.windowHeight); got != tt.want {
+			t.Errorf("%s: windowScale(%v, %d) = %d, want %d", tt.name, tt.scale, tt.windowHeight, got, tt.want)
+		}
+	}
+}
+
+func TestDevicePixels(t *testing.T) {
+	tests := []struct {
+		logical int
+		factor  float64
+		want    int
+	}{
+		{480, 1, 480},
+		{480, 0, 480},
+		{480, 2, 960},
+		{481, 1.5, 722},
+	}
+	for _, tt := range tests {
+		if got := devicePixels(tt.logical, tt.factor); got != tt.want {
+			t.Errorf("devicePixels(%d, %g) = %d, want %d", tt.logical, tt.factor, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+)
+
+// stateSlotKeys save to slots 1-4; with Shift held they load instead.
+var stateSlotKeys = [...]ebiten.Key{ebiten.KeyF5, ebiten.KeyF6, ebiten.KeyF7, ebiten.KeyF8}
+
+// stateSlots is the number of numbered save state slots.
+const stateSlots = len(stateSlotKeys)
+
+// stateSlotPath returns the save state file for a numbered slot (1-based):
+// the ROM path with its extension replaced by .stateN.
+func stateSlotPath(romPath string, slot int) string {
+	base := strings.TrimSuffix(romPath, filepath.Ext(romPath))
+	return fmt.Sprintf("%s.state%d", base, slot)
+}
+
+// saveStateSlot writes the emulator state to a slot file.
+func saveStateSlot(emu *emulator.Emulator, path string) error {
+	state, err := emu.SaveState()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, state, 0o600); err != nil {
+		return fmt.Errorf("failed to write save state: %w", err)
+	}
+	return nil
+}
+
+// loadStateSlot restores the emulator state from a slot file. It returns
+// false, with no error, if the slot has not been saved yet.
+func loadStateSlot(emu *emulator.Emulator, path string) (bool, error) {
+	state, err := os.ReadFile(path) // #nosec G304 - path is derived from the ROM path
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read save state: %w", err)
+	}
+	if err := emu.LoadState(state); err != nil {
+		return false, fmt.Errorf("failed to load save state: %w", err)
+	}
+	return true, nil
+}
+
+// stateSlotMessage saves (load == false) or loads a slot and returns the
+// confirmation to show, logging the outcome.
+func stateSlotMessage(emu *emulator.Emulator, romPath string, slot int, load bool) string {
+	path := stateSlotPath(romPath, slot)
+
+	if !load {
+		if err := saveStateSlot(emu, path); err != nil {
+			slog.Warn("save state failed", "slot", slot, "error", err)
+			return fmt.Sprintf("Save to slot %d failed", slot)
+		}
+		slog.Info("saved state", "slot", slot, "path", path)
+		return fmt.Sprintf("Saved slot %d", slot)
+	}
+
+	ok, err := loadStateSlot(emu, path)
+	switch {
+	case err != nil:
+		slog.Warn("load state failed", "slot", slot, "error", err)
+		return fmt.Sprintf("Load from slot %d failed", slot)
+	case !ok:
+		slog.Info("save state slot is empty", "slot", slot, "path", path)
+		return fmt.Sprintf("Slot %d is empty", slot)
+	}
+	slog.Info("loaded state", "slot", slot, "path", path)
+	return fmt.Sprintf("Loaded slot %d", slot)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStateSlotPath(t *testing.T) {
+	tests := []struct {
+		romPath string
+		slot    int
+		want    string
+	}{
+		{"game.gb", 1, "game.state1"},
+		{"roms/game.gbc", stateSlots, "roms/game.state4"},
+		{"roms/game", 2, "roms/game.state2"},
+		{"roms.v2/game.gb", 3, "roms.v2/game.state3"},
+	}
+
+	for _, tt := range tests {
+		if got := stateSlotPath(tt.romPath, tt.slot); got != tt.want {
+			t.Errorf("stateSlotPath(%q, %d) = %q, want %q", tt.romPath, tt.slot, got, tt.want)
+		}
+	}
+}
+
+func TestStateSlotRoundTrip(t *testing.T) {
+	emu := newTestEmulator(t)
+	path := stateSlotPath(filepath.Join(t.TempDir(), "game.gb"), 1)
+
+	// An unsaved slot is not an error and leaves the emulator running
+	if ok, err := loadStateSlot(emu, path); err != nil || ok {
+		t.Fatalf("loadStateSlot() = %v, %v; want false, nil", ok, err)
+	}
+
+	emu.RunFrame()
+	if err := saveStateSlot(emu, path); err != nil {
+		t.Fatalf("saveStateSlot() error = %v", err)
+	}
+	saved := emu.Cycles()
+
+	emu.RunFrame()
+	if ok, err := loadStateSlot(emu, path); err != nil || !ok {
+		t.Fatalf("loadStateSlot() = %v, %v; want true, nil", ok, err)
+	}
+	if got := emu.Cycles(); got != saved {
+		t.Errorf("Cycles() after loading = %d, want %d", got, saved)
+	}
+}
+
+func TestStateSlotMessage(t *testing.T) {
+	emu := newTestEmulator(t)
+	romPath := filepath.Join(t.TempDir(), "game.gb")
+
+	steps := []struct {
+		slot int
+		load bool
+		want string
+	}{
+		{2, true, "Slot 2 is empty"},
+		{2, false, "Saved slot 2"},
+		{2, true, "Loaded slot 2"},
+		{3, true, "Slot 3 is empty"},
+	}
+	for _, step := range steps {
+		if got := stateSlotMessage(emu, romPath, step.slot, step.load); got != step.want {
+			t.Errorf("stateSlotMessage(slot %d, load %v) = %q, want %q", step.slot, step.load, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"math"
+
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+// Supported --fps range.
+const (
+	minFPS = 1
+	maxFPS = 240
+)
+
+// Maximum --frame-skip value.
+const maxFrameSkip = 9
+
+// tickRate returns the Ebiten ticks per second used for a target frame rate:
+// the nearest whole number, since Ebiten only supports integer rates.
+func tickRate(fps float64) int {
+	return max(1, int(math.Round(fps)))
+}
+
+// cyclesPerTick returns how many emulated cycles to run per Ebiten tick so
+// that the emulator produces fps frames per second at tps ticks per second.
+// The result is fractional; the display carries the remainder between ticks.
+func cyclesPerTick(fps float64, tps int) float64 {
+	return fps * ppu.DotsPerFrame / float64(tps)
+}
+
+// presentFrame reports whether the display presents draw number frame when
+// skipping skip frames after each presented one. Emulation and audio run on
+// every frame regardless.
+func presentFrame(frame uint64, skip int) bool {
+	return skip <= 0 || frame%uint64(skip+1) == 0 //nolint:gosec // skip is validated non-negative
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+func TestTickRate(t *testing.T) {
+	tests := []struct {
+		fps  float64
+		want int
+	}{
+		{59.7275, 60},
+		{60, 60},
+		{30, 30},
+		{144.4, 144},
+		{0.2, 1},
+	}
+
+	for _, tt := range tests {
+		if got := tickRate(tt.fps); got != tt.want {
+			t.Errorf("tickRate(%g) = %d, want %d", tt.fps, got, tt.want)
+		}
+	}
+}
+
+func TestCyclesPerTick(t *testing.T) {
+	nativeFPS := float64(emulator.ClockSpeed) / ppu.DotsPerFrame
+
+	tests := []struct {
+		name string
+		fps  float64
+		tps  int
+		want float64
+	}{
+		// Real time: one tick at 60 Hz is 1/60 s of Game Boy time
+		{"Native at 60 TPS", nativeFPS, 60, emulator.ClockSpeed / 60.0},
+		{"60 FPS", 60, 60, ppu.DotsPerFrame},
+		{"Double speed", 120, 60, 2 * ppu.DotsPerFrame},
+		{"30 FPS", 30, 30, ppu.DotsPerFrame},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cyclesPerTick(tt.fps, tt.tps); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("cyclesPerTick(%g, %d) = %f, want %f", tt.fps, tt.tps, got, tt.want)
+			}
+		})
+	}
+
+	// Over the first second at the native rate, 59 whole frames run; the partial
+	// 60th frame is carried over in the budget
+	perTick := cyclesPerTick(nativeFPS, tickRate(nativeFPS))
+	budget, frames := 0.0, 0
+	for range tickRate(nativeFPS) {
+		budget += perTick
+		for budget >= ppu.DotsPerFrame {
+			budget -= ppu.DotsPerFrame
+			frames++
+		}
+	}
+	if frames != 59 {
+		t.Errorf("frames in one second = %d, want 59", frames)
+	}
+}
+
+func TestPresentFrame(t *testing.T) {
+	tests := []struct {
+		name string
+		skip int
+		want []bool // For frames 0-5
+	}{
+		{"No skip", 0, []bool{true, true, true, true, true, true}},
+		{"Skip 1", 1, []bool{true, false, true, false, true, false}},
+		{"Skip 2", 2, []bool{true, false, false, true, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for frame, want := range tt.want {
+				if got := presentFrame(uint64(frame), tt.skip); got != want { //nolint:gosec // Test values are small
+					t.Errorf("presentFrame(%d, %d) = %v, want %v", frame, tt.skip, got, want)
+				}
+			}
+		})
+	}
+}
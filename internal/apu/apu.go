@@ -17,6 +17,7 @@ type APU struct {
 	// Frame sequencer (512 Hz, every 8192 CPU cycles)
 	frameStep    uint8  // Current step (0-7)
 	frameCounter uint16 // Cycles until next step
+	divClocked   bool   // Steps come from ClockFrameSequencer instead of frameCounter
 
 	// Sound channels
 	channel1 *PulseChannel // Pulse with sweep
@@ -34,6 +35,7 @@ type APU struct {
 	panning uint8 // Channel panning bits
 
 	// Audio output
+	mono              bool      // Downmix left and right to mono
 	sampleBuffer      []float32 // Stereo samples (L, R, L, R, ...)
 	sampleAccumulator float64   // Fractional samples accumulated between Update() calls
 }
@@ -55,11 +57,13 @@ func (a *APU) Update(cycles uint16) {
 		return
 	}
 
-	// Update frame sequencer
-	a.frameCounter += cycles
-	for a.frameCounter >= 8192 {
-		a.frameCounter -= 8192
-		a.clockFrameSequencer()
+	// Update frame sequencer (unless driven by the timer's DIV counter)
+	if !a.divClocked {
+		a.frameCounter += cycles
+		for a.frameCounter >= 8192 {
+			a.frameCounter -= 8192
+			a.clockFrameSequencer()
+		}
 	}
 
 	// Update each channel
@@ -72,6 +76,37 @@ func (a *APU) Update(cycles uint16) {
 	a.generateSamples(cycles)
 }
 
+// SetDIVClocked selects whether the frame sequencer is clocked by the timer's
+// DIV counter (via ClockFrameSequencer) rather than by an internal counter.
+// Hardware clocks it from DIV, so DIV writes affect frame sequencer timing.
+func (a *APU) SetDIVClocked(divClocked bool) {
+	a.divClocked = divClocked
+}
+
+// SetWaveRAMConflicts selects whether CPU access to wave RAM (0xFF30-0xFF3F)
+// is restricted to the byte channel 3 is playing while it is on, as on DMG.
+// It is off by default because naive code that writes wave RAM during
+// playback depends on free access.
+func (a *APU) SetWaveRAMConflicts(enabled bool) {
+	a.channel3.SetAccessConflicts(enabled)
+}
+
+// SetMono selects whether output is downmixed to mono. When enabled, each
+// sample is the average of the panned left and right mixes, written to both
+// channels. Panning is still applied first, so this is a pure downmix.
+func (a *APU) SetMono(mono bool) {
+	a.mono = mono
+}
+
+// ClockFrameSequencer advances the frame sequencer by one step when it is
+// DIV-clocked and the APU is enabled. It is called on falling edges of DIV bit 4.
+func (a *APU) ClockFrameSequencer() {
+	if !a.enabled || !a.divClocked {
+		return
+	}
+	a.clockFrameSequencer()
+}
+
 // clockFrameSequencer advances the frame sequencer by one step.
 func (a *APU) clockFrameSequencer() {
 	// Length counter (256 Hz) - steps 0, 2, 4, 6
@@ -169,6 +204,12 @@ func (a *APU) generateSamples(cycles uint16) {
 		left *= 0.6
 		right *= 0.6
 
+		// Optional mono downmix
+		if a.mono {
+			mid := (left + right) / 2
+			left, right = mid, mid
+		}
+
 		// Add to output buffer (stereo interleaved)
 		a.sampleBuffer = append(a.sampleBuffer, left, right)
 	}
@@ -255,8 +296,10 @@ func (a *APU) Write(addr uint16, value uint8) {
 		return
 	}
 
-	// When APU is disabled, all other registers are read-only
+	// When APU is disabled, all other registers are read-only,
+	// except the length timers, which remain writable on DMG
 	if !a.enabled {
+		a.writeLengthWhileDisabled(addr, value)
 		return
 	}
 
@@ -321,6 +364,21 @@ func (a *APU) Write(addr uint16, value uint8) {
 	}
 }
 
+// writeLengthWhileDisabled handles writes while the APU is disabled, when only
+// the length load bits of NR11, NR21, NR31 and NR41 are writable.
+func (a *APU) writeLengthWhileDisabled(addr uint16, value uint8) {
+	switch addr {
+	case 0xFF11:
+		a.channel1.WriteLength(value)
+	case 0xFF16:
+		a.channel2.WriteLength(value)
+	case 0xFF1B:
+		a.channel3.WriteLength(value)
+	case 0xFF20:
+		a.channel4.WriteLength(value)
+	}
+}
+
 // readNR50 reads the NR50 register (master volume).
 func (a *APU) readNR50() uint8 {
 	var value uint8
@@ -372,9 +430,9 @@ func (a *APU) writeNR52(value uint8) {
 	wasEnabled := a.enabled
 	a.enabled = (value & 0x80) != 0
 
-	// If APU is being disabled, clear all registers
+	// If APU is being disabled, clear all registers (except length counters on DMG)
 	if wasEnabled && !a.enabled {
-		a.reset()
+		a.powerOff()
 	}
 
 	// If APU is being enabled, reset frame sequencer
@@ -384,12 +442,27 @@ func (a *APU) writeNR52(value uint8) {
 	}
 }
 
-// reset clears all APU registers (called when APU is disabled).
+// powerOff clears the APU registers when the APU is disabled via NR52.
+// On DMG the channel length counters survive power-off.
+func (a *APU) powerOff() {
+	a.channel1.PowerOff()
+	a.channel2.PowerOff()
+	a.channel3.PowerOff()
+	a.channel4.PowerOff()
+	a.resetControl()
+}
+
+// reset clears all APU registers, including the length counters.
 func (a *APU) reset() {
 	a.channel1.Reset()
 	a.channel2.Reset()
 	a.channel3.Reset()
 	a.channel4.Reset()
+	a.resetControl()
+}
+
+// resetControl clears the master control registers and frame sequencer.
+func (a *APU) resetControl() {
 	a.leftVolume = 0
 	a.rightVolume = 0
 	a.vinLeft = false
@@ -237,6 +237,72 @@ func TestAPU_DisableClearsRegisters(_ *testing.T) {
 	}
 }
 
+func TestAPU_LengthWritableWhileDisabled(t *testing.T) {
+	apu := New() // APU starts disabled
+
+	apu.Write(0xFF11, 0xC5) // Duty 3, length load 5
+	apu.Write(0xFF16, 0x0A) // Length load 10
+	apu.Write(0xFF1B, 0x20) // Length load 32
+	apu.Write(0xFF20, 0x3F) // Length load 63
+
+	if got := apu.channel1.lengthCounter; got != 59 {
+		t.Errorf("channel 1 length counter = %d, want 59", got)
+	}
+	if got := apu.channel2.lengthCounter; got != 54 {
+		t.Errorf("channel 2 length counter = %d, want 54", got)
+	}
+	if got := apu.channel3.lengthCounter; got != 224 {
+		t.Errorf("channel 3 length counter = %d, want 224", got)
+	}
+	if got := apu.channel4.lengthCounter; got != 1 {
+		t.Errorf("channel 4 length counter = %d, want 1", got)
+	}
+
+	// The duty bits of the same write are ignored
+	if got := apu.channel1.dutyCycle; got != 0 {
+		t.Errorf("channel 1 duty = %d, want 0 (write ignored)", got)
+	}
+
+	// Other registers stay blocked
+	apu.Write(0xFF12, 0xF0)
+	apu.Write(0xFF13, 0x42)
+	if apu.channel1.nr12 != 0 || apu.channel1.frequency != 0 {
+		t.Errorf("NR12 = 0x%02X, frequency = %d, want writes ignored", apu.channel1.nr12, apu.channel1.frequency)
+	}
+}
+
+func TestAPU_PowerOffKeepsLengthCounters(t *testing.T) {
+	apu := New()
+	apu.Write(0xFF26, 0x80) // Enable APU
+
+	apu.Write(0xFF11, 0x80|0x10) // Duty 2, length load 16
+	apu.Write(0xFF12, 0xF0)
+	apu.Write(0xFF13, 0x42)
+	apu.Write(0xFF1B, 0x80) // Length load 128
+
+	// Power off via NR52
+	apu.Write(0xFF26, 0x00)
+
+	if got := apu.channel1.lengthCounter; got != 48 {
+		t.Errorf("channel 1 length counter = %d, want 48", got)
+	}
+	if got := apu.channel3.lengthCounter; got != 128 {
+		t.Errorf("channel 3 length counter = %d, want 128", got)
+	}
+
+	// Everything else is cleared
+	if apu.channel1.dutyCycle != 0 || apu.channel1.frequency != 0 || apu.channel1.nr12 != 0 {
+		t.Errorf("channel 1 duty = %d, frequency = %d, NR12 = 0x%02X, want cleared",
+			apu.channel1.dutyCycle, apu.channel1.frequency, apu.channel1.nr12)
+	}
+
+	// A full reset clears the length counters too
+	apu.Reset()
+	if got := apu.channel1.lengthCounter; got != 0 {
+		t.Errorf("channel 1 length counter after Reset = %d, want 0", got)
+	}
+}
+
 func TestAPU_SampleGeneration(t *testing.T) {
 	apu := New()
 	apu.Write(0xFF26, 0x80) // Enable APU
@@ -272,6 +338,40 @@ func TestAPU_SampleGeneration(t *testing.T) {
 	}
 }
 
+func TestAPU_MonoDownmix(t *testing.T) {
+	// generate runs channel 1 panned left only and returns the first stereo sample
+	generate := func(mono bool) (float32, float32) {
+		apu := New()
+		apu.SetMono(mono)
+		apu.Write(0xFF26, 0x80) // Enable APU
+		apu.Write(0xFF11, 0xC0) // 75% duty, so the first step is high
+		apu.Write(0xFF12, 0xF0) // Max volume
+		apu.Write(0xFF14, 0x80) // Trigger
+		apu.Write(0xFF24, 0x77) // Max volume
+		apu.Write(0xFF25, 0x10) // CH1 left only
+
+		apu.Update(100)
+		samples := apu.GetSampleBuffer()
+		if len(samples) < 2 {
+			t.Fatalf("len(samples) = %d, want at least 2", len(samples))
+		}
+		return samples[0], samples[1]
+	}
+
+	left, right := generate(false)
+	if left == 0 || right != 0 {
+		t.Fatalf("stereo sample = (%f, %f), want left-only", left, right)
+	}
+
+	monoLeft, monoRight := generate(true)
+	if monoLeft != monoRight {
+		t.Errorf("mono sample = (%f, %f), want equal channels", monoLeft, monoRight)
+	}
+	if monoLeft != left/2 {
+		t.Errorf("mono sample = %f, want %f (half of left)", monoLeft, left/2)
+	}
+}
+
 func TestAPU_Reset(t *testing.T) {
 	apu := New()
 
@@ -324,6 +424,31 @@ func TestAPU_FrameSequencerSteps(t *testing.T) {
 	}
 }
 
+func TestAPU_DIVClockedFrameSequencer(t *testing.T) {
+	apu := New()
+	apu.SetDIVClocked(true)
+	apu.Write(0xFF26, 0x80) // Enable APU
+
+	// The internal counter no longer advances the frame sequencer
+	apu.Update(8192)
+	if apu.frameStep != 0 {
+		t.Errorf("frameStep after Update = %d, want 0 (DIV-clocked)", apu.frameStep)
+	}
+
+	// Each DIV-APU event advances one step
+	apu.ClockFrameSequencer()
+	if apu.frameStep != 1 {
+		t.Errorf("frameStep after ClockFrameSequencer = %d, want 1", apu.frameStep)
+	}
+
+	// Disabled APU ignores DIV-APU events
+	apu.Write(0xFF26, 0x00)
+	apu.ClockFrameSequencer()
+	if apu.frameStep != 0 {
+		t.Errorf("frameStep with APU disabled = %d, want 0", apu.frameStep)
+	}
+}
+
 func TestAPU_Update_DisabledAPU(t *testing.T) {
 	apu := New()
 	// APU starts disabled
@@ -337,3 +462,50 @@ func TestAPU_Update_DisabledAPU(t *testing.T) {
 		t.Error("Disabled APU should not generate samples")
 	}
 }
+
+func TestAPU_DACDisable(t *testing.T) {
+	tests := []struct {
+		name    string
+		nrx2    uint16
+		nrx4    uint16
+		bit     uint8
+		channel func(a *APU) float32
+	}{
+		{"channel 1", 0xFF12, 0xFF14, 0x01, func(a *APU) float32 { return a.channel1.GetSample() }},
+		{"channel 2", 0xFF17, 0xFF19, 0x02, func(a *APU) float32 { return a.channel2.GetSample() }},
+		{"channel 4", 0xFF21, 0xFF23, 0x08, func(a *APU) float32 { return a.channel4.GetSample() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apu := New()
+			apu.Write(0xFF26, 0x80) // Enable APU
+			apu.Write(tt.nrx2, 0xF0)
+			apu.Write(tt.nrx4, 0x80) // Trigger
+			if apu.Read(0xFF26)&tt.bit == 0 {
+				t.Fatal("channel should be enabled after trigger")
+			}
+
+			// Clearing the top 5 bits turns the DAC off, even with an
+			// envelope period set
+			apu.Write(tt.nrx2, 0x07)
+			if apu.Read(0xFF26)&tt.bit != 0 {
+				t.Error("NR52 status bit should clear when the DAC is turned off")
+			}
+			if got := tt.channel(apu); got != 0 {
+				t.Errorf("sample = %v, want 0 with the DAC off", got)
+			}
+
+			// Turning the DAC back on does not restart the channel
+			apu.Write(tt.nrx2, 0xF0)
+			if apu.Read(0xFF26)&tt.bit != 0 {
+				t.Error("channel should stay disabled until triggered")
+			}
+
+			apu.Write(tt.nrx4, 0x80) // Trigger
+			if apu.Read(0xFF26)&tt.bit == 0 {
+				t.Error("channel should be enabled after re-trigger")
+			}
+		})
+	}
+}
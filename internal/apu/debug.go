@@ -0,0 +1,72 @@
+package apu
+
+// ChannelState is a read-only snapshot of a sound channel, for debugging.
+type ChannelState struct {
+	Enabled       bool
+	DACEnabled    bool
+	Volume        uint8  // Envelope volume (0-15); NR32 output level code (0-3) on channel 3
+	Frequency     uint16 // 11-bit period value; NR43 (shift, width, divisor) on channel 4
+	Length        uint16 // Remaining length counter
+	LengthEnabled bool
+}
+
+// APUState is a read-only snapshot of the APU, for debugging.
+type APUState struct {
+	Enabled     bool
+	Channels    [4]ChannelState // Channels 1-4
+	LeftVolume  uint8           // NR50 left master volume (0-7)
+	RightVolume uint8           // NR50 right master volume (0-7)
+	Panning     uint8           // NR51 panning bits
+}
+
+// DebugState returns a snapshot of the APU and channel state.
+func (a *APU) DebugState() APUState {
+	return APUState{
+		Enabled: a.enabled,
+		Channels: [4]ChannelState{
+			a.channel1.DebugState(),
+			a.channel2.DebugState(),
+			a.channel3.DebugState(),
+			a.channel4.DebugState(),
+		},
+		LeftVolume:  a.leftVolume,
+		RightVolume: a.rightVolume,
+		Panning:     a.panning,
+	}
+}
+
+// DebugState returns a snapshot of the channel state.
+func (p *PulseChannel) DebugState() ChannelState {
+	return ChannelState{
+		Enabled:       p.enabled,
+		DACEnabled:    p.dacEnabled,
+		Volume:        p.envelopeVolume,
+		Frequency:     p.frequency,
+		Length:        uint16(p.lengthCounter),
+		LengthEnabled: p.lengthEnabled,
+	}
+}
+
+// DebugState returns a snapshot of the channel state.
+func (w *WaveChannel) DebugState() ChannelState {
+	return ChannelState{
+		Enabled:       w.enabled,
+		DACEnabled:    w.dacEnabled,
+		Volume:        w.outputLevel,
+		Frequency:     w.frequency,
+		Length:        w.lengthCounter,
+		LengthEnabled: w.lengthEnabled,
+	}
+}
+
+// DebugState returns a snapshot of the channel state.
+func (n *NoiseChannel) DebugState() ChannelState {
+	return ChannelState{
+		Enabled:       n.enabled,
+		DACEnabled:    n.dacEnabled,
+		Volume:        n.envelopeVolume,
+		Frequency:     uint16(n.nr43),
+		Length:        uint16(n.lengthCounter),
+		LengthEnabled: n.lengthEnabled,
+	}
+}
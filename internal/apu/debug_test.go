@@ -0,0 +1,60 @@
+package apu
+
+import (
+	"testing"
+)
+
+func TestAPU_DebugState(t *testing.T) {
+	apu := New()
+	apu.Write(0xFF26, 0x80) // Enable APU
+	apu.Write(0xFF24, 0x53) // NR50: left 5, right 3
+	apu.Write(0xFF25, 0xF1) // NR51
+
+	apu.Write(0xFF12, 0xA0) // CH1 volume 10
+	apu.Write(0xFF13, 0x34) // CH1 frequency low
+	apu.Write(0xFF14, 0x85) // CH1 trigger, frequency high 5
+
+	apu.Write(0xFF1A, 0x80) // CH3 DAC enable
+	apu.Write(0xFF1C, 0x40) // CH3 output level 2 (50%)
+	apu.Write(0xFF1D, 0xFF) // CH3 frequency low
+	apu.Write(0xFF1E, 0x87) // CH3 trigger, frequency high 7
+
+	state := apu.DebugState()
+
+	if !state.Enabled {
+		t.Error("Enabled = false, want true")
+	}
+	if state.LeftVolume != 5 || state.RightVolume != 3 {
+		t.Errorf("master volume = %d/%d, want 5/3", state.LeftVolume, state.RightVolume)
+	}
+	if state.Panning != 0xF1 {
+		t.Errorf("Panning = 0x%02X, want 0xF1", state.Panning)
+	}
+
+	ch1 := state.Channels[0]
+	if !ch1.Enabled || !ch1.DACEnabled {
+		t.Errorf("CH1 enabled/DAC = %v/%v, want true/true", ch1.Enabled, ch1.DACEnabled)
+	}
+	if ch1.Volume != 10 {
+		t.Errorf("CH1 Volume = %d, want 10", ch1.Volume)
+	}
+	if ch1.Frequency != 0x534 {
+		t.Errorf("CH1 Frequency = 0x%03X, want 0x534", ch1.Frequency)
+	}
+
+	ch3 := state.Channels[2]
+	if !ch3.Enabled {
+		t.Error("CH3 Enabled = false, want true")
+	}
+	if ch3.Volume != 2 {
+		t.Errorf("CH3 Volume = %d, want 2", ch3.Volume)
+	}
+	if ch3.Frequency != 0x7FF {
+		t.Errorf("CH3 Frequency = 0x%03X, want 0x7FF", ch3.Frequency)
+	}
+
+	// Untriggered channels stay disabled
+	if state.Channels[1].Enabled || state.Channels[3].Enabled {
+		t.Error("CH2/CH4 enabled without a trigger")
+	}
+}
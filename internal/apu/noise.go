@@ -156,6 +156,14 @@ func (n *NoiseChannel) IsEnabled() bool {
 	return n.enabled
 }
 
+// PowerOff clears the channel when the APU is powered off via NR52.
+// Unlike Reset, the length counter is kept, as on DMG.
+func (n *NoiseChannel) PowerOff() {
+	length := n.lengthCounter
+	n.Reset()
+	n.lengthCounter = length
+}
+
 // Reset resets the channel to initial state.
 func (n *NoiseChannel) Reset() {
 	n.enabled = false
@@ -189,6 +197,11 @@ func (n *NoiseChannel) WriteNR41(value uint8) {
 	n.lengthCounter = 64 - (value & 0x3F)
 }
 
+// WriteLength writes the length load bits of NR41 while the APU is disabled.
+func (n *NoiseChannel) WriteLength(value uint8) {
+	n.lengthCounter = 64 - (value & 0x3F)
+}
+
 // ReadNR42 reads NR42 (volume envelope).
 func (n *NoiseChannel) ReadNR42() uint8 {
 	return n.nr42
@@ -13,6 +13,7 @@ type PulseChannel struct {
 	sweepTimer   uint8
 	sweepEnabled bool
 	sweepShadow  uint16
+	sweepNegUsed bool // A negate-mode calculation ran since the last trigger
 
 	// Length timer
 	lengthCounter uint8
@@ -150,11 +151,13 @@ func (p *PulseChannel) ClockSweep() {
 }
 
 // calculateSweepFrequency calculates the new frequency from sweep.
+// It disables the channel if the result overflows 11 bits.
 func (p *PulseChannel) calculateSweepFrequency() uint16 {
 	delta := p.sweepShadow >> p.sweepShift
 	var newFreq uint16
 	if p.sweepNegate {
 		newFreq = p.sweepShadow - delta
+		p.sweepNegUsed = true
 	} else {
 		newFreq = p.sweepShadow + delta
 	}
@@ -191,8 +194,10 @@ func (p *PulseChannel) trigger() {
 			p.sweepTimer = 8
 		}
 		p.sweepEnabled = p.sweepPeriod > 0 || p.sweepShift > 0
+		p.sweepNegUsed = false
 
-		// Sweep calculation on trigger
+		// Immediate sweep calculation and overflow check on trigger.
+		// This runs whenever shift is non-zero, even if the period is 0.
 		if p.sweepShift > 0 {
 			_ = p.calculateSweepFrequency()
 		}
@@ -209,6 +214,14 @@ func (p *PulseChannel) IsEnabled() bool {
 	return p.enabled
 }
 
+// PowerOff clears the channel when the APU is powered off via NR52.
+// Unlike Reset, the length counter is kept, as on DMG.
+func (p *PulseChannel) PowerOff() {
+	length := p.lengthCounter
+	p.Reset()
+	p.lengthCounter = length
+}
+
 // Reset resets the channel to initial state.
 func (p *PulseChannel) Reset() {
 	p.enabled = false
@@ -237,6 +250,7 @@ func (p *PulseChannel) Reset() {
 		p.sweepTimer = 0
 		p.sweepEnabled = false
 		p.sweepShadow = 0
+		p.sweepNegUsed = false
 	}
 }
 
@@ -248,11 +262,17 @@ func (p *PulseChannel) ReadNR10() uint8 {
 }
 
 // WriteNR10 writes NR10 (sweep).
+// Clearing the negate bit after a negate-mode calculation has run since the
+// last trigger disables the channel.
 func (p *PulseChannel) WriteNR10(value uint8) {
 	p.nr10 = value
 	p.sweepPeriod = (value >> 4) & 0x07
 	p.sweepNegate = (value & 0x08) != 0
 	p.sweepShift = value & 0x07
+
+	if !p.sweepNegate && p.sweepNegUsed {
+		p.enabled = false
+	}
 }
 
 // ReadNR11 reads NR11/NR21 (length timer & duty).
@@ -267,6 +287,12 @@ func (p *PulseChannel) WriteNR11(value uint8) {
 	p.lengthCounter = 64 - (value & 0x3F)
 }
 
+// WriteLength writes only the length load bits of NR11/NR21, leaving the duty
+// unchanged. This is the only effect of the write while the APU is disabled.
+func (p *PulseChannel) WriteLength(value uint8) {
+	p.lengthCounter = 64 - (value & 0x3F)
+}
+
 // ReadNR12 reads NR12/NR22 (volume envelope).
 func (p *PulseChannel) ReadNR12() uint8 {
 	return p.nr12
@@ -292,6 +318,8 @@ func (p *PulseChannel) ReadNR13() uint8 {
 }
 
 // WriteNR13 writes NR13/NR23 (frequency low).
+// The new frequency takes effect at the next duty step. The sweep shadow
+// frequency is only reloaded on trigger, so an in-progress sweep is unaffected.
 func (p *PulseChannel) WriteNR13(value uint8) {
 	p.nr13 = value
 	p.frequency = (p.frequency & 0x0700) | uint16(value)
@@ -303,6 +331,7 @@ func (p *PulseChannel) ReadNR14() uint8 {
 }
 
 // WriteNR14 writes NR14/NR24 (frequency high & control).
+// As with NR13, frequency writes do not disturb the sweep shadow frequency.
 func (p *PulseChannel) WriteNR14(value uint8) {
 	p.nr14 = value
 	p.frequency = (p.frequency & 0x00FF) | (uint16(value&0x07) << 8)
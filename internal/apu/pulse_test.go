@@ -122,6 +122,122 @@ func TestPulseChannel_Sweep(t *testing.T) {
 	}
 }
 
+func TestPulseChannel_SweepTriggerOverflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		nr10        uint8
+		wantEnabled bool
+	}{
+		{"Shift 1, period 0 overflows", 0x01, false},
+		{"Shift 1, period 1 overflows", 0x11, false},
+		{"Shift 0 skips calculation", 0x10, true},
+		{"Negate cannot overflow", 0x09, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPulseChannel(true)
+			p.WriteNR10(tt.nr10)
+			p.WriteNR12(0xF0) // Max volume, DAC on
+			p.WriteNR13(0xFF)
+			p.WriteNR14(0x87) // Trigger, frequency 2047
+
+			if p.IsEnabled() != tt.wantEnabled {
+				t.Errorf("IsEnabled() = %v, want %v", p.IsEnabled(), tt.wantEnabled)
+			}
+		})
+	}
+}
+
+func TestPulseChannel_SweepNegateClear(t *testing.T) {
+	tests := []struct {
+		name        string
+		nr10        uint8 // Written before trigger
+		wantEnabled bool  // After clearing the negate bit
+	}{
+		{"After negate calculation", 0x19, false},  // Period 1, negate, shift 1
+		{"Without negate calculation", 0x08, true}, // Negate, shift 0: no calculation
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPulseChannel(true)
+			p.WriteNR10(tt.nr10)
+			p.WriteNR12(0xF0)
+			p.WriteNR13(100)
+			p.WriteNR14(0x80) // Trigger
+
+			if !p.IsEnabled() {
+				t.Fatal("channel should be enabled after trigger")
+			}
+
+			p.WriteNR10(tt.nr10 &^ 0x08) // Clear negate
+
+			if p.IsEnabled() != tt.wantEnabled {
+				t.Errorf("IsEnabled() = %v, want %v", p.IsEnabled(), tt.wantEnabled)
+			}
+		})
+	}
+
+	// A new trigger clears the negate history
+	p := NewPulseChannel(true)
+	p.WriteNR10(0x19)
+	p.WriteNR12(0xF0)
+	p.WriteNR13(100)
+	p.WriteNR14(0x80)
+	p.WriteNR10(0x08) // Negate, shift 0
+	p.WriteNR14(0x80) // Retrigger: no calculation
+	p.WriteNR10(0x00)
+	if !p.IsEnabled() {
+		t.Error("clearing negate after retrigger without calculation should not disable the channel")
+	}
+}
+
+func TestPulseChannel_FrequencyWriteDuringSweep(t *testing.T) {
+	p := NewPulseChannel(true)
+	p.WriteNR10(0x11) // Period 1, increase, shift 1
+	p.WriteNR12(0xF0)
+	p.WriteNR13(100)
+	p.WriteNR14(0x80) // Trigger: shadow = 100
+
+	// Direct frequency writes change the frequency but not the shadow
+	p.WriteNR13(200)
+	p.WriteNR14(0x00)
+	if p.frequency != 200 {
+		t.Errorf("frequency = %d, want 200", p.frequency)
+	}
+	if p.sweepShadow != 100 {
+		t.Errorf("sweepShadow = %d, want 100", p.sweepShadow)
+	}
+
+	// The sweep continues from the shadow value: 100 + 100>>1 = 150
+	p.ClockSweep()
+	if p.frequency != 150 {
+		t.Errorf("frequency after sweep = %d, want 150", p.frequency)
+	}
+	if p.sweepShadow != 150 {
+		t.Errorf("sweepShadow after sweep = %d, want 150", p.sweepShadow)
+	}
+	if !p.IsEnabled() {
+		t.Error("channel should still be enabled")
+	}
+}
+
+func TestPulseChannel_FrequencyWriteTakesEffect(t *testing.T) {
+	p := NewPulseChannel(false)
+	p.WriteNR12(0xF0)
+	p.WriteNR14(0x80) // Trigger at frequency 0 (8192 cycles per duty step)
+
+	// Switch to frequency 2047 (4 cycles per duty step) without retriggering
+	p.WriteNR13(0xFF)
+	p.WriteNR14(0x07)
+
+	p.Update(4)
+	if p.dutyPos != 1 {
+		t.Errorf("dutyPos = %d, want 1", p.dutyPos)
+	}
+}
+
 func TestPulseChannel_DACDisable(t *testing.T) {
 	p := NewPulseChannel(false)
 
@@ -0,0 +1,280 @@
+package apu
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// apuState is the APU state written by SaveState.
+type apuState struct {
+	Enabled      bool
+	FrameStep    uint8
+	FrameCounter uint16
+	LeftVolume   uint8
+	RightVolume  uint8
+	VINLeft      bool
+	VINRight     bool
+	Panning      uint8
+
+	Channel1 pulseState
+	Channel2 pulseState
+	Channel3 waveState
+	Channel4 noiseState
+}
+
+// pulseState is the state of a pulse channel.
+type pulseState struct {
+	Enabled    bool
+	DACEnabled bool
+
+	SweepPeriod  uint8
+	SweepNegate  bool
+	SweepShift   uint8
+	SweepTimer   uint8
+	SweepEnabled bool
+	SweepShadow  uint16
+	SweepNegUsed bool
+
+	LengthCounter uint8
+	LengthEnabled bool
+
+	EnvelopeVolume   uint8
+	EnvelopeInitial  uint8
+	EnvelopeIncrease bool
+	EnvelopePeriod   uint8
+	EnvelopeTimer    uint8
+
+	Frequency  uint16
+	DutyCycle  uint8
+	DutyPos    uint8
+	PhaseTimer uint16
+
+	NR10, NR11, NR12, NR13, NR14 uint8
+}
+
+// waveState is the state of the wave channel.
+type waveState struct {
+	Enabled    bool
+	DACEnabled bool
+
+	LengthCounter uint16
+	LengthEnabled bool
+
+	Frequency   uint16
+	OutputLevel uint8
+	PhaseTimer  uint16
+	WavePos     uint8
+	WaveRAM     [16]uint8
+
+	NR30, NR31, NR32, NR33, NR34 uint8
+}
+
+// noiseState is the state of the noise channel.
+type noiseState struct {
+	Enabled    bool
+	DACEnabled bool
+
+	LengthCounter uint8
+	LengthEnabled bool
+
+	EnvelopeVolume   uint8
+	EnvelopeInitial  uint8
+	EnvelopeIncrease bool
+	EnvelopePeriod   uint8
+	EnvelopeTimer    uint8
+
+	LFSR        uint16
+	LFSRWidth   bool
+	ClockShift  uint8
+	DivisorCode uint8
+	PhaseTimer  uint16
+
+	NR41, NR42, NR43, NR44 uint8
+}
+
+// SaveState writes the registers and channel state to w. Output settings
+// and buffered samples are not saved.
+func (a *APU) SaveState(w io.Writer) error {
+	s := apuState{
+		Enabled:      a.enabled,
+		FrameStep:    a.frameStep,
+		FrameCounter: a.frameCounter,
+		LeftVolume:   a.leftVolume,
+		RightVolume:  a.rightVolume,
+		VINLeft:      a.vinLeft,
+		VINRight:     a.vinRight,
+		Panning:      a.panning,
+
+		Channel1: a.channel1.state(),
+		Channel2: a.channel2.state(),
+		Channel3: a.channel3.state(),
+		Channel4: a.channel4.state(),
+	}
+	if err := binary.Write(w, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to write APU state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState. Output settings are kept
+// and buffered samples are dropped.
+func (a *APU) LoadState(r io.Reader) error {
+	var s apuState
+	if err := binary.Read(r, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to read APU state: %w", err)
+	}
+
+	a.enabled = s.Enabled
+	a.frameStep = s.FrameStep
+	a.frameCounter = s.FrameCounter
+	a.leftVolume = s.LeftVolume
+	a.rightVolume = s.RightVolume
+	a.vinLeft = s.VINLeft
+	a.vinRight = s.VINRight
+	a.panning = s.Panning
+
+	a.channel1.setState(s.Channel1)
+	a.channel2.setState(s.Channel2)
+	a.channel3.setState(s.Channel3)
+	a.channel4.setState(s.Channel4)
+
+	a.sampleBuffer = a.sampleBuffer[:0]
+	a.sampleAccumulator = 0
+	return nil
+}
+
+// state returns the channel state for saving.
+func (p *PulseChannel) state() pulseState {
+	return pulseState{
+		Enabled:          p.enabled,
+		DACEnabled:       p.dacEnabled,
+		SweepPeriod:      p.sweepPeriod,
+		SweepNegate:      p.sweepNegate,
+		SweepShift:       p.sweepShift,
+		SweepTimer:       p.sweepTimer,
+		SweepEnabled:     p.sweepEnabled,
+		SweepShadow:      p.sweepShadow,
+		SweepNegUsed:     p.sweepNegUsed,
+		LengthCounter:    p.lengthCounter,
+		LengthEnabled:    p.lengthEnabled,
+		EnvelopeVolume:   p.envelopeVolume,
+		EnvelopeInitial:  p.envelopeInitial,
+		EnvelopeIncrease: p.envelopeIncrease,
+		EnvelopePeriod:   p.envelopePeriod,
+		EnvelopeTimer:    p.envelopeTimer,
+		Frequency:        p.frequency,
+		DutyCycle:        p.dutyCycle,
+		DutyPos:          p.dutyPos,
+		PhaseTimer:       p.phaseTimer,
+		NR10:             p.nr10,
+		NR11:             p.nr11,
+		NR12:             p.nr12,
+		NR13:             p.nr13,
+		NR14:             p.nr14,
+	}
+}
+
+// setState restores saved channel state. Whether the channel has a sweep
+// unit is fixed and kept.
+func (p *PulseChannel) setState(s pulseState) {
+	p.enabled = s.Enabled
+	p.dacEnabled = s.DACEnabled
+	p.sweepPeriod = s.SweepPeriod
+	p.sweepNegate = s.SweepNegate
+	p.sweepShift = s.SweepShift
+	p.sweepTimer = s.SweepTimer
+	p.sweepEnabled = s.SweepEnabled
+	p.sweepShadow = s.SweepShadow
+	p.sweepNegUsed = s.SweepNegUsed
+	p.lengthCounter = s.LengthCounter
+	p.lengthEnabled = s.LengthEnabled
+	p.envelopeVolume = s.EnvelopeVolume
+	p.envelopeInitial = s.EnvelopeInitial
+	p.envelopeIncrease = s.EnvelopeIncrease
+	p.envelopePeriod = s.EnvelopePeriod
+	p.envelopeTimer = s.EnvelopeTimer
+	p.frequency = s.Frequency
+	p.dutyCycle = s.DutyCycle
+	p.dutyPos = s.DutyPos
+	p.phaseTimer = s.PhaseTimer
+	p.nr10, p.nr11, p.nr12, p.nr13, p.nr14 = s.NR10, s.NR11, s.NR12, s.NR13, s.NR14
+}
+
+// state returns the channel state for saving.
+func (w *WaveChannel) state() waveState {
+	return waveState{
+		Enabled:       w.enabled,
+		DACEnabled:    w.dacEnabled,
+		LengthCounter: w.lengthCounter,
+		LengthEnabled: w.lengthEnabled,
+		Frequency:     w.frequency,
+		OutputLevel:   w.outputLevel,
+		PhaseTimer:    w.phaseTimer,
+		WavePos:       w.wavePos,
+		WaveRAM:       w.waveRAM,
+		NR30:          w.nr30,
+		NR31:          w.nr31,
+		NR32:          w.nr32,
+		NR33:          w.nr33,
+		NR34:          w.nr34,
+	}
+}
+
+// setState restores saved channel state. The wave RAM access conflict
+// setting is kept.
+func (w *WaveChannel) setState(s waveState) {
+	w.enabled = s.Enabled
+	w.dacEnabled = s.DACEnabled
+	w.lengthCounter = s.LengthCounter
+	w.lengthEnabled = s.LengthEnabled
+	w.frequency = s.Frequency
+	w.outputLevel = s.OutputLevel
+	w.phaseTimer = s.PhaseTimer
+	w.wavePos = s.WavePos
+	w.waveRAM = s.WaveRAM
+	w.nr30, w.nr31, w.nr32, w.nr33, w.nr34 = s.NR30, s.NR31, s.NR32, s.NR33, s.NR34
+}
+
+// state returns the channel state for saving.
+func (n *NoiseChannel) state() noiseState {
+	return noiseState{
+		Enabled:          n.enabled,
+		DACEnabled:       n.dacEnabled,
+		LengthCounter:    n.lengthCounter,
+		LengthEnabled:    n.lengthEnabled,
+		EnvelopeVolume:   n.envelopeVolume,
+		EnvelopeInitial:  n.envelopeInitial,
+		EnvelopeIncrease: n.envelopeIncrease,
+		EnvelopePeriod:   n.envelopePeriod,
+		EnvelopeTimer:    n.envelopeTimer,
+		LFSR:             n.lfsr,
+		LFSRWidth:        n.lfsrWidth,
+		ClockShift:       n.clockShift,
+		DivisorCode:      n.divisorCode,
+		PhaseTimer:       n.phaseTimer,
+		NR41:             n.nr41,
+		NR42:             n.nr42,
+		NR43:             n.nr43,
+		NR44:             n.nr44,
+	}
+}
+
+// setState restores saved channel state.
+func (n *NoiseChannel) setState(s noiseState) {
+	n.enabled = s.Enabled
+	n.dacEnabled = s.DACEnabled
+	n.lengthCounter = s.LengthCounter
+	n.lengthEnabled = s.LengthEnabled
+	n.envelopeVolume = s.EnvelopeVolume
+	n.envelopeInitial = s.EnvelopeInitial
+	n.envelopeIncrease = s.EnvelopeIncrease
+	n.envelopePeriod = s.EnvelopePeriod
+	n.envelopeTimer = s.EnvelopeTimer
+	n.lfsr = s.LFSR
+	n.lfsrWidth = s.LFSRWidth
+	n.clockShift = s.ClockShift
+	n.divisorCode = s.DivisorCode
+	n.phaseTimer = s.PhaseTimer
+	n.nr41, n.nr42, n.nr43, n.nr44 = s.NR41, s.NR42, s.NR43, s.NR44
+}
@@ -18,6 +18,10 @@ type WaveChannel struct {
 	// Wave RAM (32 4-bit samples)
 	waveRAM [16]uint8
 
+	// accessConflicts restricts CPU wave RAM access to the byte being played
+	// while the channel is on (kept across Reset)
+	accessConflicts bool
+
 	// Register values
 	nr30, nr31, nr32, nr33, nr34 uint8
 }
@@ -107,6 +111,14 @@ func (w *WaveChannel) IsEnabled() bool {
 	return w.enabled
 }
 
+// PowerOff clears the channel when the APU is powered off via NR52.
+// Unlike Reset, the length counter is kept, as on DMG.
+func (w *WaveChannel) PowerOff() {
+	length := w.lengthCounter
+	w.Reset()
+	w.lengthCounter = length
+}
+
 // Reset resets the channel to initial state.
 func (w *WaveChannel) Reset() {
 	w.enabled = false
@@ -154,6 +166,11 @@ func (w *WaveChannel) WriteNR31(value uint8) {
 	w.lengthCounter = 256 - uint16(value)
 }
 
+// WriteLength writes the length load of NR31 while the APU is disabled.
+func (w *WaveChannel) WriteLength(value uint8) {
+	w.lengthCounter = 256 - uint16(value)
+}
+
 // ReadNR32 reads NR32 (output level).
 func (w *WaveChannel) ReadNR32() uint8 {
 	return w.nr32 | 0x9F // Bits 7, 4-0 unused
@@ -193,12 +210,28 @@ func (w *WaveChannel) WriteNR34(value uint8) {
 	}
 }
 
+// SetAccessConflicts selects whether CPU access to wave RAM conflicts with
+// playback. When enabled, while the channel is on every read and write
+// reaches the byte holding the current sample, whatever the address, as on
+// DMG hardware. When disabled, wave RAM is always freely accessible.
+func (w *WaveChannel) SetAccessConflicts(enabled bool) {
+	w.accessConflicts = enabled
+}
+
+// waveRAMIndex returns the wave RAM byte a CPU access to offset reaches.
+func (w *WaveChannel) waveRAMIndex(offset uint16) uint16 {
+	if w.accessConflicts && w.enabled {
+		return uint16(w.wavePos / 2)
+	}
+	return offset
+}
+
 // ReadWaveRAM reads a byte from wave RAM.
 func (w *WaveChannel) ReadWaveRAM(offset uint16) uint8 {
-	return w.waveRAM[offset]
+	return w.waveRAM[w.waveRAMIndex(offset)]
 }
 
 // WriteWaveRAM writes a byte to wave RAM.
 func (w *WaveChannel) WriteWaveRAM(offset uint16, value uint8) {
-	w.waveRAM[offset] = value
+	w.waveRAM[w.waveRAMIndex(offset)] = value
 }
@@ -98,6 +98,55 @@ func TestWaveChannel_WaveRAM(t *testing.T) {
 	}
 }
 
+func TestWaveChannel_WaveRAMAccessConflicts(t *testing.T) {
+	w := NewWaveChannel()
+	w.SetAccessConflicts(true)
+	for i := uint16(0); i < 16; i++ {
+		w.WriteWaveRAM(i, uint8(i)<<4|uint8(i)) //nolint:gosec // G115: i < 16
+	}
+
+	// Stopped: access is free
+	if got := w.ReadWaveRAM(5); got != 0x55 {
+		t.Errorf("stopped: ReadWaveRAM(5) = 0x%02X, want 0x55", got)
+	}
+
+	w.WriteNR30(0x80) // Enable DAC
+	w.WriteNR34(0x80) // Trigger
+	w.wavePos = 6     // Playing a sample of byte 3
+
+	// Playing: every address reaches the current byte
+	if got := w.ReadWaveRAM(5); got != 0x33 {
+		t.Errorf("playing: ReadWaveRAM(5) = 0x%02X, want 0x33", got)
+	}
+	w.WriteWaveRAM(9, 0xAB)
+	if w.waveRAM[3] != 0xAB || w.waveRAM[9] != 0x99 {
+		t.Errorf("playing: write to 9 changed [3] = 0x%02X, [9] = 0x%02X, want 0xAB, 0x99",
+			w.waveRAM[3], w.waveRAM[9])
+	}
+
+	// Stopping the channel frees access again
+	w.WriteNR30(0x00)
+	if got := w.ReadWaveRAM(9); got != 0x99 {
+		t.Errorf("stopped again: ReadWaveRAM(9) = 0x%02X, want 0x99", got)
+	}
+}
+
+func TestWaveChannel_WaveRAMFreeAccess(t *testing.T) {
+	w := NewWaveChannel()
+	w.WriteNR30(0x80) // Enable DAC
+	w.WriteNR34(0x80) // Trigger
+	w.wavePos = 6
+
+	// Without access conflicts, playback does not restrict access
+	w.WriteWaveRAM(9, 0xAB)
+	if got := w.ReadWaveRAM(9); got != 0xAB {
+		t.Errorf("ReadWaveRAM(9) = 0x%02X, want 0xAB", got)
+	}
+	if w.waveRAM[3] != 0x00 {
+		t.Errorf("waveRAM[3] = 0x%02X, want unchanged 0x00", w.waveRAM[3])
+	}
+}
+
 func TestWaveChannel_FrequencyChange(t *testing.T) {
 	w := NewWaveChannel()
 
@@ -3,6 +3,8 @@ package cartridge
 import (
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 )
 
 // Cartridge represents a Game Boy cartridge with ROM and optional RAM.
@@ -24,6 +26,55 @@ type Cartridge interface {
 
 	// SetRAM loads save data into the cartridge RAM (if battery-backed)
 	SetRAM(data []byte) error
+
+	// Reset restores the banking registers to their power-on state, keeping
+	// the contents of RAM
+	Reset()
+
+	// SaveState writes RAM and the banking registers for a save state
+	SaveState(w io.Writer) error
+
+	// LoadState restores state written by SaveState
+	LoadState(r io.Reader) error
+}
+
+// BankReader is implemented by cartridges that can read any ROM or RAM bank
+// directly, regardless of the current banking state. It is intended for
+// debugging tools and tests.
+type BankReader interface {
+	// ReadROMBank reads offset (0x0000-0x3FFF) of 16 KiB ROM bank bank.
+	ReadROMBank(bank int, offset uint16) uint8
+
+	// ReadRAMBank reads offset (0x0000-0x1FFF) of 8 KiB RAM bank bank.
+	ReadRAMBank(bank int, offset uint16) uint8
+}
+
+// RAMTracker is implemented by cartridges that track whether their external
+// RAM has been written since it was last read for saving. Callers can use it
+// to skip rewriting unchanged save files.
+type RAMTracker interface {
+	// RAMDirty returns true if external RAM has been written since the last
+	// call to GetRAM.
+	RAMDirty() bool
+}
+
+// Bank sizes used by BankReader.
+const (
+	romBankSize = 0x4000
+	ramBankSize = 0x2000
+)
+
+// readBank reads offset within bank of data, split into banks of size bytes.
+// Out-of-range banks and offsets read as 0xFF.
+func readBank(data []byte, size, bank int, offset uint16) uint8 {
+	if bank < 0 || int(offset) >= size {
+		return 0xFF
+	}
+	index := bank*size + int(offset)
+	if index >= len(data) {
+		return 0xFF
+	}
+	return data[index]
 }
 
 // ErrInvalidCartridgeType indicates an unsupported or unknown cartridge type.
@@ -35,10 +86,36 @@ var ErrROMSizeMismatch = errors.New("ROM size does not match header")
 // ErrROMTooLarge indicates the ROM size exceeds the maximum allowed size.
 var ErrROMTooLarge = errors.New("ROM size exceeds maximum allowed size of 8 MiB")
 
+// Options configures optional cartridge loading behavior.
+type Options struct {
+	// MBC1M forces MBC1 multicart wiring, overriding heuristic detection.
+	MBC1M bool
+
+	// AllowFallback loads unsupported cartridge types as the closest supported
+	// controller (see FallbackType) instead of returning ErrInvalidCartridgeType.
+	// Games may not run correctly.
+	AllowFallback bool
+
+	// IgnoreHeaderChecksum loads ROMs with an invalid header checksum, logging
+	// a warning instead of returning ErrInvalidHeaderChecksum. Hardware does
+	// not require a valid checksum once past the boot ROM.
+	IgnoreHeaderChecksum bool
+
+	// PadROM loads ROMs whose size does not match the header: undersized
+	// dumps are padded with 0xFF to the declared size and oversized ones are
+	// truncated, with a warning, instead of returning ErrROMSizeMismatch.
+	PadROM bool
+}
+
 // New creates a new cartridge from ROM data.
 // It automatically detects the cartridge type from the header and creates
 // the appropriate implementation (ROM-only, MBC1, MBC3, MBC5, etc.).
 func New(rom []byte) (Cartridge, error) {
+	return NewWithOptions(rom, Options{})
+}
+
+// NewWithOptions creates a new cartridge from ROM data using the given options.
+func NewWithOptions(rom []byte, opts Options) (Cartridge, error) {
 	// Check maximum ROM size (8 MiB)
 	const maxROMSize = 8 * 1024 * 1024 // 8 MiB
 	if len(rom) > maxROMSize {
@@ -46,30 +123,107 @@ func New(rom []byte) (Cartridge, error) {
 	}
 
 	// Parse header
-	header, err := ParseHeader(rom)
+	header, err := parseHeader(rom)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse header: %w", err)
 	}
+	if !header.VerifyHeaderChecksum(rom) {
+		if !opts.IgnoreHeaderChecksum {
+			return nil, fmt.Errorf("failed to parse header: %w", ErrInvalidHeaderChecksum)
+		}
+		slog.Warn("invalid header checksum; loading anyway", "title", header.GetTitle(),
+			"checksum", fmt.Sprintf("0x%02X", header.HeaderChecksum))
+	}
 
 	// Verify ROM size matches header
 	expectedSize := header.GetROMSizeBytes()
-	if len(rom) < expectedSize {
+	switch {
+	case opts.PadROM && len(rom) < expectedSize:
+		slog.Warn("ROM smaller than header size; padding with 0xFF", "size", len(rom), "expected", expectedSize)
+		rom = padROM(rom, expectedSize)
+	case opts.PadROM && expectedSize > 0 && len(rom) > expectedSize:
+		slog.Warn("ROM larger than header size; truncating", "size", len(rom), "expected", expectedSize)
+		rom = rom[:expectedSize]
+	case len(rom) < expectedSize:
 		return nil, fmt.Errorf("%w: expected %d bytes, got %d",
 			ErrROMSizeMismatch, expectedSize, len(rom))
 	}
 
+	// Many commercial games have incorrect global checksums, so only warn
+	if !header.VerifyGlobalChecksum(rom) {
+		slog.Warn("invalid global checksum", "title", header.GetTitle(),
+			"checksum", fmt.Sprintf("0x%02X%02X", header.GlobalChecksum[0], header.GlobalChecksum[1]))
+	}
+
 	// Create cartridge based on type
 	cartType := CartridgeType(header.CartridgeType)
+	if opts.AllowFallback && !cartType.Supported() {
+		cartType = FallbackType(cartType, len(rom))
+		slog.Debug("unsupported cartridge type, using fallback",
+			"type", CartridgeType(header.CartridgeType), "fallback", cartType)
+	}
 
 	switch cartType {
 	case TypeROMOnly, TypeROMRAM, TypeROMRAMBattery:
 		return newROMOnly(rom, header)
 
 	case TypeMBC1, TypeMBC1RAM, TypeMBC1RAMBattery:
-		return newMBC1(rom, header)
+		cart, err := newMBC1(rom, header)
+		if err != nil {
+			return nil, err
+		}
+		if opts.MBC1M {
+			cart.multicart = true
+		}
+		return cart, nil
+
+	case TypeMBC2, TypeMBC2Battery:
+		return newMBC2(rom, header)
+
+	case TypeMBC3TimerBattery, TypeMBC3TimerRAMBattery, TypeMBC3, TypeMBC3RAM, TypeMBC3RAMBattery:
+		return newMBC3(rom, header)
 
 	default:
 		return nil, fmt.Errorf("%w: type 0x%02X (%s)",
 			ErrInvalidCartridgeType, byte(cartType), cartType.String())
 	}
 }
+
+// padROM returns a copy of rom extended to size bytes with 0xFF, the value
+// read from unconnected ROM address lines.
+func padROM(rom []byte, size int) []byte {
+	padded := make([]byte, size)
+	copy(padded, rom)
+	for i := len(rom); i < size; i++ {
+		padded[i] = 0xFF
+	}
+	return padded
+}
+
+// Supported reports whether the cartridge type has a controller implementation.
+func (t CartridgeType) Supported() bool {
+	switch t {
+	case TypeROMOnly, TypeROMRAM, TypeROMRAMBattery,
+		TypeMBC1, TypeMBC1RAM, TypeMBC1RAMBattery,
+		TypeMBC2, TypeMBC2Battery,
+		TypeMBC3TimerBattery, TypeMBC3TimerRAMBattery, TypeMBC3, TypeMBC3RAM, TypeMBC3RAMBattery:
+		return true
+	default:
+		return false
+	}
+}
+
+// FallbackType returns the supported controller used for an unsupported
+// cartridge type when Options.AllowFallback is set: ROM-only for ROMs that
+// fit without banking, MBC1 otherwise. The cartridge keeps its RAM and
+// battery as described by its original type. Supported types are returned
+// unchanged.
+func FallbackType(t CartridgeType, romSize int) CartridgeType {
+	if t.Supported() {
+		return t
+	}
+	if romSize <= 2*romBankSize {
+		return TypeROMOnly
+	}
+	return TypeMBC1
+}
@@ -1,7 +1,9 @@
 package cartridge
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -13,16 +15,9 @@ func TestNewUnsupportedMBCTypes(t *testing.T) {
 		cartType     CartridgeType
 		expectedType byte
 	}{
-		{"MBC2", TypeMBC2, 0x05},
-		{"MBC2+Battery", TypeMBC2Battery, 0x06},
 		{"MMM01", TypeMMM01, 0x0B},
 		{"MMM01+RAM", TypeMMM01RAM, 0x0C},
 		{"MMM01+RAM+Battery", TypeMMM01RAMBattery, 0x0D},
-		{"MBC3+Timer+Battery", TypeMBC3TimerBattery, 0x0F},
-		{"MBC3+Timer+RAM+Battery", TypeMBC3TimerRAMBattery, 0x10},
-		{"MBC3", TypeMBC3, 0x11},
-		{"MBC3+RAM", TypeMBC3RAM, 0x12},
-		{"MBC3+RAM+Battery", TypeMBC3RAMBattery, 0x13},
 		{"MBC5", TypeMBC5, 0x19},
 		{"MBC5+RAM", TypeMBC5RAM, 0x1A},
 		{"MBC5+RAM+Battery", TypeMBC5RAMBattery, 0x1B},
@@ -108,6 +103,59 @@ func TestNewInvalidROMSize(t *testing.T) {
 	}
 }
 
+// TestNewPadROM verifies that PadROM loads ROMs whose size does not match
+// the header, padding undersized ones with 0xFF and truncating oversized ones.
+func TestNewPadROM(t *testing.T) {
+	// A 64 KiB MBC1 ROM truncated to 48 KiB (3 banks)
+	rom := make([]byte, 0xC000)
+	for bank := range 3 {
+		rom[bank*0x4000] = byte(bank)
+	}
+	setupMBC1Header(rom, byte(TypeMBC1), 0x00, 0x01)
+
+	if _, err := New(rom); !errors.Is(err, ErrROMSizeMismatch) {
+		t.Fatalf("New() error = %v, want ErrROMSizeMismatch", err)
+	}
+
+	cart, err := NewWithOptions(rom, Options{PadROM: true})
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+
+	cart.Write(0x2000, 0x02)
+	if got := cart.Read(0x4000); got != 0x02 {
+		t.Errorf("bank 2 at 0x4000 = 0x%02X, want 0x02 (original data)", got)
+	}
+
+	// Bank 3 lies past the original data and reads as padding
+	cart.Write(0x2000, 0x03)
+	for _, addr := range []uint16{0x4000, 0x5234, 0x7FFF} {
+		if got := cart.Read(addr); got != 0xFF {
+			t.Errorf("bank 3 at 0x%04X = 0x%02X, want 0xFF (padding)", addr, got)
+		}
+	}
+	if got := cart.(BankReader).ReadROMBank(3, 0x0000); got != 0xFF {
+		t.Errorf("ReadROMBank(3, 0) = 0x%02X, want 0xFF", got)
+	}
+
+	// The caller's slice is not modified
+	if len(rom) != 0xC000 {
+		t.Errorf("len(rom) = 0x%X, want 0xC000", len(rom))
+	}
+
+	// An oversized ROM is truncated to the declared size
+	big := make([]byte, 0x10000)
+	big[0x4000] = 0x01
+	setupMBC1Header(big, byte(TypeMBC1), 0x00, 0x00) // Declares 32 KiB
+	cart, err = NewWithOptions(big, Options{PadROM: true})
+	if err != nil {
+		t.Fatalf("NewWithOptions() oversized error = %v", err)
+	}
+	if got := cart.(BankReader).ReadROMBank(2, 0x0000); got != 0xFF {
+		t.Errorf("ReadROMBank(2, 0) after truncation = 0x%02X, want 0xFF", got)
+	}
+}
+
 // TestNewTooSmallROM verifies that loading a ROM smaller than header size fails.
 func TestNewTooSmallROM(t *testing.T) {
 	// Create a ROM that's too small to contain a valid header
@@ -179,3 +227,257 @@ func TestNewROMExactly8MiB(t *testing.T) {
 		t.Error("Expected valid cartridge for 8 MiB ROM, got nil")
 	}
 }
+
+// TestNewAllowFallback verifies that unsupported types load as the fallback
+// controller only when AllowFallback is set.
+func TestNewAllowFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		cartType CartridgeType
+		romSize  byte
+		wantType string
+		wantRAM  bool
+	}{
+		{"Small MBC5 as ROM only", TypeMBC5, 0x00, "*cartridge.ROMOnly", false},
+		{"MBC5+RAM+Battery as MBC1", TypeMBC5RAMBattery, 0x02, "*cartridge.MBC1", true},
+		{"HuC1 as MBC1", TypeHuC1RAMBattery, 0x01, "*cartridge.MBC1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom := make([]byte, 0x8000<<tt.romSize)
+			setupMBC1Header(rom, byte(tt.cartType), 0x02, tt.romSize) // 8 KiB RAM
+
+			if _, err := New(rom); !errors.Is(err, ErrInvalidCartridgeType) {
+				t.Fatalf("New() error = %v, want ErrInvalidCartridgeType", err)
+			}
+
+			cart, err := NewWithOptions(rom, Options{AllowFallback: true})
+			if err != nil {
+				t.Fatalf("NewWithOptions() error = %v", err)
+			}
+			if got := fmt.Sprintf("%T", cart); got != tt.wantType {
+				t.Errorf("cartridge type = %s, want %s", got, tt.wantType)
+			}
+			if got := cart.GetRAM() != nil; got != tt.wantRAM {
+				t.Errorf("has RAM = %v, want %v", got, tt.wantRAM)
+			}
+			if got := CartridgeType(cart.Header().CartridgeType); got != tt.cartType {
+				t.Errorf("Header().CartridgeType = %s, want %s (unchanged)", got, tt.cartType)
+			}
+		})
+	}
+}
+
+func TestNewIgnoreHeaderChecksum(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	setupMBC1Header(rom, byte(TypeROMOnly), 0x00, 0x00)
+	rom[0x014D]++ // Corrupt the header checksum
+
+	if _, err := New(rom); !errors.Is(err, ErrInvalidHeaderChecksum) {
+		t.Fatalf("New() error = %v, want ErrInvalidHeaderChecksum", err)
+	}
+
+	cart, err := NewWithOptions(rom, Options{IgnoreHeaderChecksum: true})
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+
+	// Validation is still available to callers
+	if cart.Header().VerifyHeaderChecksum(rom) {
+		t.Error("VerifyHeaderChecksum() = true, want false")
+	}
+}
+
+func TestRAMDirty(t *testing.T) {
+	tests := []struct {
+		name     string
+		cartType CartridgeType
+		ramSize  byte
+	}{
+		{"ROM+RAM+Battery", TypeROMRAMBattery, 0x02},
+		{"MBC1+RAM+Battery", TypeMBC1RAMBattery, 0x02},
+		{"MBC2+Battery", TypeMBC2Battery, 0x00},
+		{"MBC3+RAM+Battery", TypeMBC3RAMBattery, 0x02},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom := make([]byte, 0x8000)
+			setupMBC1Header(rom, byte(tt.cartType), tt.ramSize, 0x00)
+
+			cart, err := New(rom)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			tracker, ok := cart.(RAMTracker)
+			if !ok {
+				t.Fatalf("%T does not implement RAMTracker", cart)
+			}
+
+			// A session without RAM writes stays clean, even after loading a save
+			if err := cart.SetRAM([]byte{0x01, 0x02}); err != nil {
+				t.Fatalf("SetRAM() error = %v", err)
+			}
+			cart.Write(0x0000, 0x0A) // Enable RAM
+			_ = cart.Read(0xA000)
+			if tracker.RAMDirty() {
+				t.Error("RAMDirty() = true before any RAM write, want false")
+			}
+
+			cart.Write(0xA000, 0x05)
+			if !tracker.RAMDirty() {
+				t.Error("RAMDirty() = false after RAM write, want true")
+			}
+
+			_ = cart.GetRAM()
+			if tracker.RAMDirty() {
+				t.Error("RAMDirty() = true after GetRAM, want false")
+			}
+
+			// Writes while RAM is disabled are dropped and do not dirty it
+			// (ROM-only cartridges have no RAM enable register)
+			if tt.cartType != TypeROMRAMBattery {
+				cart.Write(0x0000, 0x00)
+				cart.Write(0xA000, 0x06)
+				if tracker.RAMDirty() {
+					t.Error("RAMDirty() = true after write to disabled RAM, want false")
+				}
+			}
+		})
+	}
+}
+
+func TestCartridgeReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		cartType CartridgeType
+		ramSize  byte
+		bankAddr uint16 // ROM bank register address
+	}{
+		{"MBC1+RAM+Battery", TypeMBC1RAMBattery, 0x03, 0x2000},
+		{"MBC2+Battery", TypeMBC2Battery, 0x00, 0x2100},
+		{"MBC3+RAM+Battery", TypeMBC3RAMBattery, 0x03, 0x2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 64 KiB ROM (4 banks), each bank starting with its number
+			rom := make([]byte, 0x10000)
+			for bank := range 4 {
+				rom[bank*0x4000] = byte(bank)
+			}
+			setupMBC1Header(rom, byte(tt.cartType), tt.ramSize, 0x01)
+
+			cart, err := New(rom)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			cart.Write(0x0000, 0x0A) // Enable RAM
+			cart.Write(0xA000, 0x05)
+			cart.Write(tt.bankAddr, 0x03)
+			if tt.cartType != TypeMBC2Battery {
+				cart.Write(0x4000, 0x01) // RAM bank 1
+				cart.Write(0x6000, 0x01) // MBC1 advanced mode / MBC3 latch
+			}
+			if got := cart.Read(0x4000); got != 0x03 {
+				t.Fatalf("Setup failed: bank at 0x4000 = %d, want 3", got)
+			}
+
+			cart.Reset()
+
+			if got := cart.Read(0x4000); got != 0x01 {
+				t.Errorf("after Reset, bank at 0x4000 = %d, want 1", got)
+			}
+			if got := cart.Read(0xA000); got != 0xFF {
+				t.Errorf("after Reset, RAM read = 0x%02X, want 0xFF (disabled)", got)
+			}
+
+			// RAM contents survive, in bank 0
+			cart.Write(0x0000, 0x0A)
+			if got := cart.Read(0xA000) & 0x0F; got != 0x05 {
+				t.Errorf("after Reset, RAM bank 0 = 0x%02X, want 0x05", got)
+			}
+		})
+	}
+}
+
+func TestCartridgeState(t *testing.T) {
+	tests := []struct {
+		name     string
+		cartType CartridgeType
+		ramSize  byte
+		bankAddr uint16 // ROM bank register address
+	}{
+		{"ROM+RAM", TypeROMRAM, 0x02, 0},
+		{"MBC1+RAM+Battery", TypeMBC1RAMBattery, 0x03, 0x2000},
+		{"MBC2+Battery", TypeMBC2Battery, 0x00, 0x2100},
+		{"MBC3+Timer+RAM+Battery", TypeMBC3TimerRAMBattery, 0x03, 0x2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rom := make([]byte, 0x10000)
+			for bank := range 4 {
+				rom[bank*0x4000] = byte(bank)
+			}
+			setupMBC1Header(rom, byte(tt.cartType), tt.ramSize, 0x01)
+			newCart := func() Cartridge {
+				cart, err := New(rom)
+				if err != nil {
+					t.Fatalf("New() error = %v", err)
+				}
+				return cart
+			}
+
+			cart := newCart()
+			cart.Write(0x0000, 0x0A) // Enable RAM
+			cart.Write(0xA000, 0x05)
+			if tt.bankAddr != 0 {
+				cart.Write(tt.bankAddr, 0x03)
+			}
+
+			var buf bytes.Buffer
+			if err := cart.SaveState(&buf); err != nil {
+				t.Fatalf("SaveState() error = %v", err)
+			}
+
+			restored := newCart()
+			if err := restored.LoadState(&buf); err != nil {
+				t.Fatalf("LoadState() error = %v", err)
+			}
+			if got, want := restored.Read(0x4000), cart.Read(0x4000); got != want {
+				t.Errorf("bank at 0x4000 = %d, want %d", got, want)
+			}
+			if got := restored.Read(0xA000) & 0x0F; got != 0x05 {
+				t.Errorf("RAM = 0x%02X, want 0x05 (restored and enabled)", got)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("LoadState() left %d bytes unread", buf.Len())
+			}
+		})
+	}
+}
+
+func TestCartridgeStateRAMSizeMismatch(t *testing.T) {
+	rom := make([]byte, 0x10000)
+	setupMBC1Header(rom, byte(TypeMBC1RAMBattery), 0x03, 0x01) // 32 KiB RAM
+	cart, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := cart.SaveState(&buf); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	setupMBC1Header(rom, byte(TypeMBC1RAMBattery), 0x02, 0x01) // 8 KiB RAM
+	smaller, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := smaller.LoadState(&buf); !errors.Is(err, ErrStateRAMSize) {
+		t.Errorf("LoadState() error = %v, want %v", err, ErrStateRAMSize)
+	}
+}
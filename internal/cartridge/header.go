@@ -75,6 +75,14 @@ type Header struct {
 	GlobalChecksum [2]byte
 }
 
+// nintendoLogo is the logo bitmap every licensed cartridge stores at 0x0104-0x0133.
+var nintendoLogo = [48]byte{
+	0xCE, 0xED, 0x66, 0x66, 0xCC, 0x0D, 0x00, 0x0B, 0x03, 0x73, 0x00, 0x83,
+	0x00, 0x0C, 0x00, 0x0D, 0x00, 0x08, 0x11, 0x1F, 0x88, 0x89, 0x00, 0x0E,
+	0xDC, 0xCC, 0x6E, 0xE6, 0xDD, 0xDD, 0xD9, 0x99, 0xBB, 0xBB, 0x67, 0x63,
+	0x6E, 0x0E, 0xEC, 0xCC, 0xDD, 0xDC, 0x99, 0x9F, 0xBB, 0xB9, 0x33, 0x3E,
+}
+
 // CartridgeType represents the type of cartridge and MBC.
 //
 //nolint:revive // CartridgeType is intentionally explicit for clarity
@@ -255,6 +263,11 @@ func (h *Header) GetRAMSizeBytes() int {
 	return banks * 8192 // 8 KiB per bank
 }
 
+// IsCGBOnly reports whether the cartridge requires a Game Boy Color (CGB flag 0xC0).
+func (h *Header) IsCGBOnly() bool {
+	return h.CGBFlag == 0xC0
+}
+
 // GetTitle returns the cartridge title as a string, trimmed of null bytes.
 func (h *Header) GetTitle() string {
 	// Find the first null byte
@@ -275,7 +288,24 @@ var ErrInvalidROMSize = errors.New("ROM too small: must be at least 336 bytes (0
 var ErrInvalidHeaderChecksum = errors.New("invalid header checksum")
 
 // ParseHeader parses the cartridge header from ROM data.
+// It returns ErrInvalidHeaderChecksum if the header checksum does not match.
 func ParseHeader(rom []byte) (*Header, error) {
+	h, err := parseHeader(rom)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify header checksum
+	if !h.VerifyHeaderChecksum(rom) {
+		return nil, ErrInvalidHeaderChecksum
+	}
+
+	return h, nil
+}
+
+// parseHeader parses the cartridge header from ROM data without verifying
+// the header checksum.
+func parseHeader(rom []byte) (*Header, error) {
 	if len(rom) < 0x0150 {
 		return nil, fmt.Errorf("%w: got %d bytes", ErrInvalidROMSize, len(rom))
 	}
@@ -327,11 +357,6 @@ func ParseHeader(rom []byte) (*Header, error) {
 	// Global checksum (0x014E-0x014F)
 	copy(h.GlobalChecksum[:], rom[0x014E:0x0150])
 
-	// Verify header checksum
-	if !h.VerifyHeaderChecksum(rom) {
-		return nil, ErrInvalidHeaderChecksum
-	}
-
 	return h, nil
 }
 
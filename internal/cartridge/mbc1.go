@@ -1,5 +1,7 @@
 package cartridge
 
+import "bytes"
+
 // MBC1 represents a cartridge with MBC1 (Memory Bank Controller 1).
 // MBC1 is the most common MBC type, supporting up to 2 MiB of ROM and 32 KiB of RAM.
 //
@@ -13,6 +15,10 @@ package cartridge
 // - 0x2000-0x3FFF: ROM Bank Number (lower 5 bits)
 // - 0x4000-0x5FFF: RAM Bank Number / ROM Bank Number (upper 2 bits)
 // - 0x6000-0x7FFF: Banking Mode Select (0 = simple ROM, 1 = advanced RAM/ROM).
+//
+// MBC1M (multicart) boards wire only 4 bits of the ROM bank register, so the
+// secondary bank register (0x4000-0x5FFF) supplies ROM bank bits 4-5 instead
+// of bits 5-6. Each game therefore occupies a 256 KiB (16 bank) chunk.
 type MBC1 struct {
 	header *Header
 	rom    []byte
@@ -23,6 +29,10 @@ type MBC1 struct {
 	romBank     uint8 // ROM bank number (0x2000-0x3FFF), 5 bits
 	ramBank     uint8 // RAM bank number (0x4000-0x5FFF), 2 bits
 	bankingMode uint8 // Banking mode (0x6000-0x7FFF): 0 = simple, 1 = advanced
+	ramDirty    bool  // RAM written since the last GetRAM
+
+	// multicart is true for MBC1M wiring (secondary register feeds bank bit 4)
+	multicart bool
 
 	// Calculated values
 	numROMBanks int
@@ -34,6 +44,7 @@ type MBC1 struct {
 //nolint:unparam // Error return is for future expansion and interface consistency
 func newMBC1(rom []byte, header *Header) (*MBC1, error) {
 	cart := &MBC1{
+		multicart:   isMBC1Multicart(rom),
 		header:      header,
 		rom:         rom,
 		ramEnabled:  false,
@@ -64,7 +75,7 @@ func (c *MBC1) Read(addr uint16) uint8 {
 		bankNumber := 0
 		if c.bankingMode == 1 {
 			// Use upper 2 bits from ramBank as upper bits of ROM bank
-			bankNumber = int(c.ramBank) << 5
+			bankNumber = int(c.ramBank) << c.upperBankShift()
 		}
 
 		// Ensure bank is within bounds
@@ -80,12 +91,15 @@ func (c *MBC1) Read(addr uint16) uint8 {
 
 	// ROM Bank 01-7F (0x4000-0x7FFF)
 	case addr < 0x8000:
-		// Combine lower 5 bits (romBank) with upper 2 bits (ramBank)
-		bankNumber := int(c.romBank) | (int(c.ramBank) << 5)
+		// Combine lower bits (romBank) with upper 2 bits (ramBank)
+		shift := c.upperBankShift()
+		bankNumber := int(c.romBank)&(1<<shift-1) | (int(c.ramBank) << shift)
 
 		// Handle special case: banks 0x00, 0x20, 0x40, 0x60 are not accessible here
-		// If lower 5 bits are 0, use 1 instead
-		if (bankNumber & 0x1F) == 0 {
+		// If lower 5 bits are 0, use 1 instead.
+		// On MBC1M the zero check still applies to the full 5-bit register
+		// (handled on write), so each game's bank 0 can be mapped here.
+		if !c.multicart && (bankNumber&0x1F) == 0 {
 			bankNumber |= 0x01
 		}
 
@@ -102,22 +116,7 @@ func (c *MBC1) Read(addr uint16) uint8 {
 
 	// External RAM (0xA000-0xBFFF)
 	case addr >= 0xA000 && addr < 0xC000:
-		if !c.ramEnabled || c.ram == nil {
-			return 0xFF
-		}
-
-		// In advanced banking mode, ramBank selects RAM bank
-		// In simple mode, ramBank is always 0
-		bankNumber := 0
-		if c.bankingMode == 1 && c.numRAMBanks > 1 {
-			bankNumber = int(c.ramBank)
-			if bankNumber >= c.numRAMBanks {
-				bankNumber %= c.numRAMBanks
-			}
-		}
-
-		offset := bankNumber*0x2000 + int(addr-0xA000)
-		if offset < len(c.ram) {
+		if offset, ok := c.ramOffset(addr); ok {
 			return c.ram[offset]
 		}
 		return 0xFF
@@ -157,25 +156,82 @@ func (c *MBC1) Write(addr uint16, value uint8) {
 
 	// External RAM (0xA000-0xBFFF)
 	case addr >= 0xA000 && addr < 0xC000:
-		if !c.ramEnabled || c.ram == nil {
-			return
+		if offset, ok := c.ramOffset(addr); ok {
+			c.ram[offset] = value
+			c.ramDirty = true
 		}
+	}
+}
 
-		// In advanced banking mode, ramBank selects RAM bank
-		// In simple mode, ramBank is always 0
-		bankNumber := 0
-		if c.bankingMode == 1 && c.numRAMBanks > 1 {
-			bankNumber = int(c.ramBank)
-			if bankNumber >= c.numRAMBanks {
-				bankNumber %= c.numRAMBanks
-			}
+// ramOffset translates an external RAM address (0xA000-0xBFFF) into an offset
+// into the allocated RAM. It returns false if RAM is disabled or absent, or if
+// the offset falls outside the allocated RAM (e.g. above 2 KiB on carts
+// declaring RAMSize 0x01).
+func (c *MBC1) ramOffset(addr uint16) (int, bool) {
+	if !c.ramEnabled || c.ram == nil {
+		return 0, false
+	}
+
+	// In advanced banking mode, ramBank selects RAM bank
+	// In simple mode, ramBank is always 0
+	bankNumber := 0
+	if c.bankingMode == 1 && c.numRAMBanks > 1 {
+		bankNumber = int(c.ramBank)
+		if bankNumber >= c.numRAMBanks {
+			bankNumber %= c.numRAMBanks
 		}
+	}
 
-		offset := bankNumber*0x2000 + int(addr-0xA000)
-		if offset < len(c.ram) {
-			c.ram[offset] = value
+	offset := bankNumber*0x2000 + int(addr-0xA000)
+	return offset, offset < len(c.ram)
+}
+
+// upperBankShift returns the ROM bank bit the secondary bank register starts at.
+// Standard MBC1 uses bits 5-6; MBC1M multicarts use bits 4-5.
+func (c *MBC1) upperBankShift() uint {
+	if c.multicart {
+		return 4
+	}
+	return 5
+}
+
+// IsMulticart returns true if the cartridge uses MBC1M multicart wiring.
+func (c *MBC1) IsMulticart() bool {
+	return c.multicart
+}
+
+// isMBC1Multicart heuristically detects MBC1M multicart ROMs.
+// Multicarts are at least 1 MiB and repeat the Nintendo logo at the start of
+// each 256 KiB game chunk, so a logo in any chunk after the first identifies one.
+func isMBC1Multicart(rom []byte) bool {
+	const (
+		minMulticartSize = 1024 * 1024 // 1 MiB
+		gameSize         = 0x40000     // 256 KiB per game
+		logoStart        = 0x0104
+		logoEnd          = 0x0134
+	)
+
+	if len(rom) < minMulticartSize {
+		return false
+	}
+
+	logos := 0
+	for base := 0; base+logoEnd <= len(rom) && base < minMulticartSize; base += gameSize {
+		if bytes.Equal(rom[base+logoStart:base+logoEnd], nintendoLogo[:]) {
+			logos++
 		}
 	}
+	return logos > 1
+}
+
+// ReadROMBank reads a byte from any ROM bank without changing the banking state.
+func (c *MBC1) ReadROMBank(bank int, offset uint16) uint8 {
+	return readBank(c.rom, romBankSize, bank, offset)
+}
+
+// ReadRAMBank reads a byte from any RAM bank without changing the banking state.
+func (c *MBC1) ReadRAMBank(bank int, offset uint16) uint8 {
+	return readBank(c.ram, ramBankSize, bank, offset)
 }
 
 // Header returns the cartridge header.
@@ -183,6 +239,14 @@ func (c *MBC1) Header() *Header {
 	return c.header
 }
 
+// Reset restores the banking registers to their power-on state. RAM is kept.
+func (c *MBC1) Reset() {
+	c.ramEnabled = false
+	c.romBank = 1
+	c.ramBank = 0
+	c.bankingMode = 0
+}
+
 // HasBattery returns true if the cartridge has battery-backed RAM.
 func (c *MBC1) HasBattery() bool {
 	return CartridgeType(c.header.CartridgeType).HasBattery()
@@ -193,12 +257,18 @@ func (c *MBC1) GetRAM() []byte {
 	if c.ram == nil {
 		return nil
 	}
+	c.ramDirty = false
 	// Return a copy to prevent external modification
 	ramCopy := make([]byte, len(c.ram))
 	copy(ramCopy, c.ram)
 	return ramCopy
 }
 
+// RAMDirty returns true if RAM has been written since the last GetRAM.
+func (c *MBC1) RAMDirty() bool {
+	return c.ramDirty
+}
+
 // SetRAM loads save data into the cartridge RAM.
 func (c *MBC1) SetRAM(data []byte) error {
 	if c.ram == nil {
@@ -6,8 +6,6 @@ import (
 
 // setupMBC1Header sets up a minimal header and recalculates checksum.
 // Call this after modifying any header fields (like ROM size).
-//
-//nolint:unparam // cartType parameter kept for potential future test variations
 func setupMBC1Header(rom []byte, cartType, ramSize, romSize byte) {
 	setupMinimalHeader(rom, cartType, ramSize)
 	rom[0x0148] = romSize
@@ -196,6 +194,50 @@ func TestMBC1RAMBanking(t *testing.T) {
 	}
 }
 
+func TestMBC12KiBRAM(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	setupMBC1Header(rom, 0x03, 0x01, 0x00) // MBC1+RAM+Battery, 2 KiB RAM
+
+	header, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+	cart, err := newMBC1(rom, header)
+	if err != nil {
+		t.Fatalf("newMBC1() error = %v", err)
+	}
+
+	cart.Write(0x0000, 0x0A) // Enable RAM
+
+	for _, mode := range []uint8{0, 1} {
+		cart.Write(0x6000, mode)
+		cart.Write(0x4000, 0x03) // RAM bank 3 does not exist on a 2 KiB cart
+
+		// Writes within 0xA000-0xA7FF persist
+		cart.Write(0xA000, 0x11+mode)
+		cart.Write(0xA7FF, 0x22+mode)
+		if got := cart.Read(0xA000); got != 0x11+mode {
+			t.Errorf("mode %d: Read(0xA000) = 0x%02X, want 0x%02X", mode, got, 0x11+mode)
+		}
+		if got := cart.Read(0xA7FF); got != 0x22+mode {
+			t.Errorf("mode %d: Read(0xA7FF) = 0x%02X, want 0x%02X", mode, got, 0x22+mode)
+		}
+
+		// Reads above the 2 KiB boundary return 0xFF
+		cart.Write(0xA800, 0x33)
+		if got := cart.Read(0xA800); got != 0xFF {
+			t.Errorf("mode %d: Read(0xA800) = 0x%02X, want 0xFF", mode, got)
+		}
+		if got := cart.Read(0xBFFF); got != 0xFF {
+			t.Errorf("mode %d: Read(0xBFFF) = 0x%02X, want 0xFF", mode, got)
+		}
+	}
+
+	if got := len(cart.GetRAM()); got != 2048 {
+		t.Errorf("len(GetRAM()) = %d, want 2048", got)
+	}
+}
+
 func TestMBC1AdvancedROMBanking(t *testing.T) {
 	// Create a 2 MiB ROM (128 banks) to test upper bits
 	rom := make([]byte, 2*1024*1024)
@@ -248,6 +290,88 @@ func TestMBC1AdvancedROMBanking(t *testing.T) {
 	}
 }
 
+// TestMBC1AdvancedModeSingleRAMBank tests that on a large ROM with only 8 KiB
+// of RAM, advanced mode uses the secondary register for ROM banking only and
+// RAM stays on bank 0.
+func TestMBC1AdvancedModeSingleRAMBank(t *testing.T) {
+	rom := make([]byte, 1024*1024)
+	rom[0x80000] = 0x20 // Bank 0x20
+	rom[0x84000] = 0x21 // Bank 0x21
+
+	setupMBC1Header(rom, 0x03, 0x02, 0x05) // MBC1+RAM+Battery, 8 KiB RAM, 1 MiB
+
+	header, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+	cart, err := newMBC1(rom, header)
+	if err != nil {
+		t.Fatalf("newMBC1() error = %v", err)
+	}
+
+	cart.Write(0x0000, 0x0A) // Enable RAM
+	cart.Write(0xA000, 0x11)
+	cart.Write(0xBFFF, 0x22)
+
+	cart.Write(0x6000, 0x01) // Advanced mode
+	cart.Write(0x4000, 0x01) // Secondary register = 1
+
+	// ROM banking uses the secondary register
+	if got := cart.Read(0x0000); got != 0x20 {
+		t.Errorf("Read(0x0000) = 0x%02X, want 0x20", got)
+	}
+	if got := cart.Read(0x4000); got != 0x21 {
+		t.Errorf("Read(0x4000) = 0x%02X, want 0x21", got)
+	}
+
+	// RAM is unaffected
+	if got := cart.Read(0xA000); got != 0x11 {
+		t.Errorf("Read(0xA000) = 0x%02X, want 0x11 (RAM bank 0)", got)
+	}
+	if got := cart.Read(0xBFFF); got != 0x22 {
+		t.Errorf("Read(0xBFFF) = 0x%02X, want 0x22 (RAM bank 0)", got)
+	}
+
+	cart.Write(0xA001, 0x33)
+	if got := cart.ReadRAMBank(0, 0x0001); got != 0x33 {
+		t.Errorf("RAM bank 0 offset 1 = 0x%02X, want 0x33", got)
+	}
+}
+
+// TestMBC1SimpleModeLocksRAMBank0 tests that simple mode always accesses RAM
+// bank 0, whatever the secondary register holds.
+func TestMBC1SimpleModeLocksRAMBank0(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	setupMinimalHeader(rom, 0x03, 0x03) // MBC1+RAM+Battery, 32 KiB (4 banks)
+
+	header, _ := ParseHeader(rom)
+	cart, _ := newMBC1(rom, header)
+
+	cart.Write(0x0000, 0x0A) // Enable RAM
+
+	cart.Write(0x4000, 0x02) // Secondary register = 2, ignored for RAM in simple mode
+	cart.Write(0xA000, 0x11)
+	if got := cart.ReadRAMBank(0, 0x0000); got != 0x11 {
+		t.Errorf("RAM bank 0 = 0x%02X, want 0x11", got)
+	}
+	if got := cart.ReadRAMBank(2, 0x0000); got != 0x00 {
+		t.Errorf("RAM bank 2 = 0x%02X, want 0x00 (untouched)", got)
+	}
+
+	// Advanced mode maps bank 2
+	cart.Write(0x6000, 0x01)
+	cart.Write(0xA000, 0x22)
+	if got := cart.ReadRAMBank(2, 0x0000); got != 0x22 {
+		t.Errorf("RAM bank 2 after advanced-mode write = 0x%02X, want 0x22", got)
+	}
+
+	// Back in simple mode, bank 0 is visible again
+	cart.Write(0x6000, 0x00)
+	if got := cart.Read(0xA000); got != 0x11 {
+		t.Errorf("Read(0xA000) back in simple mode = 0x%02X, want 0x11", got)
+	}
+}
+
 func TestMBC1HasBattery(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -418,3 +542,172 @@ func TestMBC1BankMasking(t *testing.T) {
 		t.Errorf("Bank wrapping: bank 6 should wrap to bank 2, got 0x%02X, want 0x02", got)
 	}
 }
+
+// newMBC1MulticartROM creates a 1 MiB MBC1M ROM with four 256 KiB games.
+// The first byte of every bank holds its bank number.
+func newMBC1MulticartROM() []byte {
+	rom := make([]byte, 1024*1024)
+	for bank := range 64 {
+		rom[bank*0x4000] = byte(bank)
+	}
+
+	// Each game starts with its own header, including the Nintendo logo
+	for game := range 4 {
+		copy(rom[game*0x40000+0x0104:], nintendoLogo[:])
+	}
+
+	setupMBC1Header(rom, 0x01, 0x00, 0x05) // MBC1, no RAM, 1 MiB
+	return rom
+}
+
+func TestMBC1MulticartDetection(t *testing.T) {
+	rom := newMBC1MulticartROM()
+	if !isMBC1Multicart(rom) {
+		t.Error("isMBC1Multicart() = false for ROM with logos in every game chunk")
+	}
+
+	// A single logo is a standard MBC1 cartridge
+	clear(rom[0x40104:0x40134])
+	clear(rom[0x80104:0x80134])
+	clear(rom[0xC0104:0xC0134])
+	if isMBC1Multicart(rom) {
+		t.Error("isMBC1Multicart() = true for ROM with a single logo")
+	}
+
+	// ROMs below 1 MiB are never multicarts
+	small := make([]byte, 512*1024)
+	copy(small[0x0104:], nintendoLogo[:])
+	copy(small[0x40104:], nintendoLogo[:])
+	if isMBC1Multicart(small) {
+		t.Error("isMBC1Multicart() = true for 512 KiB ROM")
+	}
+}
+
+func TestMBC1MulticartBanking(t *testing.T) {
+	rom := newMBC1MulticartROM()
+
+	cart, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	mbc, ok := cart.(*MBC1)
+	if !ok {
+		t.Fatalf("New() returned %T, want *MBC1", cart)
+	}
+	if !mbc.IsMulticart() {
+		t.Fatal("IsMulticart() = false, want true")
+	}
+
+	// Advanced mode: secondary register selects the game (256 KiB chunk)
+	mbc.Write(0x6000, 0x01)
+	for game := range uint8(4) {
+		mbc.Write(0x4000, game)
+		mbc.Write(0x2000, 0x01)
+
+		wantBank0 := game << 4
+		if got := mbc.Read(0x0000); got != wantBank0 {
+			t.Errorf("game %d: bank 0 area = 0x%02X, want 0x%02X", game, got, wantBank0)
+		}
+		if got := mbc.Read(0x4000); got != wantBank0|0x01 {
+			t.Errorf("game %d: bank 1 area = 0x%02X, want 0x%02X", game, got, wantBank0|0x01)
+		}
+	}
+
+	// Only 4 bits of the ROM bank register are wired: bank 0x1F within game 1 is 0x1F
+	mbc.Write(0x4000, 0x01)
+	mbc.Write(0x2000, 0x1F)
+	if got := mbc.Read(0x4000); got != 0x1F {
+		t.Errorf("bank register 0x1F in game 1 = 0x%02X, want 0x1F", got)
+	}
+
+	// Writing 0x10 has non-zero 5 bits, so the game's bank 0 is mapped at 0x4000
+	mbc.Write(0x4000, 0x02)
+	mbc.Write(0x2000, 0x10)
+	if got := mbc.Read(0x4000); got != 0x20 {
+		t.Errorf("bank register 0x10 in game 2 = 0x%02X, want 0x20", got)
+	}
+}
+
+func TestMBC1MulticartOption(t *testing.T) {
+	// Standard MBC1 ROM without repeated logos
+	rom := make([]byte, 1024*1024)
+	for bank := range 64 {
+		rom[bank*0x4000] = byte(bank)
+	}
+	setupMBC1Header(rom, 0x01, 0x00, 0x05)
+
+	cart, err := NewWithOptions(rom, Options{MBC1M: true})
+	if err != nil {
+		t.Fatalf("NewWithOptions() error = %v", err)
+	}
+	mbc, ok := cart.(*MBC1)
+	if !ok {
+		t.Fatalf("NewWithOptions() returned %T, want *MBC1", cart)
+	}
+	if !mbc.IsMulticart() {
+		t.Fatal("IsMulticart() = false with MBC1M option")
+	}
+
+	// Secondary register 0x01 selects bank 0x11 rather than 0x21
+	mbc.Write(0x4000, 0x01)
+	mbc.Write(0x2000, 0x01)
+	if got := mbc.Read(0x4000); got != 0x11 {
+		t.Errorf("MBC1M bank = 0x%02X, want 0x11", got)
+	}
+}
+
+func TestMBC1ReadBank(t *testing.T) {
+	rom := make([]byte, 0x20000) // 128 KiB (8 banks)
+	for bank := range 8 {
+		rom[bank*0x4000+0x0123] = byte(0xB0 + bank)
+	}
+	setupMBC1Header(rom, 0x03, 0x03, 0x02) // MBC1+RAM+Battery, 32 KiB RAM, 128 KiB ROM
+
+	header, _ := ParseHeader(rom)
+	cart, _ := newMBC1(rom, header)
+
+	// Put a marker in RAM bank 2, then disable RAM and return to bank 0
+	cart.Write(0x0000, 0x0A)
+	cart.Write(0x6000, 0x01)
+	cart.Write(0x4000, 0x02)
+	cart.Write(0xA010, 0x5A)
+	cart.Write(0x4000, 0x00)
+	cart.Write(0x0000, 0x00)
+
+	cart.Write(0x2000, 0x03) // Active ROM bank 3
+
+	if got := cart.ReadROMBank(5, 0x0123); got != 0xB5 {
+		t.Errorf("ReadROMBank(5, 0x0123) = 0x%02X, want 0xB5", got)
+	}
+	if got := cart.ReadROMBank(0, 0x0123); got != 0xB0 {
+		t.Errorf("ReadROMBank(0, 0x0123) = 0x%02X, want 0xB0", got)
+	}
+	if got := cart.ReadRAMBank(2, 0x0010); got != 0x5A {
+		t.Errorf("ReadRAMBank(2, 0x0010) = 0x%02X, want 0x5A", got)
+	}
+
+	// The active banks are unchanged
+	if got := cart.Read(0x4123); got != 0xB3 {
+		t.Errorf("Read(0x4123) = 0x%02X, want 0xB3 (bank 3)", got)
+	}
+	if cart.ramEnabled || cart.ramBank != 0 {
+		t.Errorf("ramEnabled = %v, ramBank = %d, want false, 0", cart.ramEnabled, cart.ramBank)
+	}
+
+	// Out-of-range banks and offsets read as 0xFF
+	tests := []struct {
+		name string
+		got  uint8
+	}{
+		{"ROM bank 8", cart.ReadROMBank(8, 0)},
+		{"ROM bank -1", cart.ReadROMBank(-1, 0)},
+		{"ROM offset 0x4000", cart.ReadROMBank(0, 0x4000)},
+		{"RAM bank 4", cart.ReadRAMBank(4, 0)},
+		{"RAM offset 0x2000", cart.ReadRAMBank(0, 0x2000)},
+	}
+	for _, tt := range tests {
+		if tt.got != 0xFF {
+			t.Errorf("%s = 0x%02X, want 0xFF", tt.name, tt.got)
+		}
+	}
+}
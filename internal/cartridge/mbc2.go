@@ -0,0 +1,160 @@
+package cartridge
+
+// mbc2RAMSize is the size of MBC2's built-in RAM (512 half-bytes).
+const mbc2RAMSize = 0x200
+
+// MBC2 represents a cartridge with MBC2 (Memory Bank Controller 2).
+// MBC2 supports up to 256 KiB of ROM and has 512x4 bits of built-in RAM.
+//
+// Memory Map:
+// - 0x0000-0x3FFF: ROM Bank 00 (fixed)
+// - 0x4000-0x7FFF: ROM Bank 01-0F (switchable)
+// - 0xA000-0xBFFF: Built-in RAM (only A000-A1FF is decoded; the rest echoes it)
+//
+// Control Registers (write-only, 0x0000-0x3FFF):
+// - Address bit 8 clear: RAM Enable (write 0x0A to enable, anything else disables)
+// - Address bit 8 set: ROM Bank Number (4 bits, 0 is treated as 1).
+//
+// Only the low 4 bits of each RAM byte exist; the upper nibble reads as 1s.
+type MBC2 struct {
+	header *Header
+	rom    []byte
+	ram    []byte
+
+	// Banking control
+	ramEnabled bool  // RAM enable flag
+	romBank    uint8 // ROM bank number, 4 bits
+	ramDirty   bool  // RAM written since the last GetRAM
+
+	// Calculated values
+	numROMBanks int
+}
+
+// newMBC2 creates a new MBC2 cartridge.
+//
+//nolint:unparam // Error return is for future expansion and interface consistency
+func newMBC2(rom []byte, header *Header) (*MBC2, error) {
+	return &MBC2{
+		header:      header,
+		rom:         rom,
+		ram:         make([]byte, mbc2RAMSize), // Built-in, regardless of the header RAM size
+		romBank:     1,                         // Bank 0 is not allowed, so default to 1
+		numROMBanks: header.GetROMBanks(),
+	}, nil
+}
+
+// Read reads a byte from the cartridge.
+func (c *MBC2) Read(addr uint16) uint8 {
+	switch {
+	// ROM Bank 00 (0x0000-0x3FFF)
+	case addr < 0x4000:
+		if int(addr) < len(c.rom) {
+			return c.rom[addr]
+		}
+		return 0xFF
+
+	// ROM Bank 01-0F (0x4000-0x7FFF)
+	case addr < 0x8000:
+		bankNumber := int(c.romBank)
+
+		// Wrap to available ROM banks
+		if c.numROMBanks > 0 && bankNumber >= c.numROMBanks {
+			bankNumber %= c.numROMBanks
+		}
+
+		offset := bankNumber*0x4000 + int(addr-0x4000)
+		if offset < len(c.rom) {
+			return c.rom[offset]
+		}
+		return 0xFF
+
+	// Built-in RAM, echoed every 512 bytes (0xA000-0xBFFF)
+	case addr >= 0xA000 && addr < 0xC000:
+		if !c.ramEnabled {
+			return 0xFF
+		}
+		return 0xF0 | c.ram[(addr-0xA000)%mbc2RAMSize]
+
+	default:
+		return 0xFF
+	}
+}
+
+// Write writes a byte to the cartridge (MBC control registers or RAM).
+func (c *MBC2) Write(addr uint16, value uint8) {
+	switch {
+	// RAM Enable / ROM Bank Number (0x0000-0x3FFF), selected by address bit 8
+	case addr < 0x4000:
+		if addr&0x0100 == 0 {
+			c.ramEnabled = (value & 0x0F) == 0x0A
+			return
+		}
+
+		c.romBank = value & 0x0F
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+
+	// Built-in RAM, echoed every 512 bytes (0xA000-0xBFFF)
+	case addr >= 0xA000 && addr < 0xC000:
+		if c.ramEnabled {
+			c.ram[(addr-0xA000)%mbc2RAMSize] = value & 0x0F
+			c.ramDirty = true
+		}
+	}
+}
+
+// ReadROMBank reads a byte from any ROM bank without changing the banking state.
+func (c *MBC2) ReadROMBank(bank int, offset uint16) uint8 {
+	return readBank(c.rom, romBankSize, bank, offset)
+}
+
+// ReadRAMBank reads a byte from the built-in RAM as it appears in the
+// 0xA000-0xBFFF window. MBC2 has a single RAM bank.
+func (c *MBC2) ReadRAMBank(bank int, offset uint16) uint8 {
+	if bank != 0 || int(offset) >= ramBankSize {
+		return 0xFF
+	}
+	return 0xF0 | c.ram[offset%mbc2RAMSize]
+}
+
+// Header returns the cartridge header.
+func (c *MBC2) Header() *Header {
+	return c.header
+}
+
+// Reset restores the banking registers to their power-on state. RAM is kept.
+func (c *MBC2) Reset() {
+	c.ramEnabled = false
+	c.romBank = 1
+}
+
+// HasBattery returns true if the cartridge has battery-backed RAM.
+func (c *MBC2) HasBattery() bool {
+	return CartridgeType(c.header.CartridgeType).HasBattery()
+}
+
+// GetRAM returns the cartridge RAM for saving.
+func (c *MBC2) GetRAM() []byte {
+	c.ramDirty = false
+
+	// Return a copy to prevent external modification
+	ramCopy := make([]byte, len(c.ram))
+	copy(ramCopy, c.ram)
+	return ramCopy
+}
+
+// RAMDirty returns true if RAM has been written since the last GetRAM.
+func (c *MBC2) RAMDirty() bool {
+	return c.ramDirty
+}
+
+// SetRAM loads save data into the cartridge RAM.
+func (c *MBC2) SetRAM(data []byte) error {
+	// Copy data into RAM (up to RAM size), keeping only the low nibbles
+	for i := 0; i < len(data) && i < len(c.ram); i++ {
+		c.ram[i] = data[i] & 0x0F
+	}
+
+	return nil
+}
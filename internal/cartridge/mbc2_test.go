@@ -0,0 +1,126 @@
+package cartridge
+
+import (
+	"testing"
+)
+
+// newTestMBC2 creates an MBC2 cartridge with a 128 KiB ROM (8 banks) whose
+// first byte of each bank is the bank number.
+func newTestMBC2(t *testing.T) *MBC2 {
+	t.Helper()
+
+	rom := make([]byte, 0x20000)
+	for bank := 0; bank < 8; bank++ {
+		rom[bank*0x4000] = byte(bank)
+	}
+	setupMBC1Header(rom, byte(TypeMBC2Battery), 0x00, 0x02) // MBC2+Battery, 128 KiB
+
+	cart, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	mbc2, ok := cart.(*MBC2)
+	if !ok {
+		t.Fatalf("New() = %T, want *MBC2", cart)
+	}
+	return mbc2
+}
+
+func TestMBC2ROMBanking(t *testing.T) {
+	cart := newTestMBC2(t)
+
+	if got := cart.Read(0x4000); got != 0x01 {
+		t.Errorf("Read(0x4000) default bank = 0x%02X, want 0x01", got)
+	}
+
+	// Address bit 8 set selects the ROM bank register
+	cart.Write(0x2100, 0x05)
+	if got := cart.Read(0x4000); got != 0x05 {
+		t.Errorf("Read(0x4000) after selecting bank 5 = 0x%02X, want 0x05", got)
+	}
+
+	// Bank 0 is treated as bank 1
+	cart.Write(0x0100, 0x00)
+	if got := cart.Read(0x4000); got != 0x01 {
+		t.Errorf("Read(0x4000) after selecting bank 0 = 0x%02X, want 0x01", got)
+	}
+
+	// Address bit 8 clear is the RAM enable register, not the bank
+	cart.Write(0x2000, 0x03)
+	if got := cart.Read(0x4000); got != 0x01 {
+		t.Errorf("Read(0x4000) after write to 0x2000 = 0x%02X, want 0x01 (unchanged)", got)
+	}
+}
+
+func TestMBC2RAMEcho(t *testing.T) {
+	cart := newTestMBC2(t)
+
+	// RAM is disabled by default
+	cart.Write(0xA000, 0x05)
+	if got := cart.Read(0xA000); got != 0xFF {
+		t.Errorf("Read(0xA000) with RAM disabled = 0x%02X, want 0xFF", got)
+	}
+
+	cart.Write(0x0000, 0x0A)
+	cart.Write(0xA000, 0x3C) // Only the low nibble is stored
+
+	for _, addr := range []uint16{0xA000, 0xA200, 0xA400, 0xBE00} {
+		if got := cart.Read(addr); got != 0xFC {
+			t.Errorf("Read(0x%04X) = 0x%02X, want 0xFC", addr, got)
+		}
+	}
+
+	// Writes through an echo land in the same cell
+	cart.Write(0xBFFF, 0x07)
+	if got := cart.Read(0xA1FF); got != 0xF7 {
+		t.Errorf("Read(0xA1FF) after write to 0xBFFF = 0x%02X, want 0xF7", got)
+	}
+
+	// Unwritten cells still read 1s in the upper nibble
+	if got := cart.Read(0xA001); got != 0xF0 {
+		t.Errorf("Read(0xA001) = 0x%02X, want 0xF0", got)
+	}
+
+	if got := cart.GetRAM(); len(got) != mbc2RAMSize || got[0] != 0x0C {
+		t.Errorf("GetRAM() len = %d, [0] = 0x%02X, want %d, 0x0C", len(got), got[0], mbc2RAMSize)
+	}
+}
+
+// TestMBC2RAMIgnoresHeaderSize tests that the built-in RAM is always 512
+// half-bytes, whatever the header RAM size says.
+func TestMBC2RAMIgnoresHeaderSize(t *testing.T) {
+	for _, ramSize := range []byte{0x00, 0x02} {
+		rom := make([]byte, 0x8000)
+		setupMBC1Header(rom, byte(TypeMBC2), ramSize, 0x00) // MBC2, 32 KiB
+
+		header, err := ParseHeader(rom)
+		if err != nil {
+			t.Fatalf("ParseHeader() error = %v", err)
+		}
+		cart, err := newMBC2(rom, header)
+		if err != nil {
+			t.Fatalf("newMBC2() error = %v", err)
+		}
+
+		cart.Write(0x0000, 0x0A) // Enable RAM
+		cart.Write(0xA1FF, 0x09)
+		if got := cart.Read(0xA1FF); got != 0xF9 {
+			t.Errorf("RAMSize 0x%02X: Read(0xA1FF) = 0x%02X, want 0xF9", ramSize, got)
+		}
+		if got := len(cart.GetRAM()); got != 512 {
+			t.Errorf("RAMSize 0x%02X: len(GetRAM()) = %d, want 512", ramSize, got)
+		}
+	}
+
+	// Save data round-trips, keeping only the low nibbles
+	rom := make([]byte, 0x8000)
+	setupMBC1Header(rom, byte(TypeMBC2Battery), 0x00, 0x00)
+	header, _ := ParseHeader(rom)
+	cart, _ := newMBC2(rom, header)
+	if err := cart.SetRAM([]byte{0xAB, 0x0C}); err != nil {
+		t.Fatalf("SetRAM() error = %v", err)
+	}
+	if got := cart.GetRAM(); got[0] != 0x0B || got[1] != 0x0C {
+		t.Errorf("GetRAM()[0:2] = %02X %02X, want 0B 0C", got[0], got[1])
+	}
+}
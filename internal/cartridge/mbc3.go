@@ -0,0 +1,475 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// RTC register select values (written to 0x4000-0x5FFF).
+const (
+	rtcSeconds  uint8 = 0x08 // RTC S: Seconds (0-59)
+	rtcMinutes  uint8 = 0x09 // RTC M: Minutes (0-59)
+	rtcHours    uint8 = 0x0A // RTC H: Hours (0-23)
+	rtcDaysLow  uint8 = 0x0B // RTC DL: Lower 8 bits of day counter
+	rtcDaysHigh uint8 = 0x0C // RTC DH: Bit 0 = day bit 8, bit 6 = halt, bit 7 = day carry
+)
+
+// RTC day-high register bits.
+const (
+	rtcDayHighBit8  uint8 = 0x01 // Bit 8 of the day counter
+	rtcDayHighHalt  uint8 = 0x40 // Halt flag (0 = active, 1 = stopped)
+	rtcDayHighCarry uint8 = 0x80 // Day counter carry (set when days overflow 511)
+
+	// rtcDayHighUnused are the day-high bits that don't exist; they read as 1.
+	rtcDayHighUnused = ^(rtcDayHighBit8 | rtcDayHighHalt | rtcDayHighCarry)
+)
+
+// rtcSaveSize is the size of the BGB/VBA RTC tail appended to save files:
+// 5 current registers and 5 latched registers (4 bytes LE each) followed by
+// a 64-bit LE unix timestamp. Some emulators write a 32-bit timestamp instead.
+const (
+	rtcSaveSize      = 48
+	rtcSaveSizeShort = 44
+)
+
+// rtcRegisters holds the RTC clock counter registers.
+type rtcRegisters struct {
+	seconds  uint8
+	minutes  uint8
+	hours    uint8
+	daysLow  uint8
+	daysHigh uint8
+}
+
+// MBC3 represents a cartridge with MBC3 (Memory Bank Controller 3).
+// MBC3 supports up to 2 MiB of ROM, 32 KiB of RAM and an optional real-time clock (RTC).
+//
+// Memory Map:
+// - 0x0000-0x3FFF: ROM Bank 00 (fixed)
+// - 0x4000-0x7FFF: ROM Bank 01-7F (switchable)
+// - 0xA000-0xBFFF: RAM Bank 00-03 or RTC register (switchable, if present)
+//
+// Control Registers (write-only):
+// - 0x0000-0x1FFF: RAM and Timer Enable (write 0x0A to enable, anything else disables)
+// - 0x2000-0x3FFF: ROM Bank Number (7 bits, 0 is treated as 1)
+// - 0x4000-0x5FFF: RAM Bank Number (0x00-0x03) or RTC Register Select (0x08-0x0C)
+// - 0x6000-0x7FFF: Latch Clock Data (write 0x00 then 0x01).
+type MBC3 struct {
+	header *Header
+	rom    []byte
+	ram    []byte
+
+	// Banking control
+	ramEnabled bool  // RAM/RTC enable flag (0x0000-0x1FFF)
+	romBank    uint8 // ROM bank number (0x2000-0x3FFF), 7 bits
+	ramBank    uint8 // RAM bank or RTC register select (0x4000-0x5FFF)
+	ramDirty   bool  // RAM or RTC written since the last GetRAM
+
+	// Real-time clock
+	hasRTC     bool
+	rtc        rtcRegisters // Live clock counters
+	rtcLatched rtcRegisters // Snapshot visible to the CPU after latching
+	latchValue uint8        // Last value written to the latch register
+	rtcUpdated time.Time    // Wall-clock time the live counters were last advanced
+
+	// now returns the current time (overridable for testing)
+	now func() time.Time
+
+	// Calculated values
+	numROMBanks int
+	numRAMBanks int
+}
+
+// newMBC3 creates a new MBC3 cartridge.
+//
+//nolint:unparam // Error return is for future expansion and interface consistency
+func newMBC3(rom []byte, header *Header) (*MBC3, error) {
+	cartType := CartridgeType(header.CartridgeType)
+	cart := &MBC3{
+		header:      header,
+		rom:         rom,
+		romBank:     1, // Bank 0 is not allowed, so default to 1
+		hasRTC:      cartType == TypeMBC3TimerBattery || cartType == TypeMBC3TimerRAMBattery,
+		latchValue:  0xFF,
+		now:         time.Now,
+		numROMBanks: header.GetROMBanks(),
+		numRAMBanks: header.GetRAMBanks(),
+	}
+	cart.rtcUpdated = cart.now()
+
+	// Initialize RAM if present
+	if cartType.HasRAM() {
+		ramSize := header.GetRAMSizeBytes()
+		if ramSize > 0 {
+			cart.ram = make([]byte, ramSize)
+		}
+	}
+
+	return cart, nil
+}
+
+// Read reads a byte from the cartridge.
+func (c *MBC3) Read(addr uint16) uint8 {
+	switch {
+	// ROM Bank 00 (0x0000-0x3FFF)
+	case addr < 0x4000:
+		if int(addr) < len(c.rom) {
+			return c.rom[addr]
+		}
+		return 0xFF
+
+	// ROM Bank 01-7F (0x4000-0x7FFF)
+	case addr < 0x8000:
+		bankNumber := int(c.romBank)
+
+		// Wrap to available ROM banks
+		if c.numROMBanks > 0 && bankNumber >= c.numROMBanks {
+			bankNumber %= c.numROMBanks
+		}
+
+		offset := bankNumber*0x4000 + int(addr-0x4000)
+		if offset < len(c.rom) {
+			return c.rom[offset]
+		}
+		return 0xFF
+
+	// External RAM or RTC register (0xA000-0xBFFF)
+	case addr >= 0xA000 && addr < 0xC000:
+		if !c.ramEnabled {
+			return 0xFF
+		}
+
+		if c.ramBank >= rtcSeconds {
+			if !c.hasRTC {
+				return 0xFF
+			}
+			return c.rtcLatched.read(c.ramBank)
+		}
+
+		if offset, ok := c.ramOffset(addr); ok {
+			return c.ram[offset]
+		}
+		return 0xFF
+
+	default:
+		return 0xFF
+	}
+}
+
+// Write writes a byte to the cartridge (MBC control registers, RAM or RTC).
+func (c *MBC3) Write(addr uint16, value uint8) {
+	switch {
+	// RAM and Timer Enable (0x0000-0x1FFF)
+	case addr < 0x2000:
+		c.ramEnabled = (value & 0x0F) == 0x0A
+
+	// ROM Bank Number (0x2000-0x3FFF)
+	case addr < 0x4000:
+		c.romBank = value & 0x7F
+		if c.romBank == 0 {
+			c.romBank = 1
+		}
+
+	// RAM Bank Number / RTC Register Select (0x4000-0x5FFF)
+	case addr < 0x6000:
+		c.ramBank = value
+
+	// Latch Clock Data (0x6000-0x7FFF)
+	case addr < 0x8000:
+		// Writing 0x00 then 0x01 copies the live clock into the latched registers
+		if c.latchValue == 0x00 && value == 0x01 && c.hasRTC {
+			c.updateRTC()
+			c.rtcLatched = c.rtc
+		}
+		c.latchValue = value
+
+	// External RAM or RTC register (0xA000-0xBFFF)
+	case addr >= 0xA000 && addr < 0xC000:
+		if !c.ramEnabled {
+			return
+		}
+
+		if c.ramBank >= rtcSeconds {
+			if c.hasRTC {
+				c.writeRTC(c.ramBank, value)
+				c.ramDirty = true
+			}
+			return
+		}
+
+		if offset, ok := c.ramOffset(addr); ok {
+			c.ram[offset] = value
+			c.ramDirty = true
+		}
+	}
+}
+
+// ramOffset translates an external RAM address into an offset into the
+// allocated RAM, returning false if it falls outside the allocated RAM.
+func (c *MBC3) ramOffset(addr uint16) (int, bool) {
+	if c.ram == nil || c.ramBank > 0x03 {
+		return 0, false
+	}
+
+	bankNumber := int(c.ramBank)
+	if c.numRAMBanks > 0 && bankNumber >= c.numRAMBanks {
+		bankNumber %= c.numRAMBanks
+	}
+
+	offset := bankNumber*0x2000 + int(addr-0xA000)
+	return offset, offset < len(c.ram)
+}
+
+// writeRTC writes an RTC register. The live counters are brought up to date
+// first so elapsed time is not lost when a register is changed.
+func (c *MBC3) writeRTC(reg, value uint8) {
+	c.updateRTC()
+	c.rtc.set(reg, value)
+	c.rtcLatched.set(reg, value)
+}
+
+// updateRTC advances the live clock counters by the wall-clock time elapsed
+// since the last update. The clock does not advance while halted.
+func (c *MBC3) updateRTC() {
+	now := c.now()
+	if c.rtc.daysHigh&rtcDayHighHalt != 0 || now.Before(c.rtcUpdated) {
+		c.rtcUpdated = now
+		return
+	}
+
+	// Only consume whole seconds so frequent updates don't lose time
+	seconds := now.Sub(c.rtcUpdated) / time.Second
+	if seconds == 0 {
+		return
+	}
+	c.rtcUpdated = c.rtcUpdated.Add(seconds * time.Second)
+	c.rtc.advance(int64(seconds))
+}
+
+// get returns the value of an RTC register.
+func (r *rtcRegisters) get(reg uint8) uint8 {
+	switch reg {
+	case rtcSeconds:
+		return r.seconds
+	case rtcMinutes:
+		return r.minutes
+	case rtcHours:
+		return r.hours
+	case rtcDaysLow:
+		return r.daysLow
+	case rtcDaysHigh:
+		return r.daysHigh
+	default:
+		return 0xFF
+	}
+}
+
+// read returns the value of an RTC register as the CPU sees it, with the
+// unused day-high bits reading as 1.
+func (r *rtcRegisters) read(reg uint8) uint8 {
+	if reg == rtcDaysHigh {
+		return r.daysHigh | rtcDayHighUnused
+	}
+	return r.get(reg)
+}
+
+// set sets the value of an RTC register. Only the bits that exist are kept:
+// seconds and minutes are 6 bits, hours 5 bits, and day-high has bits 0, 6
+// and 7.
+func (r *rtcRegisters) set(reg, value uint8) {
+	switch reg {
+	case rtcSeconds:
+		r.seconds = value & 0x3F
+	case rtcMinutes:
+		r.minutes = value & 0x3F
+	case rtcHours:
+		r.hours = value & 0x1F
+	case rtcDaysLow:
+		r.daysLow = value
+	case rtcDaysHigh:
+		r.daysHigh = value &^ rtcDayHighUnused
+	}
+}
+
+// advance adds the given number of seconds to the clock counters,
+// setting the day carry flag if the 9-bit day counter overflows.
+//
+// Counters holding out-of-range values (written by the game) tick one unit
+// at a time until they wrap to 0 at their bit limit, without carrying, as on
+// hardware. Once all counters are in range the rest is added in one step.
+func (r *rtcRegisters) advance(seconds int64) {
+	for ; seconds > 0 && !r.inRange(); seconds-- {
+		r.tick()
+	}
+	if seconds == 0 {
+		return
+	}
+
+	total := int64(r.seconds) + seconds
+	r.seconds = uint8(total % 60) //nolint:gosec // G115: Value is always in range 0-59
+
+	total = int64(r.minutes) + total/60
+	r.minutes = uint8(total % 60) //nolint:gosec // G115: Value is always in range 0-59
+
+	total = int64(r.hours) + total/60
+	r.hours = uint8(total % 24) //nolint:gosec // G115: Value is always in range 0-23
+
+	days := int64(r.daysLow) | int64(r.daysHigh&rtcDayHighBit8)<<8
+	days += total / 24
+	if days > 0x1FF {
+		r.daysHigh |= rtcDayHighCarry
+		days %= 0x200
+	}
+	r.daysLow = uint8(days)                                                 //nolint:gosec // G115: Intentional byte extraction
+	r.daysHigh = r.daysHigh&^rtcDayHighBit8 | uint8(days>>8)&rtcDayHighBit8 //nolint:gosec // G115: Intentional bit extraction
+}
+
+// inRange reports whether the seconds, minutes and hours counters hold
+// valid clock values.
+func (r *rtcRegisters) inRange() bool {
+	return r.seconds < 60 && r.minutes < 60 && r.hours < 24
+}
+
+// tick advances the clock counters by one second. A counter carries into the
+// next one when it reaches its normal limit; an out-of-range counter wraps to
+// 0 at its bit limit without carrying.
+func (r *rtcRegisters) tick() {
+	r.seconds = (r.seconds + 1) & 0x3F
+	if r.seconds != 60 {
+		return
+	}
+	r.seconds = 0
+
+	r.minutes = (r.minutes + 1) & 0x3F
+	if r.minutes != 60 {
+		return
+	}
+	r.minutes = 0
+
+	r.hours = (r.hours + 1) & 0x1F
+	if r.hours != 24 {
+		return
+	}
+	r.hours = 0
+
+	r.advanceDay()
+}
+
+// advanceDay adds one day to the 9-bit day counter, setting the carry flag
+// when it overflows.
+func (r *rtcRegisters) advanceDay() {
+	days := (uint16(r.daysLow) | uint16(r.daysHigh&rtcDayHighBit8)<<8) + 1
+	if days > 0x1FF {
+		r.daysHigh |= rtcDayHighCarry
+		days = 0
+	}
+	r.daysLow = uint8(days)                                                 //nolint:gosec // G115: Intentional byte extraction
+	r.daysHigh = r.daysHigh&^rtcDayHighBit8 | uint8(days>>8)&rtcDayHighBit8 //nolint:gosec // G115: Intentional bit extraction
+}
+
+// ReadROMBank reads a byte from any ROM bank without changing the banking state.
+func (c *MBC3) ReadROMBank(bank int, offset uint16) uint8 {
+	return readBank(c.rom, romBankSize, bank, offset)
+}
+
+// ReadRAMBank reads a byte from any RAM bank without changing the banking state.
+func (c *MBC3) ReadRAMBank(bank int, offset uint16) uint8 {
+	return readBank(c.ram, ramBankSize, bank, offset)
+}
+
+// Header returns the cartridge header.
+func (c *MBC3) Header() *Header {
+	return c.header
+}
+
+// Reset restores the banking registers to their power-on state. RAM is kept
+// and the RTC keeps running, as both are battery-backed.
+func (c *MBC3) Reset() {
+	c.ramEnabled = false
+	c.romBank = 1
+	c.ramBank = 0
+	c.latchValue = 0xFF
+}
+
+// HasBattery returns true if the cartridge has battery-backed RAM.
+func (c *MBC3) HasBattery() bool {
+	return CartridgeType(c.header.CartridgeType).HasBattery()
+}
+
+// HasRTC returns true if the cartridge has a real-time clock.
+func (c *MBC3) HasRTC() bool {
+	return c.hasRTC
+}
+
+// GetRAM returns the cartridge RAM for saving.
+// For RTC carts, the BGB/VBA compatible 48-byte RTC tail is appended after the RAM.
+func (c *MBC3) GetRAM() []byte {
+	if c.ram == nil && !c.hasRTC {
+		return nil
+	}
+	c.ramDirty = false
+
+	size := len(c.ram)
+	if c.hasRTC {
+		size += rtcSaveSize
+	}
+
+	// Return a copy to prevent external modification
+	data := make([]byte, size)
+	copy(data, c.ram)
+
+	if c.hasRTC {
+		c.updateRTC()
+		tail := data[len(c.ram):]
+		for i, reg := range []uint8{rtcSeconds, rtcMinutes, rtcHours, rtcDaysLow, rtcDaysHigh} {
+			binary.LittleEndian.PutUint32(tail[i*4:], uint32(c.rtc.get(reg)))
+			binary.LittleEndian.PutUint32(tail[20+i*4:], uint32(c.rtcLatched.get(reg)))
+		}
+		binary.LittleEndian.PutUint64(tail[40:], uint64(c.rtcUpdated.Unix())) //nolint:gosec // G115: Unix time is positive
+	}
+
+	return data
+}
+
+// RAMDirty returns true if RAM or the RTC has been written since the last GetRAM.
+func (c *MBC3) RAMDirty() bool {
+	return c.ramDirty
+}
+
+// SetRAM loads save data into the cartridge RAM.
+// If the data contains an RTC tail after the RAM, the clock is restored and
+// advanced by the real time elapsed since the save was written. Save files
+// without an RTC tail leave the clock running from its current state.
+func (c *MBC3) SetRAM(data []byte) error {
+	// Copy data into RAM (up to RAM size)
+	copyLen := min(len(data), len(c.ram))
+	copy(c.ram, data[:copyLen])
+
+	if !c.hasRTC {
+		return nil
+	}
+
+	tail := data[copyLen:]
+	if len(tail) < rtcSaveSizeShort {
+		return nil // No RTC data (older or non-RTC save file)
+	}
+
+	for i, reg := range []uint8{rtcSeconds, rtcMinutes, rtcHours, rtcDaysLow, rtcDaysHigh} {
+		c.rtc.set(reg, uint8(binary.LittleEndian.Uint32(tail[i*4:])))           //nolint:gosec // G115: Registers are stored as bytes
+		c.rtcLatched.set(reg, uint8(binary.LittleEndian.Uint32(tail[20+i*4:]))) //nolint:gosec // G115: Registers are stored as bytes
+	}
+
+	var saved int64
+	if len(tail) >= rtcSaveSize {
+		saved = int64(binary.LittleEndian.Uint64(tail[40:])) //nolint:gosec // G115: Unix time fits in int64
+	} else {
+		saved = int64(binary.LittleEndian.Uint32(tail[40:]))
+	}
+
+	// Treat the clock as last updated when the save was written so the
+	// next update advances it by the time spent powered off.
+	c.rtcUpdated = time.Unix(saved, 0)
+	c.updateRTC()
+
+	return nil
+}
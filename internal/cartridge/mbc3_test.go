@@ -0,0 +1,358 @@
+package cartridge
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestMBC3 creates an MBC3 cartridge with a controllable clock.
+func newTestMBC3(t *testing.T, cartType, ramSize byte, now *time.Time) *MBC3 {
+	t.Helper()
+
+	rom := make([]byte, 0x20000) // 128 KiB (8 banks)
+	for bank := range 8 {
+		rom[bank*0x4000] = byte(bank)
+	}
+	setupMBC1Header(rom, cartType, ramSize, 0x02)
+
+	header, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+	cart, err := newMBC3(rom, header)
+	if err != nil {
+		t.Fatalf("newMBC3() error = %v", err)
+	}
+
+	cart.now = func() time.Time { return *now }
+	cart.rtcUpdated = *now
+	return cart
+}
+
+// latchRTC latches the clock registers.
+func latchRTC(c *MBC3) {
+	c.Write(0x6000, 0x00)
+	c.Write(0x6000, 0x01)
+}
+
+// readRTC selects and reads an RTC register.
+func readRTC(c *MBC3, reg uint8) uint8 {
+	c.Write(0x4000, reg)
+	return c.Read(0xA000)
+}
+
+func TestMBC3ROMBanking(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x11, 0x00, &now)
+
+	if got := cart.Read(0x4000); got != 0x01 {
+		t.Errorf("default bank = 0x%02X, want 0x01", got)
+	}
+
+	cart.Write(0x2000, 0x05)
+	if got := cart.Read(0x4000); got != 0x05 {
+		t.Errorf("bank 5 = 0x%02X, want 0x05", got)
+	}
+
+	// Bank 0 is treated as bank 1
+	cart.Write(0x2000, 0x00)
+	if got := cart.Read(0x4000); got != 0x01 {
+		t.Errorf("bank 0 write = 0x%02X, want 0x01", got)
+	}
+}
+
+func TestMBC3RAMBanking(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x13, 0x03, &now) // MBC3+RAM+Battery, 32 KiB
+
+	cart.Write(0x0000, 0x0A)
+	for bank := range uint8(4) {
+		cart.Write(0x4000, bank)
+		cart.Write(0xA000, 0x10+bank)
+	}
+	for bank := range uint8(4) {
+		cart.Write(0x4000, bank)
+		if got := cart.Read(0xA000); got != 0x10+bank {
+			t.Errorf("RAM bank %d = 0x%02X, want 0x%02X", bank, got, 0x10+bank)
+		}
+	}
+
+	if cart.HasRTC() {
+		t.Error("HasRTC() = true for MBC3 without timer")
+	}
+}
+
+func TestMBC3RTCLatch(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x10, 0x02, &now) // MBC3+Timer+RAM+Battery
+
+	cart.Write(0x0000, 0x0A)
+
+	// 1 day, 2 hours, 3 minutes, 4 seconds later
+	now = now.Add(26*time.Hour + 3*time.Minute + 4*time.Second)
+
+	// Registers do not change until latched
+	if got := readRTC(cart, rtcSeconds); got != 0 {
+		t.Errorf("seconds before latch = %d, want 0", got)
+	}
+
+	latchRTC(cart)
+	want := map[uint8]uint8{rtcSeconds: 4, rtcMinutes: 3, rtcHours: 2, rtcDaysLow: 1, rtcDaysHigh: rtcDayHighUnused}
+	for reg, value := range want {
+		if got := readRTC(cart, reg); got != value {
+			t.Errorf("RTC register 0x%02X = %d, want %d", reg, got, value)
+		}
+	}
+}
+
+func TestMBC3RTCHalt(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x0F, 0x00, &now) // MBC3+Timer+Battery
+
+	cart.Write(0x0000, 0x0A)
+	cart.Write(0x4000, rtcDaysHigh)
+	cart.Write(0xA000, rtcDayHighHalt)
+
+	now = now.Add(time.Hour)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcHours); got != 0 {
+		t.Errorf("hours while halted = %d, want 0", got)
+	}
+
+	// Time spent halted is not counted once the clock resumes
+	cart.Write(0x4000, rtcDaysHigh)
+	cart.Write(0xA000, 0x00)
+	now = now.Add(2 * time.Minute)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcHours); got != 0 {
+		t.Errorf("hours after resuming = %d, want 0", got)
+	}
+	if got := readRTC(cart, rtcMinutes); got != 2 {
+		t.Errorf("minutes after resuming = %d, want 2", got)
+	}
+}
+
+func TestMBC3RTCDayCarry(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x0F, 0x00, &now)
+
+	now = now.Add(513 * 24 * time.Hour)
+	cart.Write(0x0000, 0x0A)
+	latchRTC(cart)
+
+	if got := readRTC(cart, rtcDaysLow); got != 1 {
+		t.Errorf("days low after overflow = %d, want 1", got)
+	}
+	if got, want := readRTC(cart, rtcDaysHigh), rtcDayHighCarry|rtcDayHighUnused; got != want {
+		t.Errorf("days high after overflow = 0x%02X, want 0x%02X", got, want)
+	}
+
+	// The carry stays set as the clock keeps running, until it is written
+	now = now.Add(24 * time.Hour)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcDaysHigh); got&rtcDayHighCarry == 0 {
+		t.Errorf("days high a day later = 0x%02X, want carry still set", got)
+	}
+	cart.Write(0xA000, 0x00)
+	if got := readRTC(cart, rtcDaysHigh); got != rtcDayHighUnused {
+		t.Errorf("days high after clearing = 0x%02X, want 0x%02X", got, rtcDayHighUnused)
+	}
+}
+
+func TestMBC3RTCRegisterMasks(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x0F, 0x00, &now)
+	cart.Write(0x0000, 0x0A)
+
+	tests := []struct {
+		reg   uint8
+		value uint8
+		want  uint8
+	}{
+		{rtcSeconds, 0xFF, 0x3F},
+		{rtcMinutes, 0xFF, 0x3F},
+		{rtcHours, 0xFF, 0x1F},
+		{rtcDaysLow, 0xFF, 0xFF},
+		{rtcDaysHigh, 0xFF, 0xFF},
+		{rtcDaysHigh, 0x00, 0x3E},
+		{rtcDaysHigh, 0x01, 0x3F},
+		{rtcDaysHigh, 0x3E, 0x3E},
+		{rtcDaysHigh, 0x80, 0xBE},
+	}
+
+	for _, tt := range tests {
+		cart.Write(0x4000, tt.reg)
+		cart.Write(0xA000, tt.value)
+		if got := cart.Read(0xA000); got != tt.want {
+			t.Errorf("RTC register 0x%02X after writing 0x%02X = 0x%02X, want 0x%02X", tt.reg, tt.value, got, tt.want)
+		}
+
+		// The live clock keeps only the existing bits too
+		if got := cart.rtc.read(tt.reg); got != tt.want {
+			t.Errorf("live RTC register 0x%02X after writing 0x%02X = 0x%02X, want 0x%02X", tt.reg, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMBC3RTCOutOfRangeValues(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x0F, 0x00, &now)
+	cart.Write(0x0000, 0x0A)
+
+	// Later writes and latches with no time elapsed keep invalid values
+	cart.Write(0x4000, rtcSeconds)
+	cart.Write(0xA000, 63)
+	cart.Write(0x4000, rtcMinutes)
+	cart.Write(0xA000, 10)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcSeconds); got != 63 {
+		t.Errorf("seconds = %d, want 63", got)
+	}
+	if got := readRTC(cart, rtcMinutes); got != 10 {
+		t.Errorf("minutes = %d, want 10", got)
+	}
+
+	// An invalid value wraps to 0 at its bit limit without carrying
+	now = now.Add(time.Second)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcSeconds); got != 0 {
+		t.Errorf("seconds after wrapping = %d, want 0", got)
+	}
+	if got := readRTC(cart, rtcMinutes); got != 10 {
+		t.Errorf("minutes after seconds wrapped = %d, want 10", got)
+	}
+
+	// Invalid hours wrap at 31 without advancing the day
+	cart.Write(0x4000, rtcHours)
+	cart.Write(0xA000, 31)
+	now = now.Add(time.Hour + 50*time.Minute)
+	latchRTC(cart)
+	want := map[uint8]uint8{rtcSeconds: 0, rtcMinutes: 0, rtcHours: 1, rtcDaysLow: 0}
+	for reg, value := range want {
+		if got := readRTC(cart, reg); got != value {
+			t.Errorf("RTC register 0x%02X after hours wrapped = %d, want %d", reg, got, value)
+		}
+	}
+}
+
+func TestMBC3RTCWriteAdjustsClock(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x0F, 0x00, &now)
+	cart.Write(0x0000, 0x0A)
+
+	cart.Write(0x4000, rtcSeconds)
+	cart.Write(0xA000, 30)
+	cart.Write(0x4000, rtcHours)
+	cart.Write(0xA000, 5)
+
+	// The clock keeps running from the written values
+	now = now.Add(40 * time.Second)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcSeconds); got != 10 {
+		t.Errorf("seconds = %d, want 10", got)
+	}
+	if got := readRTC(cart, rtcMinutes); got != 1 {
+		t.Errorf("minutes = %d, want 1", got)
+	}
+	if got := readRTC(cart, rtcHours); got != 5 {
+		t.Errorf("hours = %d, want 5", got)
+	}
+}
+
+func TestMBC3RTCSaveRoundTrip(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x10, 0x02, &now)
+
+	cart.Write(0x0000, 0x0A)
+	cart.Write(0x4000, 0x00)
+	cart.Write(0xA000, 0x42)
+
+	now = now.Add(5*time.Hour + 30*time.Minute)
+	latchRTC(cart)
+
+	save := cart.GetRAM()
+	if len(save) != 8192+rtcSaveSize {
+		t.Fatalf("len(GetRAM()) = %d, want %d", len(save), 8192+rtcSaveSize)
+	}
+
+	// Load into a fresh cartridge two days later
+	later := now.Add(48 * time.Hour)
+	loaded := newTestMBC3(t, 0x10, 0x02, &later)
+	if err := loaded.SetRAM(save); err != nil {
+		t.Fatalf("SetRAM() error = %v", err)
+	}
+
+	loaded.Write(0x0000, 0x0A)
+	loaded.Write(0x4000, 0x00)
+	if got := loaded.Read(0xA000); got != 0x42 {
+		t.Errorf("RAM after load = 0x%02X, want 0x42", got)
+	}
+
+	// Latched registers are restored as saved
+	if got := readRTC(loaded, rtcHours); got != 5 {
+		t.Errorf("latched hours after load = %d, want 5", got)
+	}
+	if got := readRTC(loaded, rtcMinutes); got != 30 {
+		t.Errorf("latched minutes after load = %d, want 30", got)
+	}
+
+	// The live clock advanced by the elapsed real time while powered off
+	latchRTC(loaded)
+	if got := readRTC(loaded, rtcDaysLow); got != 2 {
+		t.Errorf("days after elapsed time = %d, want 2", got)
+	}
+	if got := readRTC(loaded, rtcHours); got != 5 {
+		t.Errorf("hours after elapsed time = %d, want 5", got)
+	}
+}
+
+func TestMBC3SetRAMWithoutRTCTail(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x10, 0x02, &now)
+
+	save := make([]byte, 8192)
+	save[0] = 0x99
+	if err := cart.SetRAM(save); err != nil {
+		t.Fatalf("SetRAM() error = %v", err)
+	}
+
+	cart.Write(0x0000, 0x0A)
+	cart.Write(0x4000, 0x00)
+	if got := cart.Read(0xA000); got != 0x99 {
+		t.Errorf("RAM after load = 0x%02X, want 0x99", got)
+	}
+
+	// The clock keeps running from its current state
+	now = now.Add(10 * time.Second)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcSeconds); got != 10 {
+		t.Errorf("seconds = %d, want 10", got)
+	}
+}
+
+func TestMBC3SetRAMShortRTCTail(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	cart := newTestMBC3(t, 0x0F, 0x00, &now) // Timer without RAM
+
+	// 44-byte tail with a 32-bit timestamp one minute in the past
+	tail := make([]byte, rtcSaveSizeShort)
+	tail[0] = 10                                  // Current seconds
+	saved := uint32(now.Add(-time.Minute).Unix()) //nolint:gosec // G115: Test timestamp fits in 32 bits
+	tail[40] = byte(saved)
+	tail[41] = byte(saved >> 8)
+	tail[42] = byte(saved >> 16)
+	tail[43] = byte(saved >> 24)
+
+	if err := cart.SetRAM(tail); err != nil {
+		t.Fatalf("SetRAM() error = %v", err)
+	}
+
+	cart.Write(0x0000, 0x0A)
+	latchRTC(cart)
+	if got := readRTC(cart, rtcSeconds); got != 10 {
+		t.Errorf("seconds = %d, want 10", got)
+	}
+	if got := readRTC(cart, rtcMinutes); got != 1 {
+		t.Errorf("minutes = %d, want 1", got)
+	}
+}
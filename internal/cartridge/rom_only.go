@@ -6,6 +6,8 @@ type ROMOnly struct {
 	header *Header
 	rom    []byte
 	ram    []byte
+
+	ramDirty bool // RAM written since the last GetRAM
 }
 
 // newROMOnly creates a new ROM-only cartridge.
@@ -66,16 +68,30 @@ func (c *ROMOnly) Write(addr uint16, value uint8) {
 			ramAddr := addr - 0xA000
 			if int(ramAddr) < len(c.ram) {
 				c.ram[ramAddr] = value
+				c.ramDirty = true
 			}
 		}
 	}
 }
 
+// ReadROMBank reads a byte from any ROM bank without changing the banking state.
+func (c *ROMOnly) ReadROMBank(bank int, offset uint16) uint8 {
+	return readBank(c.rom, romBankSize, bank, offset)
+}
+
+// ReadRAMBank reads a byte from any RAM bank without changing the banking state.
+func (c *ROMOnly) ReadRAMBank(bank int, offset uint16) uint8 {
+	return readBank(c.ram, ramBankSize, bank, offset)
+}
+
 // Header returns the cartridge header.
 func (c *ROMOnly) Header() *Header {
 	return c.header
 }
 
+// Reset does nothing: ROM-only cartridges have no banking registers.
+func (c *ROMOnly) Reset() {}
+
 // HasBattery returns true if the cartridge has battery-backed RAM.
 func (c *ROMOnly) HasBattery() bool {
 	return CartridgeType(c.header.CartridgeType).HasBattery()
@@ -86,12 +102,18 @@ func (c *ROMOnly) GetRAM() []byte {
 	if c.ram == nil {
 		return nil
 	}
+	c.ramDirty = false
 	// Return a copy to prevent external modification
 	ramCopy := make([]byte, len(c.ram))
 	copy(ramCopy, c.ram)
 	return ramCopy
 }
 
+// RAMDirty returns true if RAM has been written since the last GetRAM.
+func (c *ROMOnly) RAMDirty() bool {
+	return c.ramDirty
+}
+
 // SetRAM loads save data into the cartridge RAM.
 func (c *ROMOnly) SetRAM(data []byte) error {
 	if c.ram == nil {
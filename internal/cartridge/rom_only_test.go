@@ -111,6 +111,44 @@ func TestROMOnlyWithRAM(t *testing.T) {
 	}
 }
 
+func TestROMOnlyWith2KiBRAM(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	setupMinimalHeader(rom, 0x09, 0x01) // ROM+RAM+Battery, 2 KiB RAM
+
+	header, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+
+	cart, err := newROMOnly(rom, header)
+	if err != nil {
+		t.Fatalf("newROMOnly() error = %v", err)
+	}
+
+	// Writes within 0xA000-0xA7FF persist
+	cart.Write(0xA000, 0x11)
+	cart.Write(0xA7FF, 0x22)
+	if got := cart.Read(0xA000); got != 0x11 {
+		t.Errorf("Read(0xA000) = 0x%02X, want 0x11", got)
+	}
+	if got := cart.Read(0xA7FF); got != 0x22 {
+		t.Errorf("Read(0xA7FF) = 0x%02X, want 0x22", got)
+	}
+
+	// Reads above the 2 KiB boundary return 0xFF
+	cart.Write(0xA800, 0x33)
+	if got := cart.Read(0xA800); got != 0xFF {
+		t.Errorf("Read(0xA800) = 0x%02X, want 0xFF", got)
+	}
+	if got := cart.Read(0xBFFF); got != 0xFF {
+		t.Errorf("Read(0xBFFF) = 0x%02X, want 0xFF", got)
+	}
+
+	if got := len(cart.GetRAM()); got != 2048 {
+		t.Errorf("len(GetRAM()) = %d, want 2048", got)
+	}
+}
+
 func TestROMOnlyNoRAM(t *testing.T) {
 	rom := make([]byte, 0x8000)
 	setupMinimalHeader(rom, 0x00, 0x00) // ROM only, no RAM
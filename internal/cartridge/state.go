@@ -0,0 +1,168 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrStateRAMSize indicates saved state has a different RAM size than the cartridge.
+var ErrStateRAMSize = errors.New("saved RAM size does not match cartridge")
+
+// writeState writes the RAM contents, prefixed with their length, followed
+// by the banking registers in regs.
+func writeState(w io.Writer, ram []byte, regs any) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(ram))); err != nil { //nolint:gosec // G115: RAM is at most 128 KiB
+		return fmt.Errorf("failed to write cartridge state: %w", err)
+	}
+	if _, err := w.Write(ram); err != nil {
+		return fmt.Errorf("failed to write cartridge state: %w", err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, regs); err != nil {
+		return fmt.Errorf("failed to write cartridge state: %w", err)
+	}
+	return nil
+}
+
+// readState reads state written by writeState into ram and regs. The saved
+// RAM must be the same size as ram.
+func readState(r io.Reader, ram []byte, regs any) error {
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return fmt.Errorf("failed to read cartridge state: %w", err)
+	}
+	if int(size) != len(ram) {
+		return fmt.Errorf("%w: saved %d bytes, cartridge has %d", ErrStateRAMSize, size, len(ram))
+	}
+	if _, err := io.ReadFull(r, ram); err != nil {
+		return fmt.Errorf("failed to read cartridge state: %w", err)
+	}
+	if err := binary.Read(r, binary.LittleEndian, regs); err != nil {
+		return fmt.Errorf("failed to read cartridge state: %w", err)
+	}
+	return nil
+}
+
+// SaveState writes the cartridge RAM to w.
+func (c *ROMOnly) SaveState(w io.Writer) error {
+	return writeState(w, c.ram, struct{}{})
+}
+
+// LoadState restores state written by SaveState.
+func (c *ROMOnly) LoadState(r io.Reader) error {
+	if err := readState(r, c.ram, &struct{}{}); err != nil {
+		return err
+	}
+	c.ramDirty = true
+	return nil
+}
+
+// mbc1State is the MBC1 banking state.
+type mbc1State struct {
+	RAMEnabled  bool
+	ROMBank     uint8
+	RAMBank     uint8
+	BankingMode uint8
+}
+
+// SaveState writes the cartridge RAM and banking registers to w.
+func (c *MBC1) SaveState(w io.Writer) error {
+	return writeState(w, c.ram, &mbc1State{
+		RAMEnabled:  c.ramEnabled,
+		ROMBank:     c.romBank,
+		RAMBank:     c.ramBank,
+		BankingMode: c.bankingMode,
+	})
+}
+
+// LoadState restores state written by SaveState.
+func (c *MBC1) LoadState(r io.Reader) error {
+	var s mbc1State
+	if err := readState(r, c.ram, &s); err != nil {
+		return err
+	}
+	c.ramEnabled = s.RAMEnabled
+	c.romBank = s.ROMBank
+	c.ramBank = s.RAMBank
+	c.bankingMode = s.BankingMode
+	c.ramDirty = true
+	return nil
+}
+
+// mbc2State is the MBC2 banking state.
+type mbc2State struct {
+	RAMEnabled bool
+	ROMBank    uint8
+}
+
+// SaveState writes the built-in RAM and banking registers to w.
+func (c *MBC2) SaveState(w io.Writer) error {
+	return writeState(w, c.ram, &mbc2State{
+		RAMEnabled: c.ramEnabled,
+		ROMBank:    c.romBank,
+	})
+}
+
+// LoadState restores state written by SaveState.
+func (c *MBC2) LoadState(r io.Reader) error {
+	var s mbc2State
+	if err := readState(r, c.ram, &s); err != nil {
+		return err
+	}
+	c.ramEnabled = s.RAMEnabled
+	c.romBank = s.ROMBank
+	c.ramDirty = true
+	return nil
+}
+
+// mbc3State is the MBC3 banking and clock state.
+type mbc3State struct {
+	RAMEnabled bool
+	ROMBank    uint8
+	RAMBank    uint8
+	LatchValue uint8
+	RTC        [5]uint8
+	RTCLatched [5]uint8
+	RTCUpdated int64 // Unix time in nanoseconds
+}
+
+// rtcRegisterOrder lists the RTC registers in save order.
+var rtcRegisterOrder = [5]uint8{rtcSeconds, rtcMinutes, rtcHours, rtcDaysLow, rtcDaysHigh}
+
+// SaveState writes the cartridge RAM, banking registers and clock to w.
+// The clock keeps running in real time while the state is stored.
+func (c *MBC3) SaveState(w io.Writer) error {
+	s := mbc3State{
+		RAMEnabled: c.ramEnabled,
+		ROMBank:    c.romBank,
+		RAMBank:    c.ramBank,
+		LatchValue: c.latchValue,
+		RTCUpdated: c.rtcUpdated.UnixNano(),
+	}
+	for i, reg := range rtcRegisterOrder {
+		s.RTC[i] = c.rtc.get(reg)
+		s.RTCLatched[i] = c.rtcLatched.get(reg)
+	}
+	return writeState(w, c.ram, &s)
+}
+
+// LoadState restores state written by SaveState.
+func (c *MBC3) LoadState(r io.Reader) error {
+	var s mbc3State
+	if err := readState(r, c.ram, &s); err != nil {
+		return err
+	}
+	c.ramEnabled = s.RAMEnabled
+	c.romBank = s.ROMBank
+	c.ramBank = s.RAMBank
+	c.latchValue = s.LatchValue
+	for i, reg := range rtcRegisterOrder {
+		c.rtc.set(reg, s.RTC[i])
+		c.rtcLatched.set(reg, s.RTCLatched[i])
+	}
+	c.rtcUpdated = time.Unix(0, s.RTCUpdated)
+	c.ramDirty = true
+	return nil
+}
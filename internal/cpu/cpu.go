@@ -1,6 +1,29 @@
 // Package cpu implements the Sharp SM83 CPU emulation for the Game Boy.
 package cpu
 
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrIllegalOpcode indicates the CPU executed an undefined opcode.
+var ErrIllegalOpcode = errors.New("illegal opcode")
+
+// IllegalOpcodeMode selects how the CPU handles undefined opcodes.
+type IllegalOpcodeMode int
+
+// Illegal opcode modes.
+const (
+	// IllegalOpcodeLockup locks up the CPU, as on hardware: it executes no
+	// further instructions, ignores interrupts and consumes 4 cycles per step.
+	IllegalOpcodeLockup IllegalOpcodeMode = iota
+
+	// IllegalOpcodeError locks up the CPU like IllegalOpcodeLockup and also
+	// records an error, available from LastError.
+	IllegalOpcodeError
+)
+
 // Interrupt bit positions in IE/IF registers.
 const (
 	InterruptVBlank uint8 = 0 // V-Blank interrupt (highest priority)
@@ -25,6 +48,15 @@ type Memory interface {
 	Write(addr uint16, value uint8)
 }
 
+// Peeker is implemented by memory that can be accessed without counting as
+// a program access, as memory.Bus does for its access statistics. The CPU
+// uses it for interrupt checks and tracing, which hardware does not do over
+// the bus.
+type Peeker interface {
+	Peek(addr uint16) uint8
+	Poke(addr uint16, value uint8)
+}
+
 // CPU represents the Sharp SM83 CPU.
 type CPU struct {
 	Registers *Registers
@@ -35,7 +67,8 @@ type CPU struct {
 
 	// Pending IME for EI instruction (delayed enable).
 	// The EI instruction enables interrupts AFTER the next instruction executes.
-	// This flag tracks that we need to set IME=true after the current instruction completes.
+	// This flag tracks that we need to set IME=true once the instruction following
+	// EI completes.
 	pendingIME bool
 
 	// Halt and stop states
@@ -49,6 +82,21 @@ type CPU struct {
 
 	// Cycle counter
 	Cycles uint64
+
+	// Illegal opcode handling: lockedUp is set once an undefined opcode executes
+	illegalMode IllegalOpcodeMode
+	lockedUp    bool
+	lastErr     error
+
+	// breakpointHit is set when LD B,B (software breakpoint) executes
+	breakpointHit bool
+
+	// tracer receives an execution trace line per instruction (nil = disabled)
+	tracer io.Writer
+
+	// OnInterrupt, if set, is called for each serviced interrupt with its IF
+	// bit (0-4), the PC pushed as the return address and the handler address.
+	OnInterrupt func(bit uint8, fromPC, toPC uint16)
 }
 
 // New creates a new CPU instance.
@@ -65,6 +113,12 @@ func New(mem Memory) *CPU {
 
 // Step executes one instruction and returns cycles taken.
 func (c *CPU) Step() uint8 {
+	// A locked-up CPU does nothing until reset, not even service interrupts
+	if c.lockedUp {
+		c.Cycles += 4
+		return 4
+	}
+
 	// Check for interrupts before executing instruction
 	if interruptCycles := c.checkInterrupts(); interruptCycles > 0 {
 		c.Cycles += uint64(interruptCycles)
@@ -74,8 +128,8 @@ func (c *CPU) Step() uint8 {
 	// Handle halt state
 	if c.halted {
 		// Check if interrupt pending (will exit HALT)
-		ie := c.Memory.Read(0xFFFF)
-		ifReg := c.Memory.Read(0xFF0F)
+		ie := c.peek(0xFFFF)
+		ifReg := c.peek(0xFF0F)
 		if (ie & ifReg & 0x1F) != 0 {
 			c.halted = false
 			// PC is currently at the HALT instruction (HALT decremented it)
@@ -93,6 +147,15 @@ func (c *CPU) Step() uint8 {
 		return 4
 	}
 
+	// Remember whether an EI was pending before this instruction. IME is only
+	// enabled once the instruction following EI has completed, so an EI executed
+	// during this step must not take effect until the end of the next step.
+	imePending := c.pendingIME
+
+	if c.tracer != nil {
+		c.trace()
+	}
+
 	// Fetch instruction
 	// Save haltBug state before fetch (for HALT handler to check)
 	c.wasHaltBug = c.haltBug
@@ -115,8 +178,9 @@ func (c *CPU) Step() uint8 {
 	// Update cycle counter
 	c.Cycles += uint64(cycles)
 
-	// Handle delayed IME from EI instruction
-	if c.pendingIME {
+	// Handle delayed IME from EI instruction.
+	// DI clears pendingIME, so "EI; DI" never enables interrupts.
+	if imePending && c.pendingIME {
 		c.IME = true
 		c.pendingIME = false
 	}
@@ -124,6 +188,52 @@ func (c *CPU) Step() uint8 {
 	return cycles
 }
 
+// Halted returns true if the CPU is in the HALT state.
+func (c *CPU) Halted() bool {
+	return c.halted
+}
+
+// TakeBreakpoint reports whether a software breakpoint (LD B,B) executed
+// since the last call, and clears the flag.
+func (c *CPU) TakeBreakpoint() bool {
+	hit := c.breakpointHit
+	c.breakpointHit = false
+	return hit
+}
+
+// TakeStop reports whether a STOP instruction executed since the last call,
+// and clears the flag. On CGB, STOP performs a prepared speed switch.
+func (c *CPU) TakeStop() bool {
+	stopped := c.stopped
+	c.stopped = false
+	return stopped
+}
+
+// SetIllegalOpcodeMode selects how undefined opcodes are handled.
+// The default is IllegalOpcodeLockup.
+func (c *CPU) SetIllegalOpcodeMode(mode IllegalOpcodeMode) {
+	c.illegalMode = mode
+}
+
+// LockedUp returns true if the CPU has locked up after executing an undefined opcode.
+func (c *CPU) LockedUp() bool {
+	return c.lockedUp
+}
+
+// LastError returns the error recorded in IllegalOpcodeError mode, or nil.
+// The error wraps ErrIllegalOpcode.
+func (c *CPU) LastError() error {
+	return c.lastErr
+}
+
+// lockUp locks up the CPU after executing the undefined opcode at PC-1.
+func (c *CPU) lockUp(opcode uint8) {
+	c.lockedUp = true
+	if c.illegalMode == IllegalOpcodeError {
+		c.lastErr = fmt.Errorf("%w 0x%02X at $%04X", ErrIllegalOpcode, opcode, c.Registers.PC-1)
+	}
+}
+
 // fetchByte fetches the next byte from memory and increments PC.
 func (c *CPU) fetchByte() uint8 {
 	value := c.Memory.Read(c.Registers.PC)
@@ -160,8 +270,26 @@ func (c *CPU) pop() uint16 {
 	return high<<8 | low
 }
 
+// peek reads memory for the CPU's own use (see Peeker).
+func (c *CPU) peek(addr uint16) uint8 {
+	if p, ok := c.Memory.(Peeker); ok {
+		return p.Peek(addr)
+	}
+	return c.Memory.Read(addr)
+}
+
+// poke writes memory for the CPU's own use (see Peeker).
+func (c *CPU) poke(addr uint16, value uint8) {
+	if p, ok := c.Memory.(Peeker); ok {
+		p.Poke(addr, value)
+		return
+	}
+	c.Memory.Write(addr, value)
+}
+
 // checkInterrupts checks for pending interrupts and services them if IME is enabled.
-// Returns the number of cycles consumed (20 if interrupt serviced, 0 otherwise).
+// Returns the number of cycles consumed (20 if interrupt serviced, 24 when waking
+// from HALT, 0 otherwise).
 func (c *CPU) checkInterrupts() uint8 {
 	// Interrupts only serviced if IME is enabled
 	if !c.IME {
@@ -169,8 +297,8 @@ func (c *CPU) checkInterrupts() uint8 {
 	}
 
 	// Read IE and IF registers
-	ie := c.Memory.Read(0xFFFF)    // Interrupt Enable
-	ifReg := c.Memory.Read(0xFF0F) // Interrupt Flag
+	ie := c.peek(0xFFFF)    // Interrupt Enable
+	ifReg := c.peek(0xFF0F) // Interrupt Flag
 
 	// Check for pending interrupts (IE & IF & 0x1F)
 	pending := ie & ifReg & 0x1F
@@ -179,40 +307,67 @@ func (c *CPU) checkInterrupts() uint8 {
 		return 0
 	}
 
-	// Find highest priority interrupt (lowest bit number)
-	for bit := uint8(0); bit < 5; bit++ {
-		if pending&(1<<bit) != 0 {
-			c.serviceInterrupt(bit)
-			return 20 // Interrupt service takes 5 M-cycles = 20 clock cycles
-		}
+	// Interrupt service takes 5 M-cycles = 20 clock cycles. Waking from HALT
+	// takes one more M-cycle, charged here rather than as a separate halted step.
+	cycles := uint8(20)
+	if c.halted {
+		cycles += 4
 	}
 
-	return 0
+	c.dispatchInterrupt()
+	return cycles
 }
 
-// serviceInterrupt services an interrupt.
-func (c *CPU) serviceInterrupt(bit uint8) {
-	// Exit HALT state if halted (interrupt wakes CPU from HALT)
+// dispatchInterrupt services the highest-priority pending interrupt, one
+// M-cycle at a time:
+//
+//   - M1-M2: internal delay. HALT is exited and IME is cleared.
+//   - M3: the high byte of PC is pushed.
+//   - M4: the low byte of PC is pushed.
+//   - M5: the interrupt is chosen and PC is set to its handler.
+//
+// IE is sampled after the high byte is pushed and IF after the low byte, so a
+// push that writes IE (SP = 0x0000 on entry) can redirect the dispatch to a
+// different interrupt or cancel it. A cancelled dispatch jumps to 0x0000 and
+// leaves IF unchanged.
+func (c *CPU) dispatchInterrupt() {
+	// M1-M2: internal delay
 	if c.halted {
 		c.halted = false
 		// When servicing an interrupt while halted, PC is at the HALT instruction
 		// We need to increment it so RET returns to the instruction AFTER HALT
 		c.Registers.PC++
 	}
-
-	// Disable interrupts
 	c.IME = false
 	c.pendingIME = false
+	fromPC := c.Registers.PC
+
+	// M3: push the high byte of PC, which may overwrite IE
+	c.Registers.SP--
+	c.Memory.Write(c.Registers.SP, uint8(fromPC>>8)) //nolint:gosec // G115: Intentional byte extraction from 16-bit value
+	ie := c.peek(0xFFFF)
 
-	// Clear IF bit for this interrupt
-	ifReg := c.Memory.Read(0xFF0F)
-	c.Memory.Write(0xFF0F, ifReg&^(1<<bit))
+	// M4: push the low byte of PC
+	c.Registers.SP--
+	c.Memory.Write(c.Registers.SP, uint8(fromPC)) //nolint:gosec // G115: Intentional byte extraction from 16-bit value
 
-	// Push PC onto stack
-	c.push(c.Registers.PC)
+	// M5: choose the highest priority interrupt (lowest bit number) and jump
+	ifReg := c.peek(0xFF0F)
+	pending := ie & ifReg & 0x1F
+	for bit := uint8(0); bit < 5; bit++ {
+		if pending&(1<<bit) != 0 {
+			c.poke(0xFF0F, ifReg&^(1<<bit))
+			c.Registers.PC = interruptHandlers[bit]
+
+			if c.OnInterrupt != nil {
+				c.OnInterrupt(bit, fromPC, c.Registers.PC)
+			}
+			return
+		}
+	}
 
-	// Jump to interrupt handler address
-	c.Registers.PC = interruptHandlers[bit]
+	// Cancelled: the pushed byte disabled every pending interrupt
+	c.Registers.PC = 0x0000
 }
 
 // Helper methods for arithmetic operations
@@ -68,6 +68,45 @@ func TestRegisters(t *testing.T) {
 	}
 }
 
+func TestPowerUpRegisters(t *testing.T) {
+	cpu, _ := setupCPU()
+	r := cpu.Registers
+
+	tests := []struct {
+		name string
+		got  uint16
+		want uint16
+	}{
+		{"AF", r.AF(), 0x01B0},
+		{"BC", r.BC(), 0x0013},
+		{"DE", r.DE(), 0x00D8},
+		{"HL", r.HL(), 0x014D},
+		{"SP", r.SP, 0xFFFE},
+		{"PC", r.PC, 0x0100},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = 0x%04X, want 0x%04X", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// F=0xB0: Z, H and C set, N clear
+	if !r.ZeroFlag() || r.SubtractFlag() || !r.HalfCarryFlag() || !r.CarryFlag() {
+		t.Errorf("Flags Z=%v N=%v H=%v C=%v, want Z=true N=false H=true C=true",
+			r.ZeroFlag(), r.SubtractFlag(), r.HalfCarryFlag(), r.CarryFlag())
+	}
+
+	if cpu.IME {
+		t.Error("IME = true, want false")
+	}
+	if cpu.Halted() {
+		t.Error("Halted() = true, want false")
+	}
+	if cpu.Cycles != 0 {
+		t.Errorf("Cycles = %d, want 0", cpu.Cycles)
+	}
+}
+
 func TestNOP(t *testing.T) {
 	mem := newMockMemory()
 	cpu := New(mem)
@@ -154,6 +193,67 @@ func TestADD8(t *testing.T) {
 	}
 }
 
+func TestADD16(t *testing.T) {
+	tests := []struct {
+		name         string
+		opcode       uint8
+		hl, operand  uint16 // operand is ignored for ADD HL,HL
+		want         uint16
+		wantH, wantC bool
+	}{
+		{"ADD HL,BC half-carry", 0x09, 0x0FFF, 0x0001, 0x1000, true, false},
+		{"ADD HL,BC carry", 0x09, 0xFFFF, 0x0001, 0x0000, true, true},
+		{"ADD HL,BC no carry", 0x09, 0x1234, 0x0100, 0x1334, false, false},
+		{"ADD HL,DE half-carry", 0x19, 0x0FFF, 0x0001, 0x1000, true, false},
+		{"ADD HL,DE carry", 0x19, 0xFFFF, 0x0001, 0x0000, true, true},
+		{"ADD HL,HL half-carry", 0x29, 0x0800, 0, 0x1000, true, false},
+		{"ADD HL,HL carry", 0x29, 0x8000, 0, 0x0000, false, true},
+		{"ADD HL,SP half-carry", 0x39, 0x0FFF, 0x0001, 0x1000, true, false},
+		{"ADD HL,SP carry", 0x39, 0xFFFF, 0x0001, 0x0000, true, true},
+	}
+
+	for _, tt := range tests {
+		// Z is never affected, even when the result is zero
+		for _, zero := range []bool{false, true} {
+			cpu, mem := setupCPU()
+			cpu.Registers.PC = 0x0100
+			mem.data[0x0100] = tt.opcode
+
+			cpu.Registers.SetHL(tt.hl)
+			switch tt.opcode {
+			case 0x09:
+				cpu.Registers.SetBC(tt.operand)
+			case 0x19:
+				cpu.Registers.SetDE(tt.operand)
+			case 0x39:
+				cpu.Registers.SP = tt.operand
+			}
+			cpu.Registers.SetFlagTo(FlagZ, zero)
+			cpu.Registers.SetFlag(FlagN)
+
+			if cycles := cpu.Step(); cycles != 8 {
+				t.Errorf("%s: cycles = %d, want 8", tt.name, cycles)
+			}
+
+			if got := cpu.Registers.HL(); got != tt.want {
+				t.Errorf("%s: HL = %04X, want %04X", tt.name, got, tt.want)
+			}
+			if got := cpu.Registers.ZeroFlag(); got != zero {
+				t.Errorf("%s: Z = %v, want %v (unchanged)", tt.name, got, zero)
+			}
+			if cpu.Registers.SubtractFlag() {
+				t.Errorf("%s: N should be cleared", tt.name)
+			}
+			if got := cpu.Registers.HalfCarryFlag(); got != tt.wantH {
+				t.Errorf("%s: H = %v, want %v", tt.name, got, tt.wantH)
+			}
+			if got := cpu.Registers.CarryFlag(); got != tt.wantC {
+				t.Errorf("%s: C = %v, want %v", tt.name, got, tt.wantC)
+			}
+		}
+	}
+}
+
 func TestSUB8(t *testing.T) {
 	mem := newMockMemory()
 	cpu := New(mem)
@@ -529,6 +629,273 @@ func TestDAA(t *testing.T) {
 	}
 }
 
+// TestDAAEdgeCases checks DAA for boundary values of A and every combination
+// of the N, H and C flags, including inputs no BCD operation produces. The
+// expected values were worked out by hand from the DAA correction rules in
+// Pan Docs.
+func TestDAAEdgeCases(t *testing.T) {
+	tests := []struct {
+		a        uint8
+		flags    uint8
+		expected uint8
+		expectC  bool
+	}{
+		// Addition
+		{0x00, 0x00, 0x00, false},
+		{0x09, 0x00, 0x09, false},
+		{0x0A, 0x00, 0x10, false},
+		{0x0F, 0x20, 0x15, false},
+		{0x7F, 0x20, 0x85, false},
+		{0x99, 0x00, 0x99, false},
+		{0x9A, 0x00, 0x00, true},
+		{0xA0, 0x00, 0x00, true},
+		{0xFF, 0x00, 0x65, true},
+		{0x00, 0x10, 0x60, true},
+		{0x00, 0x30, 0x66, true},
+		{0xFA, 0x30, 0x60, true},
+
+		// Subtraction
+		{0x00, 0x40, 0x00, false},
+		{0xFF, 0x40, 0xFF, false},
+		{0x00, 0x60, 0xFA, false},
+		{0x00, 0x50, 0xA0, true},
+		{0x00, 0x70, 0x9A, true},
+		{0x66, 0x70, 0x00, true},
+		{0x06, 0x60, 0x00, false},
+		{0x0F, 0x60, 0x09, false},
+	}
+
+	for _, tt := range tests {
+		cpu, mem := setupCPU()
+		cpu.Registers.A = tt.a
+		cpu.Registers.F = tt.flags
+		cpu.Registers.PC = 0x0100
+		mem.Write(0x0100, 0x27)
+
+		cpu.Step()
+
+		if cpu.Registers.A != tt.expected {
+			t.Errorf("DAA A=0x%02X F=0x%02X: A = 0x%02X, want 0x%02X", tt.a, tt.flags, cpu.Registers.A, tt.expected)
+		}
+		if cpu.Registers.CarryFlag() != tt.expectC {
+			t.Errorf("DAA A=0x%02X F=0x%02X: C flag = %v, want %v", tt.a, tt.flags, cpu.Registers.CarryFlag(), tt.expectC)
+		}
+		if cpu.Registers.ZeroFlag() != (tt.expected == 0) {
+			t.Errorf("DAA A=0x%02X F=0x%02X: Z flag = %v, want %v", tt.a, tt.flags, cpu.Registers.ZeroFlag(), tt.expected == 0)
+		}
+		if cpu.Registers.HalfCarryFlag() {
+			t.Errorf("DAA A=0x%02X F=0x%02X: H flag set, want clear", tt.a, tt.flags)
+		}
+	}
+}
+
+// TestDAABCDArithmetic adds and subtracts every pair of two-digit BCD
+// values, with and without carry, and checks that DAA turns the binary result
+// into the BCD result of the same decimal arithmetic.
+func TestDAABCDArithmetic(t *testing.T) {
+	bcd := func(n int) uint8 {
+		return uint8(n/10<<4 | n%10) //nolint:gosec // G115: n is within 0-99
+	}
+
+	for _, op := range []struct {
+		name   string
+		opcode uint8 // ADC A,B or SBC A,B
+		apply  func(x, y, carry int) int
+	}{
+		{"ADC", 0x88, func(x, y, carry int) int { return x + y + carry }},
+		{"SBC", 0x98, func(x, y, carry int) int { return x - y - carry }},
+	} {
+		for x := range 100 {
+			for y := range 100 {
+				for carry := range 2 {
+					result := op.apply(x, y, carry)
+					want := bcd((result + 100) % 100)
+					wantC := result < 0 || result > 99
+
+					cpu, mem := setupCPU()
+					cpu.Registers.A = bcd(x)
+					cpu.Registers.B = bcd(y)
+					cpu.Registers.F = 0
+					if carry == 1 {
+						cpu.Registers.F = FlagC
+					}
+					cpu.Registers.PC = 0x0100
+					mem.Write(0x0100, op.opcode)
+					mem.Write(0x0101, 0x27) // DAA
+
+					cpu.Step()
+					cpu.Step()
+
+					if cpu.Registers.A != want || cpu.Registers.CarryFlag() != wantC ||
+						cpu.Registers.ZeroFlag() != (want == 0) || cpu.Registers.HalfCarryFlag() {
+						t.Errorf("%s %02d,%02d carry %d: got A=0x%02X F=0x%02X, want A=0x%02X C=%v",
+							op.name, x, y, carry, cpu.Registers.A, cpu.Registers.F, want, wantC)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestSPOffsetFlags checks ADD SP,n and LD HL,SP+n with positive and negative
+// offsets. H and C come from the unsigned addition of the low nibble and low
+// byte of SP and the offset byte, regardless of the offset's sign.
+func TestSPOffsetFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		sp       uint16
+		offset   uint8
+		expected uint16
+		expectH  bool
+		expectC  bool
+	}{
+		{"0x000F - 1", 0x000F, 0xFF, 0x000E, true, true},
+		{"0x0000 - 1", 0x0000, 0xFF, 0xFFFF, false, false},
+		{"0xFFF8 - 8", 0xFFF8, 0xF8, 0xFFF0, true, true},
+		{"0x0100 - 128", 0x0100, 0x80, 0x0080, false, false},
+		{"0xD000 - 2", 0xD000, 0xFE, 0xCFFE, false, false},
+		{"0x00FF + 1", 0x00FF, 0x01, 0x0100, true, true},
+		{"0x0008 + 8", 0x0008, 0x08, 0x0010, true, false},
+	}
+
+	for _, op := range []struct {
+		name   string
+		opcode uint8
+	}{
+		{"ADD SP,n", 0xE8},
+		{"LD HL,SP+n", 0xF8},
+	} {
+		for _, tt := range tests {
+			t.Run(op.name+" "+tt.name, func(t *testing.T) {
+				cpu, mem := setupCPU()
+				cpu.Registers.SP = tt.sp
+				cpu.Registers.F = FlagZ | FlagN
+				cpu.Registers.PC = 0x0100
+				mem.Write(0x0100, op.opcode)
+				mem.Write(0x0101, tt.offset)
+
+				cpu.Step()
+
+				got := cpu.Registers.SP
+				if op.opcode == 0xF8 {
+					got = cpu.Registers.HL()
+					if cpu.Registers.SP != tt.sp {
+						t.Errorf("SP = 0x%04X, want 0x%04X (unchanged)", cpu.Registers.SP, tt.sp)
+					}
+				}
+				if got != tt.expected {
+					t.Errorf("result = 0x%04X, want 0x%04X", got, tt.expected)
+				}
+				if cpu.Registers.HalfCarryFlag() != tt.expectH {
+					t.Errorf("H flag = %v, want %v", cpu.Registers.HalfCarryFlag(), tt.expectH)
+				}
+				if cpu.Registers.CarryFlag() != tt.expectC {
+					t.Errorf("C flag = %v, want %v", cpu.Registers.CarryFlag(), tt.expectC)
+				}
+				if cpu.Registers.ZeroFlag() || cpu.Registers.SubtractFlag() {
+					t.Errorf("Z/N flags = %v/%v, want cleared", cpu.Registers.ZeroFlag(), cpu.Registers.SubtractFlag())
+				}
+			})
+		}
+	}
+}
+
+func TestLDnnSP(t *testing.T) {
+	tests := []struct {
+		name   string
+		addr   uint16
+		lowAt  uint16
+		highAt uint16
+	}{
+		{"WRAM", 0xC123, 0xC123, 0xC124},
+		{"HRAM end", 0xFFFE, 0xFFFE, 0xFFFF},
+		{"wraps to 0x0000", 0xFFFF, 0xFFFF, 0x0000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, mem := setupCPU()
+			cpu.Registers.SP = 0xBEEF
+			cpu.Registers.PC = 0x0100
+			mem.Write(0x0100, 0x08)
+			mem.Write(0x0101, uint8(tt.addr))    //nolint:gosec // G115: Intentional byte extraction
+			mem.Write(0x0102, uint8(tt.addr>>8)) //nolint:gosec // G115: Intentional byte extraction
+
+			cycles := cpu.Step()
+
+			if cycles != 20 {
+				t.Errorf("cycles = %d, want 20", cycles)
+			}
+			if mem.data[tt.lowAt] != 0xEF {
+				t.Errorf("[0x%04X] = 0x%02X, want 0xEF", tt.lowAt, mem.data[tt.lowAt])
+			}
+			if mem.data[tt.highAt] != 0xBE {
+				t.Errorf("[0x%04X] = 0x%02X, want 0xBE", tt.highAt, mem.data[tt.highAt])
+			}
+			if cpu.Registers.PC != 0x0103 {
+				t.Errorf("PC = 0x%04X, want 0x0103", cpu.Registers.PC)
+			}
+		})
+	}
+}
+
+func TestStackWrap(t *testing.T) {
+	tests := []struct {
+		name   string
+		sp     uint16
+		pushSP uint16
+		lowAt  uint16
+		highAt uint16
+	}{
+		{"SP=0x0000", 0x0000, 0xFFFE, 0xFFFE, 0xFFFF},
+		{"SP=0x0001", 0x0001, 0xFFFF, 0xFFFF, 0x0000},
+		{"SP=0x0002", 0x0002, 0x0000, 0x0000, 0x0001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, mem := setupCPU()
+			cpu.Registers.SP = tt.sp
+
+			cpu.push(0x1234)
+
+			if cpu.Registers.SP != tt.pushSP {
+				t.Errorf("SP after push = 0x%04X, want 0x%04X", cpu.Registers.SP, tt.pushSP)
+			}
+			if mem.data[tt.lowAt] != 0x34 {
+				t.Errorf("[0x%04X] = 0x%02X, want 0x34", tt.lowAt, mem.data[tt.lowAt])
+			}
+			if mem.data[tt.highAt] != 0x12 {
+				t.Errorf("[0x%04X] = 0x%02X, want 0x12", tt.highAt, mem.data[tt.highAt])
+			}
+
+			if got := cpu.pop(); got != 0x1234 {
+				t.Errorf("pop() = 0x%04X, want 0x1234", got)
+			}
+			if cpu.Registers.SP != tt.sp {
+				t.Errorf("SP after pop = 0x%04X, want 0x%04X", cpu.Registers.SP, tt.sp)
+			}
+		})
+	}
+}
+
+func TestFetchWordWrap(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.Registers.PC = 0xFFFE
+	mem.Write(0xFFFE, 0x01) // LD BC, nn
+	mem.Write(0xFFFF, 0x34)
+	mem.Write(0x0000, 0x12)
+
+	cpu.Step()
+
+	if cpu.Registers.BC() != 0x1234 {
+		t.Errorf("BC = 0x%04X, want 0x1234", cpu.Registers.BC())
+	}
+	if cpu.Registers.PC != 0x0001 {
+		t.Errorf("PC = 0x%04X, want 0x0001", cpu.Registers.PC)
+	}
+}
+
 func TestConditionalJumps(t *testing.T) {
 	cpu, mem := setupCPU()
 
@@ -1059,3 +1426,370 @@ func TestHALTBugRepeatedHALT(t *testing.T) {
 		t.Errorf("PC = 0x%04X, want 0x0102 after exiting second HALT", cpu.Registers.PC)
 	}
 }
+
+// TestEIDelay tests that EI only enables interrupts after the following instruction.
+func TestEIDelay(t *testing.T) {
+	cpu, mem := setupCPU()
+
+	cpu.Registers.PC = 0x0100
+	cpu.Registers.SP = 0xFFFE
+	mem.data[0x0100] = 0xFB // EI
+	mem.data[0x0101] = 0x00 // NOP
+	mem.data[0x0102] = 0x00 // NOP
+
+	// V-Blank interrupt enabled and pending
+	mem.data[0xFFFF] = 0x01
+	mem.data[0xFF0F] = 0x01
+
+	// Execute EI
+	cpu.Step()
+	if cpu.IME {
+		t.Error("IME should not be enabled immediately after EI")
+	}
+
+	// Execute NOP - the interrupt must not be serviced before it
+	cpu.Step()
+	if cpu.Registers.PC != 0x0102 {
+		t.Fatalf("PC after EI; NOP = 0x%04X, want 0x0102", cpu.Registers.PC)
+	}
+	if !cpu.IME {
+		t.Error("IME should be enabled after the instruction following EI")
+	}
+
+	// Next step services the interrupt
+	cycles := cpu.Step()
+	if cycles != 20 {
+		t.Errorf("Interrupt service cycles = %d, want 20", cycles)
+	}
+	if cpu.Registers.PC != 0x0040 {
+		t.Errorf("PC after interrupt = 0x%04X, want 0x0040", cpu.Registers.PC)
+	}
+	if mem.data[0xFF0F]&0x01 != 0 {
+		t.Error("V-Blank IF bit should be cleared after servicing")
+	}
+}
+
+// TestEIDI tests that EI immediately followed by DI never enables interrupts.
+func TestEIDI(t *testing.T) {
+	cpu, mem := setupCPU()
+
+	cpu.Registers.PC = 0x0100
+	mem.data[0x0100] = 0xFB // EI
+	mem.data[0x0101] = 0xF3 // DI
+	mem.data[0x0102] = 0x00 // NOP
+
+	// V-Blank interrupt enabled and pending
+	mem.data[0xFFFF] = 0x01
+	mem.data[0xFF0F] = 0x01
+
+	for range 3 {
+		cpu.Step()
+	}
+
+	if cpu.IME {
+		t.Error("IME should remain disabled after EI; DI")
+	}
+	if cpu.Registers.PC != 0x0103 {
+		t.Errorf("PC after EI; DI; NOP = 0x%04X, want 0x0103 (interrupt must not fire)", cpu.Registers.PC)
+	}
+	if mem.data[0xFF0F]&0x01 == 0 {
+		t.Error("V-Blank IF bit should still be pending")
+	}
+}
+
+// TestRETIPendingInterrupt tests that RETI enables interrupts immediately, so
+// an interrupt pending when it returns is serviced by the very next Step.
+func TestRETIPendingInterrupt(t *testing.T) {
+	cpu, mem := setupCPU()
+
+	cpu.Registers.PC = 0x0100
+	cpu.Registers.SP = 0xFFFC
+	mem.data[0xFFFC] = 0x00 // Return address 0x0200
+	mem.data[0xFFFD] = 0x02
+	mem.data[0x0100] = 0xD9 // RETI
+	mem.data[0x0200] = 0x00 // NOP
+
+	// V-Blank interrupt enabled and pending
+	mem.data[0xFFFF] = 0x01
+	mem.data[0xFF0F] = 0x01
+
+	cpu.Step()
+	if cpu.Registers.PC != 0x0200 || !cpu.IME {
+		t.Fatalf("after RETI PC = 0x%04X, IME = %v, want 0x0200, true", cpu.Registers.PC, cpu.IME)
+	}
+
+	// The next step services the interrupt without running the NOP
+	if cycles := cpu.Step(); cycles != 20 {
+		t.Errorf("Step() after RETI = %d cycles, want 20 (interrupt dispatch)", cycles)
+	}
+	if cpu.Registers.PC != 0x0040 {
+		t.Errorf("PC = 0x%04X, want 0x0040", cpu.Registers.PC)
+	}
+	if ret := uint16(mem.data[0xFFFD])<<8 | uint16(mem.data[0xFFFC]); ret != 0x0200 {
+		t.Errorf("pushed return address = 0x%04X, want 0x0200", ret)
+	}
+}
+
+// TestRETEIPendingInterrupt tests that RET followed by EI runs one more
+// instruction before a pending interrupt is serviced, unlike RETI.
+func TestRETEIPendingInterrupt(t *testing.T) {
+	cpu, mem := setupCPU()
+
+	cpu.Registers.PC = 0x0100
+	cpu.Registers.SP = 0xFFFC
+	mem.data[0xFFFC] = 0x00 // Return address 0x0200
+	mem.data[0xFFFD] = 0x02
+	mem.data[0x0100] = 0xC9 // RET
+	mem.data[0x0200] = 0xFB // EI
+	mem.data[0x0201] = 0x00 // NOP
+
+	// V-Blank interrupt enabled and pending
+	mem.data[0xFFFF] = 0x01
+	mem.data[0xFF0F] = 0x01
+
+	cpu.Step() // RET
+	if cpu.IME {
+		t.Fatal("IME should not be enabled by RET")
+	}
+	cpu.Step() // EI
+	cpu.Step() // NOP runs before the interrupt
+	if cpu.Registers.PC != 0x0202 {
+		t.Fatalf("PC after RET; EI; NOP = 0x%04X, want 0x0202", cpu.Registers.PC)
+	}
+
+	if cycles := cpu.Step(); cycles != 20 {
+		t.Errorf("Step() after NOP = %d cycles, want 20 (interrupt dispatch)", cycles)
+	}
+	if cpu.Registers.PC != 0x0040 {
+		t.Errorf("PC = 0x%04X, want 0x0040", cpu.Registers.PC)
+	}
+	if ret := uint16(mem.data[0xFFFD])<<8 | uint16(mem.data[0xFFFC]); ret != 0x0202 {
+		t.Errorf("pushed return address = 0x%04X, want 0x0202", ret)
+	}
+}
+
+// TestEIHALT tests that EI followed by HALT wakes and services a pending interrupt.
+func TestEIHALT(t *testing.T) {
+	cpu, mem := setupCPU()
+
+	cpu.Registers.PC = 0x0100
+	cpu.Registers.SP = 0xFFFE
+	mem.data[0x0100] = 0xFB // EI
+	mem.data[0x0101] = 0x76 // HALT
+	mem.data[0x0102] = 0x00 // NOP
+
+	// Execute EI and HALT with no interrupt pending
+	cpu.Step()
+	cpu.Step()
+
+	if !cpu.halted {
+		t.Fatal("CPU should be halted after EI; HALT")
+	}
+	if !cpu.IME {
+		t.Fatal("IME should be enabled after the HALT following EI")
+	}
+
+	// Raise a timer interrupt
+	mem.data[0xFFFF] = 0x04
+	mem.data[0xFF0F] = 0x04
+
+	// Waking from HALT (4) plus interrupt dispatch (20)
+	cycles := cpu.Step()
+	if cycles != 24 {
+		t.Errorf("Interrupt service cycles = %d, want 24", cycles)
+	}
+	if cpu.halted {
+		t.Error("CPU should not be halted after interrupt is serviced")
+	}
+	if cpu.Registers.PC != 0x0050 {
+		t.Errorf("PC after interrupt = 0x%04X, want 0x0050", cpu.Registers.PC)
+	}
+
+	// Return address must be the instruction after HALT
+	ret := uint16(mem.data[cpu.Registers.SP]) | uint16(mem.data[cpu.Registers.SP+1])<<8
+	if ret != 0x0102 {
+		t.Errorf("Return address = 0x%04X, want 0x0102", ret)
+	}
+	if cpu.haltBug {
+		t.Error("HALT bug must not trigger when IME is enabled")
+	}
+}
+
+// TestInterruptDispatchCycles tests that interrupt dispatch is charged once, as 5 M-cycles.
+func TestInterruptDispatchCycles(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.Registers.PC = 0x0100
+	cpu.IME = true
+	mem.data[0xFFFF] = 0x04 // IE: Timer enabled
+	mem.data[0xFF0F] = 0x04 // IF: Timer pending
+	mem.data[0x0050] = 0x00 // NOP in the timer handler
+
+	start := cpu.Cycles
+	if cycles := cpu.Step(); cycles != 20 {
+		t.Errorf("dispatch cycles = %d, want 20", cycles)
+	}
+	if cpu.Registers.PC != 0x0050 {
+		t.Fatalf("PC = 0x%04X, want 0x0050", cpu.Registers.PC)
+	}
+
+	// The handler's first instruction runs without a second dispatch charge
+	if cycles := cpu.Step(); cycles != 4 {
+		t.Errorf("handler NOP cycles = %d, want 4", cycles)
+	}
+	if got := cpu.Cycles - start; got != 24 {
+		t.Errorf("total cycles = %d, want 24", got)
+	}
+}
+
+// TestOnInterrupt tests that the interrupt callback reports the serviced
+// interrupt, the return address and the handler address.
+func TestOnInterrupt(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.Registers.PC = 0x0123
+	cpu.IME = true
+	mem.data[0xFFFF] = 0x04 // IE: Timer enabled
+	mem.data[0xFF0F] = 0x04 // IF: Timer pending
+
+	calls := 0
+	cpu.OnInterrupt = func(bit uint8, fromPC, toPC uint16) {
+		calls++
+		if bit != 2 {
+			t.Errorf("bit = %d, want 2", bit)
+		}
+		if fromPC != 0x0123 {
+			t.Errorf("fromPC = 0x%04X, want 0x0123", fromPC)
+		}
+		if toPC != 0x0050 {
+			t.Errorf("toPC = 0x%04X, want 0x0050", toPC)
+		}
+	}
+
+	cpu.Step()
+	if calls != 1 {
+		t.Errorf("OnInterrupt calls = %d, want 1", calls)
+	}
+}
+
+// TestInterruptDispatchIEPush tests the ie_push quirk: the interrupt is
+// chosen after PC's high byte is pushed, so a push that writes IE can redirect
+// or cancel the dispatch.
+func TestInterruptDispatchIEPush(t *testing.T) {
+	tests := []struct {
+		name     string
+		sp       uint16
+		pc       uint16
+		wantPC   uint16
+		wantIF   uint8
+		wantCall bool
+	}{
+		// High byte 0x00 lands in IE and disables everything: cancelled
+		{"cancelled", 0x0000, 0x0023, 0x0000, 0x05, false},
+		// High byte 0x04 lands in IE and leaves only the timer enabled
+		{"redirected to timer", 0x0000, 0x0423, 0x0050, 0x01, true},
+		// High byte 0x01 keeps V-Blank enabled
+		{"unchanged", 0x0000, 0x0123, 0x0040, 0x04, true},
+		// Only the low byte lands in IE, after the interrupt is chosen
+		{"low byte in IE", 0x0001, 0x0100, 0x0040, 0x04, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, mem := setupCPU()
+			cpu.Registers.PC = tt.pc
+			cpu.Registers.SP = tt.sp
+			cpu.IME = true
+			mem.data[0xFFFF] = 0x01 // IE: V-Blank enabled
+			mem.data[0xFF0F] = 0x05 // IF: V-Blank and Timer pending
+
+			called := false
+			cpu.OnInterrupt = func(uint8, uint16, uint16) { called = true }
+
+			if cycles := cpu.Step(); cycles != 20 {
+				t.Errorf("dispatch cycles = %d, want 20", cycles)
+			}
+			if cpu.Registers.PC != tt.wantPC {
+				t.Errorf("PC = 0x%04X, want 0x%04X", cpu.Registers.PC, tt.wantPC)
+			}
+			if mem.data[0xFF0F] != tt.wantIF {
+				t.Errorf("IF = 0x%02X, want 0x%02X", mem.data[0xFF0F], tt.wantIF)
+			}
+			if cpu.IME {
+				t.Error("IME = true after dispatch, want false")
+			}
+			if called != tt.wantCall {
+				t.Errorf("OnInterrupt called = %v, want %v", called, tt.wantCall)
+			}
+
+			// The return address is pushed either way
+			if sp := tt.sp - 2; cpu.Registers.SP != sp {
+				t.Errorf("SP = 0x%04X, want 0x%04X", cpu.Registers.SP, sp)
+			}
+			if got := uint16(mem.data[tt.sp-1])<<8 | uint16(mem.data[tt.sp-2]); got != tt.pc {
+				t.Errorf("pushed PC = 0x%04X, want 0x%04X", got, tt.pc)
+			}
+		})
+	}
+}
+
+// TestHALTWakeInterruptCycles tests the cycle accounting of waking from HALT
+// with IME=1: one M-cycle to wake plus 5 M-cycles to dispatch, in one step.
+func TestHALTWakeInterruptCycles(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.Registers.PC = 0x0100
+	cpu.Registers.SP = 0xFFFE
+	cpu.IME = true
+	mem.data[0x0100] = 0x76 // HALT
+	mem.data[0xFFFF] = 0x01 // IE: V-Blank enabled
+
+	cpu.Step() // HALT
+
+	// Halted steps take one M-cycle each
+	for range 3 {
+		if cycles := cpu.Step(); cycles != 4 {
+			t.Errorf("halted cycles = %d, want 4", cycles)
+		}
+	}
+
+	mem.data[0xFF0F] = 0x01 // IF: V-Blank pending
+	start := cpu.Cycles
+	if cycles := cpu.Step(); cycles != 24 {
+		t.Errorf("wake and dispatch cycles = %d, want 24", cycles)
+	}
+	if got := cpu.Cycles - start; got != 24 {
+		t.Errorf("Cycles advanced by %d, want 24", got)
+	}
+	if cpu.Registers.PC != 0x0040 {
+		t.Errorf("PC = 0x%04X, want 0x0040", cpu.Registers.PC)
+	}
+	if ret := uint16(mem.data[0xFFFC]) | uint16(mem.data[0xFFFD])<<8; ret != 0x0101 {
+		t.Errorf("return address = 0x%04X, want 0x0101", ret)
+	}
+
+	// The handler runs next; nothing else is charged for the interrupt
+	if cycles := cpu.Step(); cycles != 4 {
+		t.Errorf("handler NOP cycles = %d, want 4", cycles)
+	}
+}
+
+// TestHALTWakeNoIMECycles tests that waking from HALT with IME=0 takes one
+// M-cycle and is not followed by a dispatch charge.
+func TestHALTWakeNoIMECycles(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.Registers.PC = 0x0100
+	mem.data[0x0100] = 0x76 // HALT
+	mem.data[0x0101] = 0x00 // NOP
+	mem.data[0xFFFF] = 0x01 // IE: V-Blank enabled
+
+	cpu.Step() // HALT
+
+	mem.data[0xFF0F] = 0x01 // IF: V-Blank pending
+	if cycles := cpu.Step(); cycles != 4 {
+		t.Errorf("wake cycles = %d, want 4", cycles)
+	}
+	if cycles := cpu.Step(); cycles != 4 {
+		t.Errorf("NOP after wake cycles = %d, want 4", cycles)
+	}
+	if mem.data[0xFF0F]&0x01 == 0 {
+		t.Error("IF was cleared, want the interrupt left pending with IME=0")
+	}
+}
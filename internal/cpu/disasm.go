@@ -0,0 +1,149 @@
+package cpu
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operand placeholders used in the mnemonic tables.
+// d8/d16 are immediate data, a8/a16 are addresses and r8 is a signed offset.
+const (
+	operandD8  = "d8"
+	operandD16 = "d16"
+	operandA8  = "a8"
+	operandA16 = "a16"
+	operandR8  = "r8"
+)
+
+// mnemonics holds the mnemonic templates for the unprefixed opcodes.
+// Invalid opcodes are listed as "DB $XX" so they disassemble to a raw byte.
+var mnemonics = [256]string{
+	// 0x00-0x0F
+	"NOP", "LD BC,d16", "LD (BC),A", "INC BC", "INC B", "DEC B", "LD B,d8", "RLCA",
+	"LD (a16),SP", "ADD HL,BC", "LD A,(BC)", "DEC BC", "INC C", "DEC C", "LD C,d8", "RRCA",
+	// 0x10-0x1F
+	"STOP", "LD DE,d16", "LD (DE),A", "INC DE", "INC D", "DEC D", "LD D,d8", "RLA",
+	"JR r8", "ADD HL,DE", "LD A,(DE)", "DEC DE", "INC E", "DEC E", "LD E,d8", "RRA",
+	// 0x20-0x2F
+	"JR NZ,r8", "LD HL,d16", "LD (HL+),A", "INC HL", "INC H", "DEC H", "LD H,d8", "DAA",
+	"JR Z,r8", "ADD HL,HL", "LD A,(HL+)", "DEC HL", "INC L", "DEC L", "LD L,d8", "CPL",
+	// 0x30-0x3F
+	"JR NC,r8", "LD SP,d16", "LD (HL-),A", "INC SP", "INC (HL)", "DEC (HL)", "LD (HL),d8", "SCF",
+	"JR C,r8", "ADD HL,SP", "LD A,(HL-)", "DEC SP", "INC A", "DEC A", "LD A,d8", "CCF",
+	// 0x40-0x7F are generated in init (LD r,r' and HALT)
+	0x40: "", 0x7F: "",
+	// 0x80-0xBF are generated in init (ALU A,r)
+	0x80: "", 0xBF: "",
+	// 0xC0-0xCF
+	0xC0: "RET NZ", "POP BC", "JP NZ,a16", "JP a16", "CALL NZ,a16", "PUSH BC", "ADD A,d8", "RST 00H",
+	"RET Z", "RET", "JP Z,a16", "PREFIX CB", "CALL Z,a16", "CALL a16", "ADC A,d8", "RST 08H",
+	// 0xD0-0xDF
+	"RET NC", "POP DE", "JP NC,a16", "DB $D3", "CALL NC,a16", "PUSH DE", "SUB d8", "RST 10H",
+	"RET C", "RETI", "JP C,a16", "DB $DB", "CALL C,a16", "DB $DD", "SBC A,d8", "RST 18H",
+	// 0xE0-0xEF
+	"LDH (a8),A", "POP HL", "LD (C),A", "DB $E3", "DB $E4", "PUSH HL", "AND d8", "RST 20H",
+	"ADD SP,r8", "JP (HL)", "LD (a16),A", "DB $EB", "DB $EC", "DB $ED", "XOR d8", "RST 28H",
+	// 0xF0-0xFF
+	"LDH A,(a8)", "POP AF", "LD A,(C)", "DI", "DB $F4", "PUSH AF", "OR d8", "RST 30H",
+	"LD HL,SP+r8", "LD SP,HL", "LD A,(a16)", "EI", "DB $FC", "DB $FD", "CP d8", "RST 38H",
+}
+
+// cbMnemonics holds the mnemonics for the CB-prefixed opcodes.
+var cbMnemonics [256]string
+
+// registerNames lists 8-bit operands in opcode encoding order.
+var registerNames = [8]string{"B", "C", "D", "E", "H", "L", "(HL)", "A"}
+
+func init() {
+	// 0x40-0x7F: LD r,r' (0x76 is HALT)
+	for op := 0x40; op < 0x80; op++ {
+		if op == 0x76 {
+			mnemonics[op] = "HALT"
+			continue
+		}
+		mnemonics[op] = "LD " + registerNames[(op>>3)&0x07] + "," + registerNames[op&0x07]
+	}
+
+	// 0x80-0xBF: ALU A,r
+	aluOps := [8]string{"ADD A,", "ADC A,", "SUB ", "SBC A,", "AND ", "XOR ", "OR ", "CP "}
+	for op := 0x80; op < 0xC0; op++ {
+		mnemonics[op] = aluOps[(op>>3)&0x07] + registerNames[op&0x07]
+	}
+
+	// CB prefix: rotates/shifts, then BIT, RES, SET
+	shiftOps := [8]string{"RLC", "RRC", "RL", "RR", "SLA", "SRA", "SWAP", "SRL"}
+	for op := range 256 {
+		reg := registerNames[op&0x07]
+		bit := (op >> 3) & 0x07
+		switch op >> 6 {
+		case 0:
+			cbMnemonics[op] = shiftOps[bit] + " " + reg
+		case 1:
+			cbMnemonics[op] = fmt.Sprintf("BIT %d,%s", bit, reg)
+		case 2:
+			cbMnemonics[op] = fmt.Sprintf("RES %d,%s", bit, reg)
+		case 3:
+			cbMnemonics[op] = fmt.Sprintf("SET %d,%s", bit, reg)
+		}
+	}
+}
+
+// InstructionLength returns the length in bytes of the instruction starting with opcode.
+func InstructionLength(opcode uint8) uint16 {
+	if opcode == 0xCB || opcode == 0x10 {
+		return 2
+	}
+	m := mnemonics[opcode]
+	switch {
+	case strings.Contains(m, operandD16), strings.Contains(m, operandA16):
+		return 3
+	case strings.Contains(m, operandD8), strings.Contains(m, operandA8), strings.Contains(m, operandR8):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Disassemble decodes the instruction at addr and returns its mnemonic and length in bytes.
+// Memory is only read, so it is safe to call on a live bus.
+func Disassemble(mem Memory, addr uint16) (string, uint16) {
+	opcode := mem.Read(addr)
+	length := InstructionLength(opcode)
+
+	switch opcode {
+	case 0xCB:
+		return cbMnemonics[mem.Read(addr+1)], length
+	case 0x10:
+		return "STOP", length
+	}
+
+	m := mnemonics[opcode]
+	switch length {
+	case 3:
+		value := uint16(mem.Read(addr+1)) | uint16(mem.Read(addr+2))<<8
+		m = strings.Replace(m, operandD16, fmt.Sprintf("$%04X", value), 1)
+		m = strings.Replace(m, operandA16, fmt.Sprintf("$%04X", value), 1)
+	case 2:
+		value := mem.Read(addr + 1)
+		switch {
+		case strings.Contains(m, operandR8):
+			offset := int8(value) //nolint:gosec // G115: Intentional signed conversion for relative offset
+			switch {
+			case strings.HasPrefix(m, "JR"):
+				// Show the jump target rather than the raw offset
+				target := uint16(int32(addr) + 2 + int32(offset)) //nolint:gosec // G115: Intentional for address calculation
+				m = strings.Replace(m, operandR8, fmt.Sprintf("$%04X", target), 1)
+			case strings.Contains(m, "+"+operandR8):
+				m = strings.Replace(m, "+"+operandR8, fmt.Sprintf("%+d", offset), 1)
+			default:
+				m = strings.Replace(m, operandR8, fmt.Sprintf("%d", offset), 1)
+			}
+		case strings.Contains(m, operandA8):
+			m = strings.Replace(m, operandA8, fmt.Sprintf("$FF%02X", value), 1)
+		default:
+			m = strings.Replace(m, operandD8, fmt.Sprintf("$%02X", value), 1)
+		}
+	}
+
+	return m, length
+}
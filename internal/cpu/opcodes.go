@@ -1,240 +1,314 @@
 package cpu
 
+// opcodeHandler executes one opcode and returns the number of cycles taken.
+type opcodeHandler func(c *CPU) uint8
+
 // execute executes a standard (non-CB) opcode and returns the number of cycles taken.
-//
-//nolint:gocognit,gocyclo // High complexity is inherent to opcode decoding (256 instructions)
 func (c *CPU) execute(opcode uint8) uint8 {
-	switch opcode {
+	return opcodeTable[opcode](c)
+}
+
+// illegalOpcode returns the handler for an invalid opcode, which locks up the CPU.
+func illegalOpcode(opcode uint8) opcodeHandler {
+	return func(c *CPU) uint8 {
+		c.lockUp(opcode)
+		return 4
+	}
+}
+
+// opcodeTable dispatches the unprefixed opcodes. Every entry is non-nil;
+// invalid opcodes map to illegalOpcode handlers.
+var opcodeTable = [256]opcodeHandler{
 	// 0x00-0x0F
-	case 0x00: // NOP
+	0x00: func(_ *CPU) uint8 { // NOP
 		return 4
-	case 0x01: // LD BC, nn
+	},
+	0x01: func(c *CPU) uint8 { // LD BC, nn
 		c.Registers.SetBC(c.fetchWord())
 		return 12
-	case 0x02: // LD (BC), A
+	},
+	0x02: func(c *CPU) uint8 { // LD (BC), A
 		c.Memory.Write(c.Registers.BC(), c.Registers.A)
 		return 8
-	case 0x03: // INC BC
+	},
+	0x03: func(c *CPU) uint8 { // INC BC
 		c.Registers.SetBC(c.Registers.BC() + 1)
 		return 8
-	case 0x04: // INC B
+	},
+	0x04: func(c *CPU) uint8 { // INC B
 		c.Registers.B = c.inc8(c.Registers.B)
 		return 4
-	case 0x05: // DEC B
+	},
+	0x05: func(c *CPU) uint8 { // DEC B
 		c.Registers.B = c.dec8(c.Registers.B)
 		return 4
-	case 0x06: // LD B, n
+	},
+	0x06: func(c *CPU) uint8 { // LD B, n
 		c.Registers.B = c.fetchByte()
 		return 8
-	case 0x07: // RLCA
+	},
+	0x07: func(c *CPU) uint8 { // RLCA
 		c.Registers.A = c.rlc(c.Registers.A)
 		c.Registers.ClearFlag(FlagZ) // RLCA always clears Z
 		return 4
-	case 0x08: // LD (nn), SP
+	},
+	0x08: func(c *CPU) uint8 { // LD (nn), SP
 		addr := c.fetchWord()
 		c.Memory.Write(addr, uint8(c.Registers.SP))      //nolint:gosec // G115: Intentional byte extraction
 		c.Memory.Write(addr+1, uint8(c.Registers.SP>>8)) //nolint:gosec // G115: Intentional byte extraction
 		return 20
-	case 0x09: // ADD HL, BC
+	},
+	0x09: func(c *CPU) uint8 { // ADD HL, BC
 		c.Registers.SetHL(c.add16(c.Registers.HL(), c.Registers.BC()))
 		return 8
-	case 0x0A: // LD A, (BC)
+	},
+	0x0A: func(c *CPU) uint8 { // LD A, (BC)
 		c.Registers.A = c.Memory.Read(c.Registers.BC())
 		return 8
-	case 0x0B: // DEC BC
+	},
+	0x0B: func(c *CPU) uint8 { // DEC BC
 		c.Registers.SetBC(c.Registers.BC() - 1)
 		return 8
-	case 0x0C: // INC C
+	},
+	0x0C: func(c *CPU) uint8 { // INC C
 		c.Registers.C = c.inc8(c.Registers.C)
 		return 4
-	case 0x0D: // DEC C
+	},
+	0x0D: func(c *CPU) uint8 { // DEC C
 		c.Registers.C = c.dec8(c.Registers.C)
 		return 4
-	case 0x0E: // LD C, n
+	},
+	0x0E: func(c *CPU) uint8 { // LD C, n
 		c.Registers.C = c.fetchByte()
 		return 8
-	case 0x0F: // RRCA
+	},
+	0x0F: func(c *CPU) uint8 { // RRCA
 		c.Registers.A = c.rrc(c.Registers.A)
 		c.Registers.ClearFlag(FlagZ) // RRCA always clears Z
 		return 4
-
+	},
 	// 0x10-0x1F
-	case 0x10: // STOP
+	0x10: func(c *CPU) uint8 { // STOP
 		c.stopped = true
 		c.fetchByte() // STOP is 2 bytes
 		return 4
-	case 0x11: // LD DE, nn
+	},
+	0x11: func(c *CPU) uint8 { // LD DE, nn
 		c.Registers.SetDE(c.fetchWord())
 		return 12
-	case 0x12: // LD (DE), A
+	},
+	0x12: func(c *CPU) uint8 { // LD (DE), A
 		c.Memory.Write(c.Registers.DE(), c.Registers.A)
 		return 8
-	case 0x13: // INC DE
+	},
+	0x13: func(c *CPU) uint8 { // INC DE
 		c.Registers.SetDE(c.Registers.DE() + 1)
 		return 8
-	case 0x14: // INC D
+	},
+	0x14: func(c *CPU) uint8 { // INC D
 		c.Registers.D = c.inc8(c.Registers.D)
 		return 4
-	case 0x15: // DEC D
+	},
+	0x15: func(c *CPU) uint8 { // DEC D
 		c.Registers.D = c.dec8(c.Registers.D)
 		return 4
-	case 0x16: // LD D, n
+	},
+	0x16: func(c *CPU) uint8 { // LD D, n
 		c.Registers.D = c.fetchByte()
 		return 8
-	case 0x17: // RLA
+	},
+	0x17: func(c *CPU) uint8 { // RLA
 		c.Registers.A = c.rl(c.Registers.A)
 		c.Registers.ClearFlag(FlagZ) // RLA always clears Z
 		return 4
-	case 0x18: // JR n
+	},
+	0x18: func(c *CPU) uint8 { // JR n
 		offset := int8(c.fetchByte())                                  //nolint:gosec // G115: Intentional signed conversion for relative jump
 		c.Registers.PC = uint16(int32(c.Registers.PC) + int32(offset)) //nolint:gosec // G115: Intentional for address calculation
 		return 12
-	case 0x19: // ADD HL, DE
+	},
+	0x19: func(c *CPU) uint8 { // ADD HL, DE
 		c.Registers.SetHL(c.add16(c.Registers.HL(), c.Registers.DE()))
 		return 8
-	case 0x1A: // LD A, (DE)
+	},
+	0x1A: func(c *CPU) uint8 { // LD A, (DE)
 		c.Registers.A = c.Memory.Read(c.Registers.DE())
 		return 8
-	case 0x1B: // DEC DE
+	},
+	0x1B: func(c *CPU) uint8 { // DEC DE
 		c.Registers.SetDE(c.Registers.DE() - 1)
 		return 8
-	case 0x1C: // INC E
+	},
+	0x1C: func(c *CPU) uint8 { // INC E
 		c.Registers.E = c.inc8(c.Registers.E)
 		return 4
-	case 0x1D: // DEC E
+	},
+	0x1D: func(c *CPU) uint8 { // DEC E
 		c.Registers.E = c.dec8(c.Registers.E)
 		return 4
-	case 0x1E: // LD E, n
+	},
+	0x1E: func(c *CPU) uint8 { // LD E, n
 		c.Registers.E = c.fetchByte()
 		return 8
-	case 0x1F: // RRA
+	},
+	0x1F: func(c *CPU) uint8 { // RRA
 		c.Registers.A = c.rr(c.Registers.A)
 		c.Registers.ClearFlag(FlagZ) // RRA always clears Z
 		return 4
-
+	},
 	// 0x20-0x2F
-	case 0x20: // JR NZ, n
+	0x20: func(c *CPU) uint8 { // JR NZ, n
 		offset := int8(c.fetchByte()) //nolint:gosec // G115: Intentional signed conversion for relative jump
 		if !c.Registers.ZeroFlag() {
 			c.Registers.PC = uint16(int32(c.Registers.PC) + int32(offset)) //nolint:gosec // G115: Intentional for address calculation
 			return 12
 		}
 		return 8
-	case 0x21: // LD HL, nn
+	},
+	0x21: func(c *CPU) uint8 { // LD HL, nn
 		c.Registers.SetHL(c.fetchWord())
 		return 12
-	case 0x22: // LD (HL+), A
+	},
+	0x22: func(c *CPU) uint8 { // LD (HL+), A
 		c.Memory.Write(c.Registers.HL(), c.Registers.A)
 		c.Registers.SetHL(c.Registers.HL() + 1)
 		return 8
-	case 0x23: // INC HL
+	},
+	0x23: func(c *CPU) uint8 { // INC HL
 		c.Registers.SetHL(c.Registers.HL() + 1)
 		return 8
-	case 0x24: // INC H
+	},
+	0x24: func(c *CPU) uint8 { // INC H
 		c.Registers.H = c.inc8(c.Registers.H)
 		return 4
-	case 0x25: // DEC H
+	},
+	0x25: func(c *CPU) uint8 { // DEC H
 		c.Registers.H = c.dec8(c.Registers.H)
 		return 4
-	case 0x26: // LD H, n
+	},
+	0x26: func(c *CPU) uint8 { // LD H, n
 		c.Registers.H = c.fetchByte()
 		return 8
-	case 0x27: // DAA
+	},
+	0x27: func(c *CPU) uint8 { // DAA
 		c.daa()
 		return 4
-	case 0x28: // JR Z, n
+	},
+	0x28: func(c *CPU) uint8 { // JR Z, n
 		offset := int8(c.fetchByte()) //nolint:gosec // G115: Intentional signed conversion for relative jump
 		if c.Registers.ZeroFlag() {
 			c.Registers.PC = uint16(int32(c.Registers.PC) + int32(offset)) //nolint:gosec // G115: Intentional for address calculation
 			return 12
 		}
 		return 8
-	case 0x29: // ADD HL, HL
+	},
+	0x29: func(c *CPU) uint8 { // ADD HL, HL
 		c.Registers.SetHL(c.add16(c.Registers.HL(), c.Registers.HL()))
 		return 8
-	case 0x2A: // LD A, (HL+)
+	},
+	0x2A: func(c *CPU) uint8 { // LD A, (HL+)
 		c.Registers.A = c.Memory.Read(c.Registers.HL())
 		c.Registers.SetHL(c.Registers.HL() + 1)
 		return 8
-	case 0x2B: // DEC HL
+	},
+	0x2B: func(c *CPU) uint8 { // DEC HL
 		c.Registers.SetHL(c.Registers.HL() - 1)
 		return 8
-	case 0x2C: // INC L
+	},
+	0x2C: func(c *CPU) uint8 { // INC L
 		c.Registers.L = c.inc8(c.Registers.L)
 		return 4
-	case 0x2D: // DEC L
+	},
+	0x2D: func(c *CPU) uint8 { // DEC L
 		c.Registers.L = c.dec8(c.Registers.L)
 		return 4
-	case 0x2E: // LD L, n
+	},
+	0x2E: func(c *CPU) uint8 { // LD L, n
 		c.Registers.L = c.fetchByte()
 		return 8
-	case 0x2F: // CPL
+	},
+	0x2F: func(c *CPU) uint8 { // CPL
 		c.Registers.A = ^c.Registers.A
 		c.Registers.SetFlag(FlagN)
 		c.Registers.SetFlag(FlagH)
 		return 4
-
+	},
 	// 0x30-0x3F
-	case 0x30: // JR NC, n
+	0x30: func(c *CPU) uint8 { // JR NC, n
 		offset := int8(c.fetchByte()) //nolint:gosec // G115: Intentional signed conversion for relative jump
 		if !c.Registers.CarryFlag() {
 			c.Registers.PC = uint16(int32(c.Registers.PC) + int32(offset)) //nolint:gosec // G115: Intentional for address calculation
 			return 12
 		}
 		return 8
-	case 0x31: // LD SP, nn
+	},
+	0x31: func(c *CPU) uint8 { // LD SP, nn
 		c.Registers.SP = c.fetchWord()
 		return 12
-	case 0x32: // LD (HL-), A
+	},
+	0x32: func(c *CPU) uint8 { // LD (HL-), A
 		c.Memory.Write(c.Registers.HL(), c.Registers.A)
 		c.Registers.SetHL(c.Registers.HL() - 1)
 		return 8
-	case 0x33: // INC SP
+	},
+	0x33: func(c *CPU) uint8 { // INC SP
 		c.Registers.SP++
 		return 8
-	case 0x34: // INC (HL)
+	},
+	0x34: func(c *CPU) uint8 { // INC (HL)
 		addr := c.Registers.HL()
 		c.Memory.Write(addr, c.inc8(c.Memory.Read(addr)))
 		return 12
-	case 0x35: // DEC (HL)
+	},
+	0x35: func(c *CPU) uint8 { // DEC (HL)
 		addr := c.Registers.HL()
 		c.Memory.Write(addr, c.dec8(c.Memory.Read(addr)))
 		return 12
-	case 0x36: // LD (HL), n
+	},
+	0x36: func(c *CPU) uint8 { // LD (HL), n
 		c.Memory.Write(c.Registers.HL(), c.fetchByte())
 		return 12
-	case 0x37: // SCF
+	},
+	0x37: func(c *CPU) uint8 { // SCF
 		c.Registers.ClearFlag(FlagN)
 		c.Registers.ClearFlag(FlagH)
 		c.Registers.SetFlag(FlagC)
 		return 4
-	case 0x38: // JR C, n
+	},
+	0x38: func(c *CPU) uint8 { // JR C, n
 		offset := int8(c.fetchByte()) //nolint:gosec // G115: Intentional signed conversion for relative jump
 		if c.Registers.CarryFlag() {
 			c.Registers.PC = uint16(int32(c.Registers.PC) + int32(offset)) //nolint:gosec // G115: Intentional for address calculation
 			return 12
 		}
 		return 8
-	case 0x39: // ADD HL, SP
+	},
+	0x39: func(c *CPU) uint8 { // ADD HL, SP
 		c.Registers.SetHL(c.add16(c.Registers.HL(), c.Registers.SP))
 		return 8
-	case 0x3A: // LD A, (HL-)
+	},
+	0x3A: func(c *CPU) uint8 { // LD A, (HL-)
 		c.Registers.A = c.Memory.Read(c.Registers.HL())
 		c.Registers.SetHL(c.Registers.HL() - 1)
 		return 8
-	case 0x3B: // DEC SP
+	},
+	0x3B: func(c *CPU) uint8 { // DEC SP
 		c.Registers.SP--
 		return 8
-	case 0x3C: // INC A
+	},
+	0x3C: func(c *CPU) uint8 { // INC A
 		c.Registers.A = c.inc8(c.Registers.A)
 		return 4
-	case 0x3D: // DEC A
+	},
+	0x3D: func(c *CPU) uint8 { // DEC A
 		c.Registers.A = c.dec8(c.Registers.A)
 		return 4
-	case 0x3E: // LD A, n
+	},
+	0x3E: func(c *CPU) uint8 { // LD A, n
 		c.Registers.A = c.fetchByte()
 		return 8
-	case 0x3F: // CCF
+	},
+	0x3F: func(c *CPU) uint8 { // CCF
 		c.Registers.ClearFlag(FlagN)
 		c.Registers.ClearFlag(FlagH)
 		if c.Registers.CarryFlag() {
@@ -243,171 +317,224 @@ func (c *CPU) execute(opcode uint8) uint8 {
 			c.Registers.SetFlag(FlagC)
 		}
 		return 4
-
+	},
 	// 0x40-0x4F: LD r, r' instructions
-	case 0x40: // LD B, B
+	0x40: func(c *CPU) uint8 { // LD B, B
+		// Used as a software breakpoint by test ROMs (e.g. Mooneye)
+		c.breakpointHit = true
 		return 4
-	case 0x41: // LD B, C
+	},
+	0x41: func(c *CPU) uint8 { // LD B, C
 		c.Registers.B = c.Registers.C
 		return 4
-	case 0x42: // LD B, D
+	},
+	0x42: func(c *CPU) uint8 { // LD B, D
 		c.Registers.B = c.Registers.D
 		return 4
-	case 0x43: // LD B, E
+	},
+	0x43: func(c *CPU) uint8 { // LD B, E
 		c.Registers.B = c.Registers.E
 		return 4
-	case 0x44: // LD B, H
+	},
+	0x44: func(c *CPU) uint8 { // LD B, H
 		c.Registers.B = c.Registers.H
 		return 4
-	case 0x45: // LD B, L
+	},
+	0x45: func(c *CPU) uint8 { // LD B, L
 		c.Registers.B = c.Registers.L
 		return 4
-	case 0x46: // LD B, (HL)
+	},
+	0x46: func(c *CPU) uint8 { // LD B, (HL)
 		c.Registers.B = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x47: // LD B, A
+	},
+	0x47: func(c *CPU) uint8 { // LD B, A
 		c.Registers.B = c.Registers.A
 		return 4
-	case 0x48: // LD C, B
+	},
+	0x48: func(c *CPU) uint8 { // LD C, B
 		c.Registers.C = c.Registers.B
 		return 4
-	case 0x49: // LD C, C
+	},
+	0x49: func(_ *CPU) uint8 { // LD C, C
 		return 4
-	case 0x4A: // LD C, D
+	},
+	0x4A: func(c *CPU) uint8 { // LD C, D
 		c.Registers.C = c.Registers.D
 		return 4
-	case 0x4B: // LD C, E
+	},
+	0x4B: func(c *CPU) uint8 { // LD C, E
 		c.Registers.C = c.Registers.E
 		return 4
-	case 0x4C: // LD C, H
+	},
+	0x4C: func(c *CPU) uint8 { // LD C, H
 		c.Registers.C = c.Registers.H
 		return 4
-	case 0x4D: // LD C, L
+	},
+	0x4D: func(c *CPU) uint8 { // LD C, L
 		c.Registers.C = c.Registers.L
 		return 4
-	case 0x4E: // LD C, (HL)
+	},
+	0x4E: func(c *CPU) uint8 { // LD C, (HL)
 		c.Registers.C = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x4F: // LD C, A
+	},
+	0x4F: func(c *CPU) uint8 { // LD C, A
 		c.Registers.C = c.Registers.A
 		return 4
-
+	},
 	// 0x50-0x5F: More LD r, r' instructions
-	case 0x50: // LD D, B
+	0x50: func(c *CPU) uint8 { // LD D, B
 		c.Registers.D = c.Registers.B
 		return 4
-	case 0x51: // LD D, C
+	},
+	0x51: func(c *CPU) uint8 { // LD D, C
 		c.Registers.D = c.Registers.C
 		return 4
-	case 0x52: // LD D, D
+	},
+	0x52: func(_ *CPU) uint8 { // LD D, D
 		return 4
-	case 0x53: // LD D, E
+	},
+	0x53: func(c *CPU) uint8 { // LD D, E
 		c.Registers.D = c.Registers.E
 		return 4
-	case 0x54: // LD D, H
+	},
+	0x54: func(c *CPU) uint8 { // LD D, H
 		c.Registers.D = c.Registers.H
 		return 4
-	case 0x55: // LD D, L
+	},
+	0x55: func(c *CPU) uint8 { // LD D, L
 		c.Registers.D = c.Registers.L
 		return 4
-	case 0x56: // LD D, (HL)
+	},
+	0x56: func(c *CPU) uint8 { // LD D, (HL)
 		c.Registers.D = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x57: // LD D, A
+	},
+	0x57: func(c *CPU) uint8 { // LD D, A
 		c.Registers.D = c.Registers.A
 		return 4
-	case 0x58: // LD E, B
+	},
+	0x58: func(c *CPU) uint8 { // LD E, B
 		c.Registers.E = c.Registers.B
 		return 4
-	case 0x59: // LD E, C
+	},
+	0x59: func(c *CPU) uint8 { // LD E, C
 		c.Registers.E = c.Registers.C
 		return 4
-	case 0x5A: // LD E, D
+	},
+	0x5A: func(c *CPU) uint8 { // LD E, D
 		c.Registers.E = c.Registers.D
 		return 4
-	case 0x5B: // LD E, E
+	},
+	0x5B: func(_ *CPU) uint8 { // LD E, E
 		return 4
-	case 0x5C: // LD E, H
+	},
+	0x5C: func(c *CPU) uint8 { // LD E, H
 		c.Registers.E = c.Registers.H
 		return 4
-	case 0x5D: // LD E, L
+	},
+	0x5D: func(c *CPU) uint8 { // LD E, L
 		c.Registers.E = c.Registers.L
 		return 4
-	case 0x5E: // LD E, (HL)
+	},
+	0x5E: func(c *CPU) uint8 { // LD E, (HL)
 		c.Registers.E = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x5F: // LD E, A
+	},
+	0x5F: func(c *CPU) uint8 { // LD E, A
 		c.Registers.E = c.Registers.A
 		return 4
-
+	},
 	// 0x60-0x6F: More LD r, r' instructions
-	case 0x60: // LD H, B
+	0x60: func(c *CPU) uint8 { // LD H, B
 		c.Registers.H = c.Registers.B
 		return 4
-	case 0x61: // LD H, C
+	},
+	0x61: func(c *CPU) uint8 { // LD H, C
 		c.Registers.H = c.Registers.C
 		return 4
-	case 0x62: // LD H, D
+	},
+	0x62: func(c *CPU) uint8 { // LD H, D
 		c.Registers.H = c.Registers.D
 		return 4
-	case 0x63: // LD H, E
+	},
+	0x63: func(c *CPU) uint8 { // LD H, E
 		c.Registers.H = c.Registers.E
 		return 4
-	case 0x64: // LD H, H
+	},
+	0x64: func(_ *CPU) uint8 { // LD H, H
 		return 4
-	case 0x65: // LD H, L
+	},
+	0x65: func(c *CPU) uint8 { // LD H, L
 		c.Registers.H = c.Registers.L
 		return 4
-	case 0x66: // LD H, (HL)
+	},
+	0x66: func(c *CPU) uint8 { // LD H, (HL)
 		c.Registers.H = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x67: // LD H, A
+	},
+	0x67: func(c *CPU) uint8 { // LD H, A
 		c.Registers.H = c.Registers.A
 		return 4
-	case 0x68: // LD L, B
+	},
+	0x68: func(c *CPU) uint8 { // LD L, B
 		c.Registers.L = c.Registers.B
 		return 4
-	case 0x69: // LD L, C
+	},
+	0x69: func(c *CPU) uint8 { // LD L, C
 		c.Registers.L = c.Registers.C
 		return 4
-	case 0x6A: // LD L, D
+	},
+	0x6A: func(c *CPU) uint8 { // LD L, D
 		c.Registers.L = c.Registers.D
 		return 4
-	case 0x6B: // LD L, E
+	},
+	0x6B: func(c *CPU) uint8 { // LD L, E
 		c.Registers.L = c.Registers.E
 		return 4
-	case 0x6C: // LD L, H
+	},
+	0x6C: func(c *CPU) uint8 { // LD L, H
 		c.Registers.L = c.Registers.H
 		return 4
-	case 0x6D: // LD L, L
+	},
+	0x6D: func(_ *CPU) uint8 { // LD L, L
 		return 4
-	case 0x6E: // LD L, (HL)
+	},
+	0x6E: func(c *CPU) uint8 { // LD L, (HL)
 		c.Registers.L = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x6F: // LD L, A
+	},
+	0x6F: func(c *CPU) uint8 { // LD L, A
 		c.Registers.L = c.Registers.A
 		return 4
-
+	},
 	// 0x70-0x7F: LD (HL), r and LD A, r instructions
-	case 0x70: // LD (HL), B
+	0x70: func(c *CPU) uint8 { // LD (HL), B
 		c.Memory.Write(c.Registers.HL(), c.Registers.B)
 		return 8
-	case 0x71: // LD (HL), C
+	},
+	0x71: func(c *CPU) uint8 { // LD (HL), C
 		c.Memory.Write(c.Registers.HL(), c.Registers.C)
 		return 8
-	case 0x72: // LD (HL), D
+	},
+	0x72: func(c *CPU) uint8 { // LD (HL), D
 		c.Memory.Write(c.Registers.HL(), c.Registers.D)
 		return 8
-	case 0x73: // LD (HL), E
+	},
+	0x73: func(c *CPU) uint8 { // LD (HL), E
 		c.Memory.Write(c.Registers.HL(), c.Registers.E)
 		return 8
-	case 0x74: // LD (HL), H
+	},
+	0x74: func(c *CPU) uint8 { // LD (HL), H
 		c.Memory.Write(c.Registers.HL(), c.Registers.H)
 		return 8
-	case 0x75: // LD (HL), L
+	},
+	0x75: func(c *CPU) uint8 { // LD (HL), L
 		c.Memory.Write(c.Registers.HL(), c.Registers.L)
 		return 8
-	case 0x76: // HALT
+	},
+	0x76: func(c *CPU) uint8 { // HALT
 		// HALT is special: on hardware it fetches with IR = [PC] (no increment)
 		// but we've already incremented PC in fetchByte(), so undo it
 		// However, if wasHaltBug is true, fetchByte() already didn't increment PC,
@@ -417,254 +544,327 @@ func (c *CPU) execute(opcode uint8) uint8 {
 		}
 		c.halted = true
 		return 4
-	case 0x77: // LD (HL), A
+	},
+	0x77: func(c *CPU) uint8 { // LD (HL), A
 		c.Memory.Write(c.Registers.HL(), c.Registers.A)
 		return 8
-	case 0x78: // LD A, B
+	},
+	0x78: func(c *CPU) uint8 { // LD A, B
 		c.Registers.A = c.Registers.B
 		return 4
-	case 0x79: // LD A, C
+	},
+	0x79: func(c *CPU) uint8 { // LD A, C
 		c.Registers.A = c.Registers.C
 		return 4
-	case 0x7A: // LD A, D
+	},
+	0x7A: func(c *CPU) uint8 { // LD A, D
 		c.Registers.A = c.Registers.D
 		return 4
-	case 0x7B: // LD A, E
+	},
+	0x7B: func(c *CPU) uint8 { // LD A, E
 		c.Registers.A = c.Registers.E
 		return 4
-	case 0x7C: // LD A, H
+	},
+	0x7C: func(c *CPU) uint8 { // LD A, H
 		c.Registers.A = c.Registers.H
 		return 4
-	case 0x7D: // LD A, L
+	},
+	0x7D: func(c *CPU) uint8 { // LD A, L
 		c.Registers.A = c.Registers.L
 		return 4
-	case 0x7E: // LD A, (HL)
+	},
+	0x7E: func(c *CPU) uint8 { // LD A, (HL)
 		c.Registers.A = c.Memory.Read(c.Registers.HL())
 		return 8
-	case 0x7F: // LD A, A
+	},
+	0x7F: func(_ *CPU) uint8 { // LD A, A
 		return 4
-
+	},
 	// 0x80-0x8F: Arithmetic operations with A
-	case 0x80: // ADD A, B
+	0x80: func(c *CPU) uint8 { // ADD A, B
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.B, false)
 		return 4
-	case 0x81: // ADD A, C
+	},
+	0x81: func(c *CPU) uint8 { // ADD A, C
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.C, false)
 		return 4
-	case 0x82: // ADD A, D
+	},
+	0x82: func(c *CPU) uint8 { // ADD A, D
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.D, false)
 		return 4
-	case 0x83: // ADD A, E
+	},
+	0x83: func(c *CPU) uint8 { // ADD A, E
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.E, false)
 		return 4
-	case 0x84: // ADD A, H
+	},
+	0x84: func(c *CPU) uint8 { // ADD A, H
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.H, false)
 		return 4
-	case 0x85: // ADD A, L
+	},
+	0x85: func(c *CPU) uint8 { // ADD A, L
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.L, false)
 		return 4
-	case 0x86: // ADD A, (HL)
+	},
+	0x86: func(c *CPU) uint8 { // ADD A, (HL)
 		c.Registers.A = c.add8(c.Registers.A, c.Memory.Read(c.Registers.HL()), false)
 		return 8
-	case 0x87: // ADD A, A
+	},
+	0x87: func(c *CPU) uint8 { // ADD A, A
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.A, false)
 		return 4
-	case 0x88: // ADC A, B
+	},
+	0x88: func(c *CPU) uint8 { // ADC A, B
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.B, true)
 		return 4
-	case 0x89: // ADC A, C
+	},
+	0x89: func(c *CPU) uint8 { // ADC A, C
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.C, true)
 		return 4
-	case 0x8A: // ADC A, D
+	},
+	0x8A: func(c *CPU) uint8 { // ADC A, D
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.D, true)
 		return 4
-	case 0x8B: // ADC A, E
+	},
+	0x8B: func(c *CPU) uint8 { // ADC A, E
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.E, true)
 		return 4
-	case 0x8C: // ADC A, H
+	},
+	0x8C: func(c *CPU) uint8 { // ADC A, H
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.H, true)
 		return 4
-	case 0x8D: // ADC A, L
+	},
+	0x8D: func(c *CPU) uint8 { // ADC A, L
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.L, true)
 		return 4
-	case 0x8E: // ADC A, (HL)
+	},
+	0x8E: func(c *CPU) uint8 { // ADC A, (HL)
 		c.Registers.A = c.add8(c.Registers.A, c.Memory.Read(c.Registers.HL()), true)
 		return 8
-	case 0x8F: // ADC A, A
+	},
+	0x8F: func(c *CPU) uint8 { // ADC A, A
 		c.Registers.A = c.add8(c.Registers.A, c.Registers.A, true)
 		return 4
-
+	},
 	// 0x90-0x9F: Subtraction operations
-	case 0x90: // SUB B
+	0x90: func(c *CPU) uint8 { // SUB B
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.B, false)
 		return 4
-	case 0x91: // SUB C
+	},
+	0x91: func(c *CPU) uint8 { // SUB C
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.C, false)
 		return 4
-	case 0x92: // SUB D
+	},
+	0x92: func(c *CPU) uint8 { // SUB D
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.D, false)
 		return 4
-	case 0x93: // SUB E
+	},
+	0x93: func(c *CPU) uint8 { // SUB E
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.E, false)
 		return 4
-	case 0x94: // SUB H
+	},
+	0x94: func(c *CPU) uint8 { // SUB H
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.H, false)
 		return 4
-	case 0x95: // SUB L
+	},
+	0x95: func(c *CPU) uint8 { // SUB L
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.L, false)
 		return 4
-	case 0x96: // SUB (HL)
+	},
+	0x96: func(c *CPU) uint8 { // SUB (HL)
 		c.Registers.A = c.sub8(c.Registers.A, c.Memory.Read(c.Registers.HL()), false)
 		return 8
-	case 0x97: // SUB A
+	},
+	0x97: func(c *CPU) uint8 { // SUB A
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.A, false)
 		return 4
-	case 0x98: // SBC A, B
+	},
+	0x98: func(c *CPU) uint8 { // SBC A, B
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.B, true)
 		return 4
-	case 0x99: // SBC A, C
+	},
+	0x99: func(c *CPU) uint8 { // SBC A, C
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.C, true)
 		return 4
-	case 0x9A: // SBC A, D
+	},
+	0x9A: func(c *CPU) uint8 { // SBC A, D
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.D, true)
 		return 4
-	case 0x9B: // SBC A, E
+	},
+	0x9B: func(c *CPU) uint8 { // SBC A, E
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.E, true)
 		return 4
-	case 0x9C: // SBC A, H
+	},
+	0x9C: func(c *CPU) uint8 { // SBC A, H
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.H, true)
 		return 4
-	case 0x9D: // SBC A, L
+	},
+	0x9D: func(c *CPU) uint8 { // SBC A, L
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.L, true)
 		return 4
-	case 0x9E: // SBC A, (HL)
+	},
+	0x9E: func(c *CPU) uint8 { // SBC A, (HL)
 		c.Registers.A = c.sub8(c.Registers.A, c.Memory.Read(c.Registers.HL()), true)
 		return 8
-	case 0x9F: // SBC A, A
+	},
+	0x9F: func(c *CPU) uint8 { // SBC A, A
 		c.Registers.A = c.sub8(c.Registers.A, c.Registers.A, true)
 		return 4
-
+	},
 	// 0xA0-0xAF: Logic operations
-	case 0xA0: // AND B
+	0xA0: func(c *CPU) uint8 { // AND B
 		c.Registers.A = c.and(c.Registers.B)
 		return 4
-	case 0xA1: // AND C
+	},
+	0xA1: func(c *CPU) uint8 { // AND C
 		c.Registers.A = c.and(c.Registers.C)
 		return 4
-	case 0xA2: // AND D
+	},
+	0xA2: func(c *CPU) uint8 { // AND D
 		c.Registers.A = c.and(c.Registers.D)
 		return 4
-	case 0xA3: // AND E
+	},
+	0xA3: func(c *CPU) uint8 { // AND E
 		c.Registers.A = c.and(c.Registers.E)
 		return 4
-	case 0xA4: // AND H
+	},
+	0xA4: func(c *CPU) uint8 { // AND H
 		c.Registers.A = c.and(c.Registers.H)
 		return 4
-	case 0xA5: // AND L
+	},
+	0xA5: func(c *CPU) uint8 { // AND L
 		c.Registers.A = c.and(c.Registers.L)
 		return 4
-	case 0xA6: // AND (HL)
+	},
+	0xA6: func(c *CPU) uint8 { // AND (HL)
 		c.Registers.A = c.and(c.Memory.Read(c.Registers.HL()))
 		return 8
-	case 0xA7: // AND A
+	},
+	0xA7: func(c *CPU) uint8 { // AND A
 		c.Registers.A = c.and(c.Registers.A)
 		return 4
-	case 0xA8: // XOR B
+	},
+	0xA8: func(c *CPU) uint8 { // XOR B
 		c.Registers.A = c.xor(c.Registers.B)
 		return 4
-	case 0xA9: // XOR C
+	},
+	0xA9: func(c *CPU) uint8 { // XOR C
 		c.Registers.A = c.xor(c.Registers.C)
 		return 4
-	case 0xAA: // XOR D
+	},
+	0xAA: func(c *CPU) uint8 { // XOR D
 		c.Registers.A = c.xor(c.Registers.D)
 		return 4
-	case 0xAB: // XOR E
+	},
+	0xAB: func(c *CPU) uint8 { // XOR E
 		c.Registers.A = c.xor(c.Registers.E)
 		return 4
-	case 0xAC: // XOR H
+	},
+	0xAC: func(c *CPU) uint8 { // XOR H
 		c.Registers.A = c.xor(c.Registers.H)
 		return 4
-	case 0xAD: // XOR L
+	},
+	0xAD: func(c *CPU) uint8 { // XOR L
 		c.Registers.A = c.xor(c.Registers.L)
 		return 4
-	case 0xAE: // XOR (HL)
+	},
+	0xAE: func(c *CPU) uint8 { // XOR (HL)
 		c.Registers.A = c.xor(c.Memory.Read(c.Registers.HL()))
 		return 8
-	case 0xAF: // XOR A
+	},
+	0xAF: func(c *CPU) uint8 { // XOR A
 		c.Registers.A = c.xor(c.Registers.A)
 		return 4
-
+	},
 	// 0xB0-0xBF: OR and CP operations
-	case 0xB0: // OR B
+	0xB0: func(c *CPU) uint8 { // OR B
 		c.Registers.A = c.or(c.Registers.B)
 		return 4
-	case 0xB1: // OR C
+	},
+	0xB1: func(c *CPU) uint8 { // OR C
 		c.Registers.A = c.or(c.Registers.C)
 		return 4
-	case 0xB2: // OR D
+	},
+	0xB2: func(c *CPU) uint8 { // OR D
 		c.Registers.A = c.or(c.Registers.D)
 		return 4
-	case 0xB3: // OR E
+	},
+	0xB3: func(c *CPU) uint8 { // OR E
 		c.Registers.A = c.or(c.Registers.E)
 		return 4
-	case 0xB4: // OR H
+	},
+	0xB4: func(c *CPU) uint8 { // OR H
 		c.Registers.A = c.or(c.Registers.H)
 		return 4
-	case 0xB5: // OR L
+	},
+	0xB5: func(c *CPU) uint8 { // OR L
 		c.Registers.A = c.or(c.Registers.L)
 		return 4
-	case 0xB6: // OR (HL)
+	},
+	0xB6: func(c *CPU) uint8 { // OR (HL)
 		c.Registers.A = c.or(c.Memory.Read(c.Registers.HL()))
 		return 8
-	case 0xB7: // OR A
+	},
+	0xB7: func(c *CPU) uint8 { // OR A
 		c.Registers.A = c.or(c.Registers.A)
 		return 4
-	case 0xB8: // CP B
+	},
+	0xB8: func(c *CPU) uint8 { // CP B
 		c.cp(c.Registers.B)
 		return 4
-	case 0xB9: // CP C
+	},
+	0xB9: func(c *CPU) uint8 { // CP C
 		c.cp(c.Registers.C)
 		return 4
-	case 0xBA: // CP D
+	},
+	0xBA: func(c *CPU) uint8 { // CP D
 		c.cp(c.Registers.D)
 		return 4
-	case 0xBB: // CP E
+	},
+	0xBB: func(c *CPU) uint8 { // CP E
 		c.cp(c.Registers.E)
 		return 4
-	case 0xBC: // CP H
+	},
+	0xBC: func(c *CPU) uint8 { // CP H
 		c.cp(c.Registers.H)
 		return 4
-	case 0xBD: // CP L
+	},
+	0xBD: func(c *CPU) uint8 { // CP L
 		c.cp(c.Registers.L)
 		return 4
-	case 0xBE: // CP (HL)
+	},
+	0xBE: func(c *CPU) uint8 { // CP (HL)
 		c.cp(c.Memory.Read(c.Registers.HL()))
 		return 8
-	case 0xBF: // CP A
+	},
+	0xBF: func(c *CPU) uint8 { // CP A
 		c.cp(c.Registers.A)
 		return 4
-
+	},
 	// 0xC0-0xCF: Returns, pops, jumps, and calls
-	case 0xC0: // RET NZ
+	0xC0: func(c *CPU) uint8 { // RET NZ
 		if !c.Registers.ZeroFlag() {
 			c.Registers.PC = c.pop()
 			return 20
 		}
 		return 8
-	case 0xC1: // POP BC
+	},
+	0xC1: func(c *CPU) uint8 { // POP BC
 		c.Registers.SetBC(c.pop())
 		return 12
-	case 0xC2: // JP NZ, nn
+	},
+	0xC2: func(c *CPU) uint8 { // JP NZ, nn
 		addr := c.fetchWord()
 		if !c.Registers.ZeroFlag() {
 			c.Registers.PC = addr
 			return 16
 		}
 		return 12
-	case 0xC3: // JP nn
+	},
+	0xC3: func(c *CPU) uint8 { // JP nn
 		c.Registers.PC = c.fetchWord()
 		return 16
-	case 0xC4: // CALL NZ, nn
+	},
+	0xC4: func(c *CPU) uint8 { // CALL NZ, nn
 		addr := c.fetchWord()
 		if !c.Registers.ZeroFlag() {
 			c.push(c.Registers.PC)
@@ -672,36 +872,44 @@ func (c *CPU) execute(opcode uint8) uint8 {
 			return 24
 		}
 		return 12
-	case 0xC5: // PUSH BC
+	},
+	0xC5: func(c *CPU) uint8 { // PUSH BC
 		c.push(c.Registers.BC())
 		return 16
-	case 0xC6: // ADD A, n
+	},
+	0xC6: func(c *CPU) uint8 { // ADD A, n
 		c.Registers.A = c.add8(c.Registers.A, c.fetchByte(), false)
 		return 8
-	case 0xC7: // RST 00H
+	},
+	0xC7: func(c *CPU) uint8 { // RST 00H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x00
 		return 16
-	case 0xC8: // RET Z
+	},
+	0xC8: func(c *CPU) uint8 { // RET Z
 		if c.Registers.ZeroFlag() {
 			c.Registers.PC = c.pop()
 			return 20
 		}
 		return 8
-	case 0xC9: // RET
+	},
+	0xC9: func(c *CPU) uint8 { // RET
 		c.Registers.PC = c.pop()
 		return 16
-	case 0xCA: // JP Z, nn
+	},
+	0xCA: func(c *CPU) uint8 { // JP Z, nn
 		addr := c.fetchWord()
 		if c.Registers.ZeroFlag() {
 			c.Registers.PC = addr
 			return 16
 		}
 		return 12
-	case 0xCB: // CB prefix
+	},
+	0xCB: func(_ *CPU) uint8 { // CB prefix
 		// This should be handled in Step(), not here
 		panic("CB prefix should not reach execute()")
-	case 0xCC: // CALL Z, nn
+	},
+	0xCC: func(c *CPU) uint8 { // CALL Z, nn
 		addr := c.fetchWord()
 		if c.Registers.ZeroFlag() {
 			c.push(c.Registers.PC)
@@ -709,39 +917,44 @@ func (c *CPU) execute(opcode uint8) uint8 {
 			return 24
 		}
 		return 12
-	case 0xCD: // CALL nn
+	},
+	0xCD: func(c *CPU) uint8 { // CALL nn
 		addr := c.fetchWord()
 		c.push(c.Registers.PC)
 		c.Registers.PC = addr
 		return 24
-	case 0xCE: // ADC A, n
+	},
+	0xCE: func(c *CPU) uint8 { // ADC A, n
 		c.Registers.A = c.add8(c.Registers.A, c.fetchByte(), true)
 		return 8
-	case 0xCF: // RST 08H
+	},
+	0xCF: func(c *CPU) uint8 { // RST 08H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x08
 		return 16
-
+	},
 	// 0xD0-0xDF: More returns, pops, jumps, and calls
-	case 0xD0: // RET NC
+	0xD0: func(c *CPU) uint8 { // RET NC
 		if !c.Registers.CarryFlag() {
 			c.Registers.PC = c.pop()
 			return 20
 		}
 		return 8
-	case 0xD1: // POP DE
+	},
+	0xD1: func(c *CPU) uint8 { // POP DE
 		c.Registers.SetDE(c.pop())
 		return 12
-	case 0xD2: // JP NC, nn
+	},
+	0xD2: func(c *CPU) uint8 { // JP NC, nn
 		addr := c.fetchWord()
 		if !c.Registers.CarryFlag() {
 			c.Registers.PC = addr
 			return 16
 		}
 		return 12
-	case 0xD3: // Invalid opcode
-		panic("Invalid opcode 0xD3")
-	case 0xD4: // CALL NC, nn
+	},
+	0xD3: illegalOpcode(0xD3), // Invalid opcode
+	0xD4: func(c *CPU) uint8 { // CALL NC, nn
 		addr := c.fetchWord()
 		if !c.Registers.CarryFlag() {
 			c.push(c.Registers.PC)
@@ -749,36 +962,42 @@ func (c *CPU) execute(opcode uint8) uint8 {
 			return 24
 		}
 		return 12
-	case 0xD5: // PUSH DE
+	},
+	0xD5: func(c *CPU) uint8 { // PUSH DE
 		c.push(c.Registers.DE())
 		return 16
-	case 0xD6: // SUB n
+	},
+	0xD6: func(c *CPU) uint8 { // SUB n
 		c.Registers.A = c.sub8(c.Registers.A, c.fetchByte(), false)
 		return 8
-	case 0xD7: // RST 10H
+	},
+	0xD7: func(c *CPU) uint8 { // RST 10H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x10
 		return 16
-	case 0xD8: // RET C
+	},
+	0xD8: func(c *CPU) uint8 { // RET C
 		if c.Registers.CarryFlag() {
 			c.Registers.PC = c.pop()
 			return 20
 		}
 		return 8
-	case 0xD9: // RETI
+	},
+	0xD9: func(c *CPU) uint8 { // RETI
 		c.Registers.PC = c.pop()
 		c.IME = true
 		return 16
-	case 0xDA: // JP C, nn
+	},
+	0xDA: func(c *CPU) uint8 { // JP C, nn
 		addr := c.fetchWord()
 		if c.Registers.CarryFlag() {
 			c.Registers.PC = addr
 			return 16
 		}
 		return 12
-	case 0xDB: // Invalid opcode
-		panic("Invalid opcode 0xDB")
-	case 0xDC: // CALL C, nn
+	},
+	0xDB: illegalOpcode(0xDB), // Invalid opcode
+	0xDC: func(c *CPU) uint8 { // CALL C, nn
 		addr := c.fetchWord()
 		if c.Registers.CarryFlag() {
 			c.push(c.Registers.PC)
@@ -786,41 +1005,46 @@ func (c *CPU) execute(opcode uint8) uint8 {
 			return 24
 		}
 		return 12
-	case 0xDD: // Invalid opcode
-		panic("Invalid opcode 0xDD")
-	case 0xDE: // SBC A, n
+	},
+	0xDD: illegalOpcode(0xDD), // Invalid opcode
+	0xDE: func(c *CPU) uint8 { // SBC A, n
 		c.Registers.A = c.sub8(c.Registers.A, c.fetchByte(), true)
 		return 8
-	case 0xDF: // RST 18H
+	},
+	0xDF: func(c *CPU) uint8 { // RST 18H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x18
 		return 16
-
+	},
 	// 0xE0-0xEF: I/O operations and more
-	case 0xE0: // LDH (n), A
+	0xE0: func(c *CPU) uint8 { // LDH (n), A
 		c.Memory.Write(0xFF00+uint16(c.fetchByte()), c.Registers.A)
 		return 12
-	case 0xE1: // POP HL
+	},
+	0xE1: func(c *CPU) uint8 { // POP HL
 		c.Registers.SetHL(c.pop())
 		return 12
-	case 0xE2: // LD (C), A
+	},
+	0xE2: func(c *CPU) uint8 { // LD (C), A
 		c.Memory.Write(0xFF00+uint16(c.Registers.C), c.Registers.A)
 		return 8
-	case 0xE3: // Invalid opcode
-		panic("Invalid opcode 0xE3")
-	case 0xE4: // Invalid opcode
-		panic("Invalid opcode 0xE4")
-	case 0xE5: // PUSH HL
+	},
+	0xE3: illegalOpcode(0xE3), // Invalid opcode
+	0xE4: illegalOpcode(0xE4), // Invalid opcode
+	0xE5: func(c *CPU) uint8 { // PUSH HL
 		c.push(c.Registers.HL())
 		return 16
-	case 0xE6: // AND n
+	},
+	0xE6: func(c *CPU) uint8 { // AND n
 		c.Registers.A = c.and(c.fetchByte())
 		return 8
-	case 0xE7: // RST 20H
+	},
+	0xE7: func(c *CPU) uint8 { // RST 20H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x20
 		return 16
-	case 0xE8: // ADD SP, n
+	},
+	0xE8: func(c *CPU) uint8 { // ADD SP, n
 		offset := int8(c.fetchByte())                           //nolint:gosec // G115: Intentional signed conversion for relative jump
 		result := uint16(int32(c.Registers.SP) + int32(offset)) //nolint:gosec // G115: Intentional for SP offset calculation
 		// Flags for ADD SP, n are different
@@ -830,53 +1054,60 @@ func (c *CPU) execute(opcode uint8) uint8 {
 		c.Registers.SetFlagTo(FlagC, (c.Registers.SP&0xFF)+(uint16(offset)&0xFF) > 0xFF) //nolint:gosec // G115: Intentional for flag calculation
 		c.Registers.SP = result
 		return 16
-	case 0xE9: // JP (HL)
+	},
+	0xE9: func(c *CPU) uint8 { // JP (HL)
 		c.Registers.PC = c.Registers.HL()
 		return 4
-	case 0xEA: // LD (nn), A
+	},
+	0xEA: func(c *CPU) uint8 { // LD (nn), A
 		c.Memory.Write(c.fetchWord(), c.Registers.A)
 		return 16
-	case 0xEB: // Invalid opcode
-		panic("Invalid opcode 0xEB")
-	case 0xEC: // Invalid opcode
-		panic("Invalid opcode 0xEC")
-	case 0xED: // Invalid opcode
-		panic("Invalid opcode 0xED")
-	case 0xEE: // XOR n
+	},
+	0xEB: illegalOpcode(0xEB), // Invalid opcode
+	0xEC: illegalOpcode(0xEC), // Invalid opcode
+	0xED: illegalOpcode(0xED), // Invalid opcode
+	0xEE: func(c *CPU) uint8 { // XOR n
 		c.Registers.A = c.xor(c.fetchByte())
 		return 8
-	case 0xEF: // RST 28H
+	},
+	0xEF: func(c *CPU) uint8 { // RST 28H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x28
 		return 16
-
+	},
 	// 0xF0-0xFF: I/O operations and more
-	case 0xF0: // LDH A, (n)
+	0xF0: func(c *CPU) uint8 { // LDH A, (n)
 		c.Registers.A = c.Memory.Read(0xFF00 + uint16(c.fetchByte()))
 		return 12
-	case 0xF1: // POP AF
+	},
+	0xF1: func(c *CPU) uint8 { // POP AF
 		c.Registers.SetAF(c.pop())
 		return 12
-	case 0xF2: // LD A, (C)
+	},
+	0xF2: func(c *CPU) uint8 { // LD A, (C)
 		c.Registers.A = c.Memory.Read(0xFF00 + uint16(c.Registers.C))
 		return 8
-	case 0xF3: // DI
+	},
+	0xF3: func(c *CPU) uint8 { // DI
 		c.IME = false
 		c.pendingIME = false // Cancel any pending EI
 		return 4
-	case 0xF4: // Invalid opcode
-		panic("Invalid opcode 0xF4")
-	case 0xF5: // PUSH AF
+	},
+	0xF4: illegalOpcode(0xF4), // Invalid opcode
+	0xF5: func(c *CPU) uint8 { // PUSH AF
 		c.push(c.Registers.AF())
 		return 16
-	case 0xF6: // OR n
+	},
+	0xF6: func(c *CPU) uint8 { // OR n
 		c.Registers.A = c.or(c.fetchByte())
 		return 8
-	case 0xF7: // RST 30H
+	},
+	0xF7: func(c *CPU) uint8 { // RST 30H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x30
 		return 16
-	case 0xF8: // LD HL, SP+n
+	},
+	0xF8: func(c *CPU) uint8 { // LD HL, SP+n
 		offset := int8(c.fetchByte())                           //nolint:gosec // G115: Intentional signed conversion for relative jump
 		result := uint16(int32(c.Registers.SP) + int32(offset)) //nolint:gosec // G115: Intentional for SP offset calculation
 		// Flags for LD HL, SP+n
@@ -886,31 +1117,31 @@ func (c *CPU) execute(opcode uint8) uint8 {
 		c.Registers.SetFlagTo(FlagC, (c.Registers.SP&0xFF)+(uint16(offset)&0xFF) > 0xFF) //nolint:gosec // G115: Intentional for flag calculation
 		c.Registers.SetHL(result)
 		return 12
-	case 0xF9: // LD SP, HL
+	},
+	0xF9: func(c *CPU) uint8 { // LD SP, HL
 		c.Registers.SP = c.Registers.HL()
 		return 8
-	case 0xFA: // LD A, (nn)
+	},
+	0xFA: func(c *CPU) uint8 { // LD A, (nn)
 		c.Registers.A = c.Memory.Read(c.fetchWord())
 		return 16
-	case 0xFB: // EI
+	},
+	0xFB: func(c *CPU) uint8 { // EI
 		// EI enables interrupts AFTER the next instruction executes
 		c.pendingIME = true
 		return 4
-	case 0xFC: // Invalid opcode
-		panic("Invalid opcode 0xFC")
-	case 0xFD: // Invalid opcode
-		panic("Invalid opcode 0xFD")
-	case 0xFE: // CP n
+	},
+	0xFC: illegalOpcode(0xFC), // Invalid opcode
+	0xFD: illegalOpcode(0xFD), // Invalid opcode
+	0xFE: func(c *CPU) uint8 { // CP n
 		c.cp(c.fetchByte())
 		return 8
-	case 0xFF: // RST 38H
+	},
+	0xFF: func(c *CPU) uint8 { // RST 38H
 		c.push(c.Registers.PC)
 		c.Registers.PC = 0x38
 		return 16
-
-	default:
-		panic("Unknown opcode")
-	}
+	},
 }
 
 // daa performs Decimal Adjust Accumulator (DAA) operation.
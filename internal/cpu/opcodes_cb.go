@@ -1,53 +1,40 @@
 package cpu
 
+// cbTable dispatches the CB-prefixed opcodes.
+var cbTable = buildCBTable()
+
 // executeCB executes a CB-prefixed opcode and returns the number of cycles taken.
 func (c *CPU) executeCB(opcode uint8) uint8 {
-	// Get the target register/memory based on lower 3 bits
-	getTarget := func() *uint8 {
-		switch opcode & 0x07 {
-		case 0:
-			return &c.Registers.B
-		case 1:
-			return &c.Registers.C
-		case 2:
-			return &c.Registers.D
-		case 3:
-			return &c.Registers.E
-		case 4:
-			return &c.Registers.H
-		case 5:
-			return &c.Registers.L
-		case 6:
-			return nil // (HL)
-		case 7:
-			return &c.Registers.A
-		}
-		return nil
-	}
+	return cbTable[opcode](c)
+}
 
-	// Helper to get value (handles (HL) case)
-	getValue := func() uint8 {
-		if opcode&0x07 == 6 {
-			return c.Memory.Read(c.Registers.HL())
-		}
-		target := getTarget()
-		if target != nil {
-			return *target
-		}
-		return 0
+// buildCBTable builds the handlers for all 256 CB-prefixed opcodes.
+func buildCBTable() [256]opcodeHandler {
+	var table [256]opcodeHandler
+	for i := range table {
+		table[i] = cbHandler(uint8(i)) //nolint:gosec // G115: i is 0-255
 	}
+	return table
+}
 
-	// Helper to set value (handles (HL) case)
-	setValue := func(value uint8) {
-		if opcode&0x07 == 6 {
-			c.Memory.Write(c.Registers.HL(), value)
-		} else {
-			target := getTarget()
-			if target != nil {
-				*target = value
-			}
-		}
-	}
+// cbShifts are the rotate and shift operations (0x00-0x3F), indexed by bits 3-5.
+var cbShifts = [8]func(c *CPU, value uint8) uint8{
+	(*CPU).rlc,  // RLC
+	(*CPU).rrc,  // RRC
+	(*CPU).rl,   // RL
+	(*CPU).rr,   // RR
+	(*CPU).sla,  // SLA
+	(*CPU).sra,  // SRA
+	(*CPU).swap, // SWAP
+	(*CPU).srl,  // SRL
+}
+
+// cbHandler returns the handler for a CB-prefixed opcode. The target, operation
+// and bit number are decoded once here rather than on every execution.
+func cbHandler(opcode uint8) opcodeHandler {
+	// Target register/memory is selected by the lower 3 bits
+	target := opcode & 0x07
+	isHL := target == 6
 
 	// Determine operation type and bit number
 	operation := (opcode >> 6) & 0x03
@@ -55,7 +42,7 @@ func (c *CPU) executeCB(opcode uint8) uint8 {
 
 	// Calculate cycles (most are 8, (HL) operations are 16, BIT (HL) is 12)
 	cycles := uint8(8)
-	if opcode&0x07 == 6 {
+	if isHL {
 		if operation == 1 { // BIT
 			cycles = 12
 		} else {
@@ -63,50 +50,70 @@ func (c *CPU) executeCB(opcode uint8) uint8 {
 		}
 	}
 
-	switch operation {
-	case 0: // Rotates and shifts (0x00-0x3F)
-		value := getValue()
-		var result uint8
+	// Helper to get value (handles (HL) case)
+	getValue := func(c *CPU) uint8 {
+		if isHL {
+			return c.Memory.Read(c.Registers.HL())
+		}
+		return *c.cbRegister(target)
+	}
 
-		switch bitNum {
-		case 0: // RLC
-			result = c.rlc(value)
-		case 1: // RRC
-			result = c.rrc(value)
-		case 2: // RL
-			result = c.rl(value)
-		case 3: // RR
-			result = c.rr(value)
-		case 4: // SLA
-			result = c.sla(value)
-		case 5: // SRA
-			result = c.sra(value)
-		case 6: // SWAP
-			result = c.swap(value)
-		case 7: // SRL
-			result = c.srl(value)
+	// Helper to set value (handles (HL) case)
+	setValue := func(c *CPU, value uint8) {
+		if isHL {
+			c.Memory.Write(c.Registers.HL(), value)
+		} else {
+			*c.cbRegister(target) = value
 		}
+	}
 
-		setValue(result)
-		return cycles
+	switch operation {
+	case 0: // Rotates and shifts (0x00-0x3F)
+		shift := cbShifts[bitNum]
+		return func(c *CPU) uint8 {
+			setValue(c, shift(c, getValue(c)))
+			return cycles
+		}
 
 	case 1: // BIT (0x40-0x7F)
-		value := getValue()
-		c.bit(value, bitNum)
-		return cycles
+		return func(c *CPU) uint8 {
+			c.bit(getValue(c), bitNum)
+			return cycles
+		}
 
 	case 2: // RES (0x80-0xBF)
-		value := getValue()
-		result := value &^ (1 << bitNum)
-		setValue(result)
-		return cycles
+		mask := uint8(1) << bitNum
+		return func(c *CPU) uint8 {
+			setValue(c, getValue(c)&^mask)
+			return cycles
+		}
 
-	case 3: // SET (0xC0-0xFF)
-		value := getValue()
-		result := value | (1 << bitNum)
-		setValue(result)
-		return cycles
+	default: // SET (0xC0-0xFF)
+		mask := uint8(1) << bitNum
+		return func(c *CPU) uint8 {
+			setValue(c, getValue(c)|mask)
+			return cycles
+		}
 	}
+}
 
-	return cycles
+// cbRegister returns the register selected by the lower 3 bits of a CB opcode.
+// Target 6 is (HL) and is handled by the caller.
+func (c *CPU) cbRegister(target uint8) *uint8 {
+	switch target {
+	case 0:
+		return &c.Registers.B
+	case 1:
+		return &c.Registers.C
+	case 2:
+		return &c.Registers.D
+	case 3:
+		return &c.Registers.E
+	case 4:
+		return &c.Registers.H
+	case 5:
+		return &c.Registers.L
+	default:
+		return &c.Registers.A
+	}
 }
@@ -0,0 +1,156 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+// invalidOpcodes are the unprefixed opcodes not defined on the SM83.
+var invalidOpcodes = []uint8{0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD}
+
+func TestOpcodeTableComplete(t *testing.T) {
+	for op := range opcodeTable {
+		if opcodeTable[op] == nil {
+			t.Errorf("opcodeTable[0x%02X] = nil, want handler", op)
+		}
+		if cbTable[op] == nil {
+			t.Errorf("cbTable[0x%02X] = nil, want handler", op)
+		}
+	}
+}
+
+func TestIllegalOpcodeLockup(t *testing.T) {
+	for _, op := range invalidOpcodes {
+		cpu, mem := setupCPU()
+		cpu.Registers.PC = 0xC000
+		mem.data[0xC000] = op
+
+		// Step must not panic
+		if cycles := cpu.Step(); cycles != 4 {
+			t.Errorf("opcode 0x%02X: cycles = %d, want 4", op, cycles)
+		}
+		if !cpu.LockedUp() {
+			t.Errorf("opcode 0x%02X: LockedUp() = false, want true", op)
+		}
+		if err := cpu.LastError(); err != nil {
+			t.Errorf("opcode 0x%02X: LastError() = %v, want nil", op, err)
+		}
+
+		// A locked-up CPU executes nothing and ignores interrupts
+		cpu.IME = true
+		mem.data[0xFFFF] = 0x01
+		mem.data[0xFF0F] = 0x01
+		pc := cpu.Registers.PC
+		for range 10 {
+			if cycles := cpu.Step(); cycles != 4 {
+				t.Errorf("opcode 0x%02X: locked cycles = %d, want 4", op, cycles)
+			}
+		}
+		if cpu.Registers.PC != pc {
+			t.Errorf("opcode 0x%02X: PC = 0x%04X, want 0x%04X", op, cpu.Registers.PC, pc)
+		}
+	}
+}
+
+func TestIllegalOpcodeError(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.SetIllegalOpcodeMode(IllegalOpcodeError)
+	cpu.Registers.PC = 0xC000
+	mem.data[0xC000] = 0xD3
+
+	if err := cpu.LastError(); err != nil {
+		t.Fatalf("LastError() before step = %v, want nil", err)
+	}
+
+	cpu.Step()
+
+	err := cpu.LastError()
+	if !errors.Is(err, ErrIllegalOpcode) {
+		t.Fatalf("LastError() = %v, want ErrIllegalOpcode", err)
+	}
+	if want := "illegal opcode 0xD3 at $C000"; err.Error() != want {
+		t.Errorf("LastError() = %q, want %q", err.Error(), want)
+	}
+	if !cpu.LockedUp() {
+		t.Error("LockedUp() = false, want true")
+	}
+}
+
+func TestExecuteCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		opcode uint8
+		cb     bool
+		want   uint8
+	}{
+		{"NOP", 0x00, false, 4},
+		{"LD BC, nn", 0x01, false, 12},
+		{"LD (nn), SP", 0x08, false, 20},
+		{"LD A, (HL)", 0x7E, false, 8},
+		{"ADC A, n", 0xCE, false, 8},
+		{"RST 38H", 0xFF, false, 16},
+		{"RLC B", 0x00, true, 8},
+		{"RLC (HL)", 0x06, true, 16},
+		{"BIT 0, (HL)", 0x46, true, 12},
+		{"RES 7, (HL)", 0xBE, true, 16},
+		{"SET 7, A", 0xFF, true, 8},
+	}
+
+	for _, tt := range tests {
+		cpu, _ := setupCPU()
+		var got uint8
+		if tt.cb {
+			got = cpu.executeCB(tt.opcode)
+		} else {
+			got = cpu.execute(tt.opcode)
+		}
+		if got != tt.want {
+			t.Errorf("%s cycles = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteCBTargets(t *testing.T) {
+	cpu, mem := setupCPU()
+	cpu.Registers.SetHL(0xC000)
+	mem.data[0xC000] = 0x00
+
+	cpu.executeCB(0xC7) // SET 0, A
+	cpu.executeCB(0xF8) // SET 7, B
+	cpu.executeCB(0xDE) // SET 3, (HL)
+	cpu.executeCB(0x31) // SWAP C
+
+	if cpu.Registers.A&0x01 == 0 {
+		t.Errorf("A = 0x%02X, want bit 0 set", cpu.Registers.A)
+	}
+	if cpu.Registers.B&0x80 == 0 {
+		t.Errorf("B = 0x%02X, want bit 7 set", cpu.Registers.B)
+	}
+	if mem.data[0xC000] != 0x08 {
+		t.Errorf("(HL) = 0x%02X, want 0x08", mem.data[0xC000])
+	}
+}
+
+// dispatchOpcodes is a mix of common register, memory and flag opcodes
+// that leave PC and SP usable when executed repeatedly.
+var dispatchOpcodes = []uint8{0x00, 0x04, 0x0D, 0x3C, 0x47, 0x78, 0x7E, 0x80, 0xA8, 0xB1, 0xBF, 0x23, 0x2B, 0x37, 0x3F}
+
+func BenchmarkExecute(b *testing.B) {
+	cpu, _ := setupCPU()
+	cpu.Registers.SetHL(0xC000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cpu.execute(dispatchOpcodes[i%len(dispatchOpcodes)])
+	}
+}
+
+func BenchmarkExecuteCB(b *testing.B) {
+	cpu, _ := setupCPU()
+	cpu.Registers.SetHL(0xC000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cpu.executeCB(uint8(i)) //nolint:gosec // G115: Intentional wrap to cover all CB opcodes
+	}
+}
@@ -22,7 +22,9 @@ type Registers struct {
 	PC uint16 // Program counter
 }
 
-// NewRegisters creates a new Registers instance with default values.
+// NewRegisters creates a new Registers instance with the DMG power-up values,
+// i.e. the state the boot ROM leaves behind when it jumps to 0x0100:
+// AF=0x01B0 (Z, H and C set), BC=0x0013, DE=0x00D8, HL=0x014D, SP=0xFFFE.
 func NewRegisters() *Registers {
 	return &Registers{
 		A:  0x01,
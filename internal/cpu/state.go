@@ -0,0 +1,63 @@
+package cpu
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// cpuState is the CPU state written by SaveState.
+type cpuState struct {
+	Registers     Registers
+	IME           bool
+	PendingIME    bool
+	Halted        bool
+	Stopped       bool
+	HaltBug       bool
+	WasHaltBug    bool
+	Cycles        uint64
+	LockedUp      bool
+	BreakpointHit bool
+}
+
+// SaveState writes the registers and execution state to w.
+func (c *CPU) SaveState(w io.Writer) error {
+	s := cpuState{
+		Registers:     *c.Registers,
+		IME:           c.IME,
+		PendingIME:    c.pendingIME,
+		Halted:        c.halted,
+		Stopped:       c.stopped,
+		HaltBug:       c.haltBug,
+		WasHaltBug:    c.wasHaltBug,
+		Cycles:        c.Cycles,
+		LockedUp:      c.lockedUp,
+		BreakpointHit: c.breakpointHit,
+	}
+	if err := binary.Write(w, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to write CPU state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState. The illegal opcode mode,
+// tracer and OnInterrupt hook are configuration and are kept.
+func (c *CPU) LoadState(r io.Reader) error {
+	var s cpuState
+	if err := binary.Read(r, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to read CPU state: %w", err)
+	}
+
+	*c.Registers = s.Registers
+	c.IME = s.IME
+	c.pendingIME = s.PendingIME
+	c.halted = s.Halted
+	c.stopped = s.Stopped
+	c.haltBug = s.HaltBug
+	c.wasHaltBug = s.WasHaltBug
+	c.Cycles = s.Cycles
+	c.lockedUp = s.LockedUp
+	c.lastErr = nil
+	c.breakpointHit = s.BreakpointHit
+	return nil
+}
@@ -0,0 +1,58 @@
+package cpu
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SetTracer enables execution tracing to w. Before each instruction executes,
+// a line is written with the PC, raw opcode bytes, decoded mnemonic and
+// register state. Pass nil to disable tracing.
+func (c *CPU) SetTracer(w io.Writer) {
+	c.tracer = w
+}
+
+// Tracer returns the writer set by SetTracer, or nil if tracing is disabled.
+func (c *CPU) Tracer() io.Writer {
+	return c.tracer
+}
+
+// trace writes a trace line for the instruction at PC.
+// Write errors are ignored; tracing must never affect emulation.
+func (c *CPU) trace() {
+	pc := c.Registers.PC
+	mnemonic, length := Disassemble(c.Memory, pc)
+
+	var raw strings.Builder
+	for i := range length {
+		if i > 0 {
+			raw.WriteByte(' ')
+		}
+		fmt.Fprintf(&raw, "%02X", c.peek(pc+i))
+	}
+
+	ime := 0
+	if c.IME {
+		ime = 1
+	}
+
+	_, _ = fmt.Fprintf(c.tracer, "PC:%04X OP:%-8s %-16s A:%02X F:%s BC:%04X DE:%04X HL:%04X SP:%04X IME:%d\n",
+		pc, raw.String(), mnemonic,
+		c.Registers.A, c.flagString(), c.Registers.BC(), c.Registers.DE(), c.Registers.HL(),
+		c.Registers.SP, ime)
+}
+
+// flagString formats the flags register as "ZNHC", using '-' for clear flags.
+func (c *CPU) flagString() string {
+	flags := []byte("----")
+	for i, f := range [4]struct {
+		flag uint8
+		name byte
+	}{{FlagZ, 'Z'}, {FlagN, 'N'}, {FlagH, 'H'}, {FlagC, 'C'}} {
+		if c.Registers.GetFlag(f.flag) {
+			flags[i] = f.name
+		}
+	}
+	return string(flags)
+}
@@ -0,0 +1,84 @@
+package cpu
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDisassemble(t *testing.T) {
+	mem := newMockMemory()
+
+	tests := []struct {
+		name     string
+		code     []uint8
+		mnemonic string
+		length   uint16
+	}{
+		{"NOP", []uint8{0x00}, "NOP", 1},
+		{"LD A,n", []uint8{0x3E, 0x42}, "LD A,$42", 2},
+		{"JP nn", []uint8{0xC3, 0x50, 0x01}, "JP $0150", 3},
+		{"JR backwards", []uint8{0x18, 0xFE}, "JR $0100", 2},
+		{"LDH (n),A", []uint8{0xE0, 0x44}, "LDH ($FF44),A", 2},
+		{"LD HL,SP+n", []uint8{0xF8, 0xFB}, "LD HL,SP-5", 2},
+		{"LD r,r", []uint8{0x78}, "LD A,B", 1},
+		{"HALT", []uint8{0x76}, "HALT", 1},
+		{"XOR (HL)", []uint8{0xAE}, "XOR (HL)", 1},
+		{"CB BIT", []uint8{0xCB, 0x7C}, "BIT 7,H", 2},
+		{"CB SWAP", []uint8{0xCB, 0x37}, "SWAP A", 2},
+		{"Invalid", []uint8{0xD3}, "DB $D3", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copy(mem.data[0x0100:], tt.code)
+
+			mnemonic, length := Disassemble(mem, 0x0100)
+			if mnemonic != tt.mnemonic {
+				t.Errorf("mnemonic = %q, want %q", mnemonic, tt.mnemonic)
+			}
+			if length != tt.length {
+				t.Errorf("length = %d, want %d", length, tt.length)
+			}
+		})
+	}
+}
+
+func TestTracer(t *testing.T) {
+	cpu, mem := setupCPU()
+
+	mem.data[0x0100] = 0x3E // LD A, $42
+	mem.data[0x0101] = 0x42
+	mem.data[0x0102] = 0xC3 // JP $0150
+	mem.data[0x0103] = 0x50
+	mem.data[0x0104] = 0x01
+
+	var buf bytes.Buffer
+	cpu.SetTracer(&buf)
+
+	cpu.Step()
+	cpu.Step()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d trace lines, want 2:\n%s", len(lines), buf.String())
+	}
+
+	want := []string{
+		"PC:0100 OP:3E 42    LD A,$42         A:01 F:Z-HC BC:0013 DE:00D8 HL:014D SP:FFFE IME:0",
+		"PC:0102 OP:C3 50 01 JP $0150         A:42 F:Z-HC BC:0013 DE:00D8 HL:014D SP:FFFE IME:0",
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d:\n got %q\nwant %q", i, lines[i], want[i])
+		}
+	}
+
+	// Disabling the tracer stops output
+	cpu.SetTracer(nil)
+	buf.Reset()
+	cpu.Step()
+	if buf.Len() != 0 {
+		t.Errorf("tracer disabled but wrote %q", buf.String())
+	}
+}
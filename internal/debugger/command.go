@@ -0,0 +1,171 @@
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnknownCommand indicates the command name is not recognized.
+	ErrUnknownCommand = errors.New("unknown command")
+
+	// ErrMissingArgument indicates a required command argument is missing.
+	ErrMissingArgument = errors.New("missing argument")
+
+	// ErrInvalidArgument indicates a command argument could not be parsed.
+	ErrInvalidArgument = errors.New("invalid argument")
+)
+
+// CommandKind identifies a debugger command.
+type CommandKind int
+
+// Debugger commands.
+const (
+	CmdNone        CommandKind = iota // Empty line (no-op)
+	CmdStep                           // s [n]: step n instructions
+	CmdNext                           // n: step over CALL/RST
+	CmdContinue                       // c: run until a breakpoint
+	CmdBreak                          // b <addr>: set a breakpoint
+	CmdDelete                         // d <addr>: delete a breakpoint
+	CmdBreakpoints                    // bl: list breakpoints
+	CmdExamine                        // x <addr> [n]: dump n bytes of memory
+	CmdWrite                          // w <addr> <value>: write a byte to memory
+	CmdRegisters                      // r: show registers
+	CmdList                           // l [addr]: disassemble
+	CmdHelp                           // h: show help
+	CmdQuit                           // q: quit
+)
+
+// Command is a parsed debugger command.
+type Command struct {
+	Kind    CommandKind
+	Addr    uint16 // Address argument (b, d, x, w, l)
+	HasAddr bool   // Whether an address was given (l)
+	Count   int    // Count argument (s, x)
+	Value   uint8  // Value argument (w)
+}
+
+// Default command arguments.
+const (
+	defaultExamineCount = 16
+	maxExamineCount     = 0x10000
+)
+
+// commandNames maps command names and aliases to command kinds.
+var commandNames = map[string]CommandKind{
+	"s": CmdStep, "step": CmdStep,
+	"n": CmdNext, "next": CmdNext,
+	"c": CmdContinue, "continue": CmdContinue,
+	"b": CmdBreak, "break": CmdBreak,
+	"d": CmdDelete, "delete": CmdDelete,
+	"bl": CmdBreakpoints, "breakpoints": CmdBreakpoints,
+	"x": CmdExamine, "examine": CmdExamine,
+	"w": CmdWrite, "write": CmdWrite,
+	"r": CmdRegisters, "regs": CmdRegisters,
+	"l": CmdList, "list": CmdList,
+	"h": CmdHelp, "help": CmdHelp, "?": CmdHelp,
+	"q": CmdQuit, "quit": CmdQuit,
+}
+
+// ParseCommand parses a debugger command line.
+// Addresses and byte values are hexadecimal, with an optional "0x" or "$" prefix.
+// Counts are decimal unless prefixed with "0x".
+func ParseCommand(line string) (Command, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Command{Kind: CmdNone}, nil
+	}
+
+	kind, ok := commandNames[strings.ToLower(fields[0])]
+	if !ok {
+		return Command{}, fmt.Errorf("%w: %q", ErrUnknownCommand, fields[0])
+	}
+	cmd := Command{Kind: kind}
+	args := fields[1:]
+
+	var err error
+	switch kind {
+	case CmdStep:
+		cmd.Count = 1
+		if len(args) > 0 {
+			cmd.Count, err = parseCount(args[0])
+		}
+
+	case CmdBreak, CmdDelete:
+		if len(args) < 1 {
+			return Command{}, fmt.Errorf("%w: address", ErrMissingArgument)
+		}
+		cmd.Addr, err = parseAddr(args[0])
+		cmd.HasAddr = true
+
+	case CmdExamine:
+		if len(args) < 1 {
+			return Command{}, fmt.Errorf("%w: address", ErrMissingArgument)
+		}
+		cmd.Addr, err = parseAddr(args[0])
+		cmd.HasAddr = true
+		cmd.Count = defaultExamineCount
+		if err == nil && len(args) > 1 {
+			cmd.Count, err = parseCount(args[1])
+		}
+		if err == nil && cmd.Count > maxExamineCount {
+			err = fmt.Errorf("%w: count %d exceeds %d", ErrInvalidArgument, cmd.Count, maxExamineCount)
+		}
+
+	case CmdWrite:
+		if len(args) < 2 {
+			return Command{}, fmt.Errorf("%w: address and value", ErrMissingArgument)
+		}
+		cmd.Addr, err = parseAddr(args[0])
+		cmd.HasAddr = true
+		if err == nil {
+			cmd.Value, err = parseByte(args[1])
+		}
+
+	case CmdList:
+		if len(args) > 0 {
+			cmd.Addr, err = parseAddr(args[0])
+			cmd.HasAddr = true
+		}
+
+	case CmdNone, CmdNext, CmdContinue, CmdBreakpoints, CmdRegisters, CmdHelp, CmdQuit:
+	}
+
+	if err != nil {
+		return Command{}, err
+	}
+	return cmd, nil
+}
+
+// parseHex parses a hexadecimal number with an optional "0x" or "$" prefix.
+func parseHex(s string, bitSize int) (uint64, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(s), "0x"), "$")
+	value, err := strconv.ParseUint(digits, 16, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidArgument, s)
+	}
+	return value, nil
+}
+
+// parseAddr parses a 16-bit hexadecimal address.
+func parseAddr(s string) (uint16, error) {
+	value, err := parseHex(s, 16)
+	return uint16(value), err //nolint:gosec // G115: ParseUint limits value to 16 bits
+}
+
+// parseByte parses an 8-bit hexadecimal value.
+func parseByte(s string) (uint8, error) {
+	value, err := parseHex(s, 8)
+	return uint8(value), err //nolint:gosec // G115: ParseUint limits value to 8 bits
+}
+
+// parseCount parses a positive count.
+func parseCount(s string) (int, error) {
+	value, err := strconv.ParseUint(s, 0, 32)
+	if err != nil || value == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidArgument, s)
+	}
+	return int(value), nil
+}
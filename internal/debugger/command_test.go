@@ -0,0 +1,75 @@
+package debugger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want Command
+	}{
+		{"", Command{Kind: CmdNone}},
+		{"   ", Command{Kind: CmdNone}},
+		{"s", Command{Kind: CmdStep, Count: 1}},
+		{"step 10", Command{Kind: CmdStep, Count: 10}},
+		{"s 0x10", Command{Kind: CmdStep, Count: 16}},
+		{"n", Command{Kind: CmdNext}},
+		{"c", Command{Kind: CmdContinue}},
+		{"continue", Command{Kind: CmdContinue}},
+		{"b 0x150", Command{Kind: CmdBreak, Addr: 0x0150, HasAddr: true}},
+		{"b 150", Command{Kind: CmdBreak, Addr: 0x0150, HasAddr: true}},
+		{"break $C000", Command{Kind: CmdBreak, Addr: 0xC000, HasAddr: true}},
+		{"d 0x150", Command{Kind: CmdDelete, Addr: 0x0150, HasAddr: true}},
+		{"bl", Command{Kind: CmdBreakpoints}},
+		{"x 0xc000 16", Command{Kind: CmdExamine, Addr: 0xC000, HasAddr: true, Count: 16}},
+		{"x ff80", Command{Kind: CmdExamine, Addr: 0xFF80, HasAddr: true, Count: 16}},
+		{"w 0xc000 0x42", Command{Kind: CmdWrite, Addr: 0xC000, HasAddr: true, Value: 0x42}},
+		{"r", Command{Kind: CmdRegisters}},
+		{"l", Command{Kind: CmdList}},
+		{"l 0x100", Command{Kind: CmdList, Addr: 0x0100, HasAddr: true}},
+		{"h", Command{Kind: CmdHelp}},
+		{"?", Command{Kind: CmdHelp}},
+		{"Q", Command{Kind: CmdQuit}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := ParseCommand(tt.line)
+			if err != nil {
+				t.Fatalf("ParseCommand(%q) error = %v", tt.line, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCommand(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommandErrors(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantErr error
+	}{
+		{"jump", ErrUnknownCommand},
+		{"b", ErrMissingArgument},
+		{"x", ErrMissingArgument},
+		{"w 0xc000", ErrMissingArgument},
+		{"b 0x10000", ErrInvalidArgument},
+		{"b xyz", ErrInvalidArgument},
+		{"w 0xc000 0x100", ErrInvalidArgument},
+		{"s 0", ErrInvalidArgument},
+		{"s -1", ErrInvalidArgument},
+		{"x 0xc000 0x10001", ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			_, err := ParseCommand(tt.line)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseCommand(%q) error = %v, want %v", tt.line, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Package debugger implements an interactive, line-based debugger for the emulator.
+//
+// The debugger reads commands from an input stream (see ParseCommand for the
+// syntax), drives the emulator one instruction at a time and prints the CPU
+// registers, flags, disassembly around PC and the PPU state after each stop.
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/richardwooding/nostalgiza/internal/cpu"
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+const (
+	// prompt is printed before each command is read.
+	prompt = "(nostalgiza) "
+
+	// listLength is the number of instructions disassembled by the list command.
+	listLength = 8
+
+	// runCycleLimit stops continue/next if no breakpoint is reached
+	// (10 seconds of emulated time), since a line-based REPL cannot be interrupted.
+	runCycleLimit = 600 * ppu.DotsPerFrame
+)
+
+// helpText describes the available commands.
+const helpText = `Commands (addresses and values are hex, e.g. 150, 0x150 or $150):
+  s, step [n]            Step n instructions (default 1)
+  n, next                Step over CALL/RST
+  c, continue            Run until a breakpoint
+  b, break <addr>        Set a breakpoint
+  d, delete <addr>       Delete a breakpoint
+  bl, breakpoints        List breakpoints
+  x, examine <addr> [n]  Dump n bytes of memory (default 16)
+  w, write <addr> <v>    Write a byte to memory
+  r, regs                Show registers
+  l, list [addr]         Disassemble at addr (default PC)
+  h, help                Show this help
+  q, quit                Quit
+`
+
+// Debugger is an interactive debugger attached to an emulator.
+type Debugger struct {
+	emu         *emulator.Emulator
+	out         io.Writer
+	breakpoints map[uint16]bool
+}
+
+// New creates a debugger for emu that writes its output to out.
+func New(emu *emulator.Emulator, out io.Writer) *Debugger {
+	return &Debugger{
+		emu:         emu,
+		out:         out,
+		breakpoints: make(map[uint16]bool),
+	}
+}
+
+// Run reads commands from in until "quit" or end of input.
+func (d *Debugger) Run(in io.Reader) error {
+	d.printState()
+
+	scanner := bufio.NewScanner(in)
+	for {
+		d.printf("%s", prompt)
+		if !scanner.Scan() {
+			break
+		}
+
+		cmd, err := ParseCommand(scanner.Text())
+		if err != nil {
+			d.printf("Error: %v\n", err)
+			continue
+		}
+		if d.Execute(cmd) {
+			return nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read command: %w", err)
+	}
+	return nil
+}
+
+// Execute runs a parsed command and reports whether the debugger should quit.
+func (d *Debugger) Execute(cmd Command) bool {
+	switch cmd.Kind {
+	case CmdStep:
+		d.Step(cmd.Count)
+		d.printState()
+	case CmdNext:
+		d.StepOver()
+		d.printState()
+	case CmdContinue:
+		d.Continue()
+		d.printState()
+	case CmdBreak:
+		d.SetBreakpoint(cmd.Addr)
+		d.printf("Breakpoint set at $%04X\n", cmd.Addr)
+	case CmdDelete:
+		d.ClearBreakpoint(cmd.Addr)
+		d.printf("Breakpoint deleted at $%04X\n", cmd.Addr)
+	case CmdBreakpoints:
+		d.printBreakpoints()
+	case CmdExamine:
+		d.printMemory(cmd.Addr, cmd.Count)
+	case CmdWrite:
+		d.emu.Memory.Poke(cmd.Addr, cmd.Value)
+		d.printf("$%04X = $%02X\n", cmd.Addr, d.emu.Memory.Peek(cmd.Addr))
+	case CmdRegisters:
+		d.printState()
+	case CmdList:
+		addr := d.emu.CPU.Registers.PC
+		if cmd.HasAddr {
+			addr = cmd.Addr
+		}
+		d.printDisassembly(addr, listLength)
+	case CmdHelp:
+		d.printf("%s", helpText)
+	case CmdQuit:
+		return true
+	case CmdNone:
+	}
+	return false
+}
+
+// SetBreakpoint sets a breakpoint at addr.
+func (d *Debugger) SetBreakpoint(addr uint16) {
+	d.breakpoints[addr] = true
+}
+
+// ClearBreakpoint removes the breakpoint at addr.
+func (d *Debugger) ClearBreakpoint(addr uint16) {
+	delete(d.breakpoints, addr)
+}
+
+// Breakpoints returns the breakpoint addresses in ascending order.
+func (d *Debugger) Breakpoints() []uint16 {
+	addrs := make([]uint16, 0, len(d.breakpoints))
+	for addr := range d.breakpoints {
+		addrs = append(addrs, addr)
+	}
+	slices.Sort(addrs)
+	return addrs
+}
+
+// Step executes n instructions.
+func (d *Debugger) Step(n int) {
+	for range n {
+		d.step()
+	}
+}
+
+// step executes one instruction. A software breakpoint it executes is
+// cleared, so it does not stop a later run.
+func (d *Debugger) step() {
+	d.emu.Step()
+	d.emu.CPU.TakeBreakpoint()
+}
+
+// StepOver executes one instruction, running a CALL or RST until it returns.
+// It returns true if it stopped at a breakpoint before the call returned.
+func (d *Debugger) StepOver() bool {
+	regs := d.emu.CPU.Registers
+	opcode := d.emu.Memory.Peek(regs.PC)
+	if !isCall(opcode) {
+		d.step()
+		return false
+	}
+
+	returnAddr := regs.PC + cpu.InstructionLength(opcode)
+	sp := regs.SP
+	return d.runUntil(func() bool {
+		return regs.PC == returnAddr && regs.SP >= sp
+	})
+}
+
+// Continue runs until a breakpoint is reached and reports whether one was hit.
+func (d *Debugger) Continue() bool {
+	return d.runUntil(func() bool { return false })
+}
+
+// runUntil steps at least once, then until done returns true or a breakpoint
+// is reached. It reports whether it stopped at a breakpoint.
+func (d *Debugger) runUntil(done func() bool) bool {
+	start := d.emu.CPU.Cycles
+	for {
+		d.emu.Step()
+
+		// Always take the software breakpoint flag, so it is not left set
+		// when done ends the run on the same step
+		softBreak := d.emu.CPU.TakeBreakpoint()
+		if done() {
+			return false
+		}
+		if softBreak {
+			d.printf("Software breakpoint (LD B,B) at $%04X\n", d.emu.CPU.Registers.PC-1)
+			return true
+		}
+		if d.emu.CPU.LockedUp() {
+			d.printf("CPU locked up by an illegal opcode at $%04X\n", d.emu.CPU.Registers.PC-1)
+			return false
+		}
+		if d.breakpoints[d.emu.CPU.Registers.PC] {
+			d.printf("Breakpoint at $%04X\n", d.emu.CPU.Registers.PC)
+			return true
+		}
+		if d.emu.CPU.Cycles-start >= runCycleLimit {
+			d.printf("Stopped after %d cycles without reaching a breakpoint\n", runCycleLimit)
+			return false
+		}
+	}
+}
+
+// isCall reports whether opcode is a CALL or RST, which return to the next instruction.
+func isCall(opcode uint8) bool {
+	switch opcode {
+	case 0xCD, 0xC4, 0xCC, 0xD4, 0xDC: // CALL, CALL cc
+		return true
+	}
+	return opcode&0xC7 == 0xC7 // RST n
+}
+
+// printState prints the registers, PPU state and disassembly at PC.
+func (d *Debugger) printState() {
+	regs := d.emu.CPU.Registers
+	ime := 0
+	if d.emu.CPU.IME {
+		ime = 1
+	}
+	// The mode as the game reads it from STAT, which is 0 while the LCD is off
+	mode := d.emu.Memory.Peek(0xFF41) & 0x03
+	d.printf("PC:%04X SP:%04X A:%02X F:%s BC:%04X DE:%04X HL:%04X IME:%d LY:%02X MODE:%d\n",
+		regs.PC, regs.SP, regs.A, flagString(regs), regs.BC(), regs.DE(), regs.HL(), ime,
+		d.emu.PPU.LY(), mode)
+	d.printDisassembly(regs.PC, listLength/2)
+}
+
+// printDisassembly prints count instructions starting at addr, marking PC.
+func (d *Debugger) printDisassembly(addr uint16, count int) {
+	for range count {
+		mnemonic, length := cpu.Disassemble(d.emu.Memory, addr)
+
+		marker := "  "
+		if addr == d.emu.CPU.Registers.PC {
+			marker = "->"
+		}
+		if d.breakpoints[addr] {
+			marker = "*" + marker[1:]
+		}
+
+		d.printf("%s %04X  %s\n", marker, addr, mnemonic)
+		addr += length
+	}
+}
+
+// printMemory prints count bytes of memory starting at addr, 16 per row.
+// VRAM and OAM are shown whatever the current PPU mode.
+func (d *Debugger) printMemory(addr uint16, count int) {
+	d.emu.PPU.SetAccessBlocking(false)
+	defer d.emu.PPU.SetAccessBlocking(true)
+
+	var line strings.Builder
+	for i := range count {
+		if i%16 == 0 {
+			if i > 0 {
+				d.printf("%s\n", line.String())
+				line.Reset()
+			}
+			fmt.Fprintf(&line, "%04X:", addr)
+		}
+		fmt.Fprintf(&line, " %02X", d.emu.Memory.Peek(addr))
+		addr++
+	}
+	d.printf("%s\n", line.String())
+}
+
+// printBreakpoints lists the breakpoints.
+func (d *Debugger) printBreakpoints() {
+	addrs := d.Breakpoints()
+	if len(addrs) == 0 {
+		d.printf("No breakpoints\n")
+		return
+	}
+	for _, addr := range addrs {
+		d.printf("$%04X\n", addr)
+	}
+}
+
+// printf writes formatted output. Write errors are ignored.
+func (d *Debugger) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(d.out, format, args...)
+}
+
+// flagString formats the flags register as "ZNHC", using '-' for clear flags.
+func flagString(regs *cpu.Registers) string {
+	flags := []byte("----")
+	for i, f := range [4]struct {
+		flag uint8
+		name byte
+	}{{cpu.FlagZ, 'Z'}, {cpu.FlagN, 'N'}, {cpu.FlagH, 'H'}, {cpu.FlagC, 'C'}} {
+		if regs.GetFlag(f.flag) {
+			flags[i] = f.name
+		}
+	}
+	return string(flags)
+}
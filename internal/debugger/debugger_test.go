@@ -0,0 +1,190 @@
+package debugger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+)
+
+// newTestDebugger creates a debugger for a ROM with code at 0x0100.
+func newTestDebugger(t *testing.T, code []byte) (*Debugger, *emulator.Emulator, *bytes.Buffer) {
+	t.Helper()
+
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0134:], []byte("TEST"))
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+	copy(rom[0x0100:], code)
+
+	emu, err := emulator.New(rom)
+	if err != nil {
+		t.Fatalf("emulator.New() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	return New(emu, &out), emu, &out
+}
+
+// testProgram calls a subroutine and loops.
+var testProgram = []byte{
+	0x00,             // 0100: NOP
+	0xCD, 0x08, 0x01, // 0101: CALL $0108
+	0x3C,       // 0104: INC A
+	0x18, 0xFD, // 0105: JR $0104
+	0x00, // 0107: NOP
+	0x04, // 0108: INC B
+	0x04, // 0109: INC B
+	0xC9, // 010A: RET
+}
+
+func run(t *testing.T, d *Debugger, line string) {
+	t.Helper()
+
+	cmd, err := ParseCommand(line)
+	if err != nil {
+		t.Fatalf("ParseCommand(%q) error = %v", line, err)
+	}
+	d.Execute(cmd)
+}
+
+func TestDebuggerStep(t *testing.T) {
+	d, emu, _ := newTestDebugger(t, testProgram)
+
+	run(t, d, "s")
+	if pc := emu.CPU.Registers.PC; pc != 0x0101 {
+		t.Errorf("PC after s = 0x%04X, want 0x0101", pc)
+	}
+
+	// Stepping into the CALL
+	run(t, d, "s 2")
+	if pc := emu.CPU.Registers.PC; pc != 0x0109 {
+		t.Errorf("PC after s 2 = 0x%04X, want 0x0109", pc)
+	}
+}
+
+func TestDebuggerStepOver(t *testing.T) {
+	d, emu, _ := newTestDebugger(t, testProgram)
+	run(t, d, "s")
+
+	run(t, d, "n")
+	if pc := emu.CPU.Registers.PC; pc != 0x0104 {
+		t.Errorf("PC after n = 0x%04X, want 0x0104", pc)
+	}
+	if b := emu.CPU.Registers.B; b != 0x02 {
+		t.Errorf("B after n = 0x%02X, want 0x02 (subroutine ran)", b)
+	}
+}
+
+func TestDebuggerBreakpoint(t *testing.T) {
+	d, emu, out := newTestDebugger(t, testProgram)
+
+	run(t, d, "b 0x109")
+	run(t, d, "c")
+	if pc := emu.CPU.Registers.PC; pc != 0x0109 {
+		t.Errorf("PC after c = 0x%04X, want 0x0109", pc)
+	}
+	if !strings.Contains(out.String(), "Breakpoint at $0109") {
+		t.Errorf("Output missing breakpoint message:\n%s", out.String())
+	}
+
+	// Stepping over a CALL stops at a breakpoint inside it
+	d2, emu2, _ := newTestDebugger(t, testProgram)
+	run(t, d2, "s")
+	run(t, d2, "b 0x109")
+	run(t, d2, "n")
+	if pc := emu2.CPU.Registers.PC; pc != 0x0109 {
+		t.Errorf("PC after n with breakpoint = 0x%04X, want 0x0109", pc)
+	}
+
+	run(t, d, "d 0x109")
+	if got := d.Breakpoints(); len(got) != 0 {
+		t.Errorf("Breakpoints() after delete = %v, want none", got)
+	}
+}
+
+func TestDebuggerMemory(t *testing.T) {
+	d, emu, out := newTestDebugger(t, testProgram)
+
+	run(t, d, "w 0xc000 0x42")
+	if got := emu.Memory.Read(0xC000); got != 0x42 {
+		t.Errorf("Memory[0xC000] = 0x%02X, want 0x42", got)
+	}
+
+	out.Reset()
+	run(t, d, "x 0xc000 4")
+	if got, want := out.String(), "C000: 42 00 00 00\n"; got != want {
+		t.Errorf("x output = %q, want %q", got, want)
+	}
+
+	out.Reset()
+	run(t, d, "x 0xc000 17")
+	if lines := strings.Count(out.String(), "\n"); lines != 2 {
+		t.Errorf("x 17 bytes printed %d lines, want 2", lines)
+	}
+}
+
+func TestDebuggerStepSoftwareBreakpoint(t *testing.T) {
+	d, _, out := newTestDebugger(t, []byte{
+		0x40,       // 0100: LD B,B
+		0x00,       // 0101: NOP
+		0x00,       // 0102: NOP
+		0x18, 0xFE, // 0103: JR $0103
+	})
+
+	// Stepping over LD B,B must not leave the breakpoint pending
+	run(t, d, "s")
+	run(t, d, "b 0103")
+	out.Reset()
+	run(t, d, "c")
+	if strings.Contains(out.String(), "Software breakpoint") {
+		t.Errorf("Continue after stepping over LD B,B reported a software breakpoint:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "Breakpoint at $0103") {
+		t.Errorf("Continue did not stop at the breakpoint:\n%s", out.String())
+	}
+}
+
+func TestDebuggerRegistersLCDOff(t *testing.T) {
+	d, emu, out := newTestDebugger(t, []byte{
+		0xAF,       // 0100: XOR A
+		0xE0, 0x40, // 0101: LDH (LCDC), A
+		0x18, 0xFE, // 0103: JR $0103
+	})
+
+	// The LCD is switched off during OAM scan, freezing the PPU's internal mode
+	run(t, d, "s 2")
+	if mode := emu.PPU.Mode(); mode == 0 {
+		t.Fatalf("PPU.Mode() = %d, want a mode other than 0 for this test", mode)
+	}
+
+	out.Reset()
+	run(t, d, "r")
+	if !strings.Contains(out.String(), "MODE:0") {
+		t.Errorf("Registers with the LCD off do not show MODE:0:\n%s", out.String())
+	}
+}
+
+func TestDebuggerRun(t *testing.T) {
+	d, emu, out := newTestDebugger(t, testProgram)
+
+	input := "s\nbogus\nl\nq\ns\n"
+	if err := d.Run(strings.NewReader(input)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Quit stops before the final step
+	if pc := emu.CPU.Registers.PC; pc != 0x0101 {
+		t.Errorf("PC after Run = 0x%04X, want 0x0101", pc)
+	}
+	if !strings.Contains(out.String(), "unknown command") {
+		t.Errorf("Output missing error for unknown command:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "-> 0101  CALL $0108") {
+		t.Errorf("Output missing disassembly at PC:\n%s", out.String())
+	}
+}
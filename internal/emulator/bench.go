@@ -0,0 +1,62 @@
+package emulator
+
+import (
+	"time"
+
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+)
+
+// ClockSpeed is the DMG CPU clock in cycles per second.
+const ClockSpeed = 4194304
+
+// CyclesPerFrame is the number of CPU cycles in one frame at normal speed
+// (about 59.73 frames per second).
+const CyclesPerFrame = ppu.DotsPerFrame
+
+// Throughput summarizes a headless run of the emulator.
+type Throughput struct {
+	Cycles  uint64        // Emulated CPU cycles
+	Frames  uint64        // Frames run
+	Elapsed time.Duration // Real time taken
+}
+
+// CyclesPerSecond returns the emulated cycles per real second.
+func (t Throughput) CyclesPerSecond() float64 {
+	if t.Elapsed <= 0 {
+		return 0
+	}
+	return float64(t.Cycles) / t.Elapsed.Seconds()
+}
+
+// FramesPerSecond returns the emulated frames per real second.
+func (t Throughput) FramesPerSecond() float64 {
+	if t.Elapsed <= 0 {
+		return 0
+	}
+	return float64(t.Frames) / t.Elapsed.Seconds()
+}
+
+// RealTimeRatio returns the emulation speed relative to real hardware
+// (1.0 = full speed, 2.0 = twice as fast).
+func (t Throughput) RealTimeRatio() float64 {
+	return t.CyclesPerSecond() / ClockSpeed
+}
+
+// RunFor runs the emulator headlessly, as fast as possible, for duration d
+// and returns the measured throughput.
+func (e *Emulator) RunFor(d time.Duration) Throughput {
+	startCycles := e.CPU.Cycles
+	start := time.Now()
+
+	var frames uint64
+	for time.Since(start) < d {
+		e.RunFrame()
+		frames++
+	}
+
+	return Throughput{
+		Cycles:  e.CPU.Cycles - startCycles,
+		Frames:  frames,
+		Elapsed: time.Since(start),
+	}
+}
@@ -0,0 +1,30 @@
+package emulator
+
+import (
+	"fmt"
+	"io"
+)
+
+// SetDoctorLog enables Gameboy Doctor compatible logging to w.
+// A line is written before every instruction, ahead of interrupt servicing,
+// so the log can be diffed against the reference logs for Blargg's cpu_instrs.
+// Pass nil to disable logging.
+//
+// Gameboy Doctor reference logs are generated with LY (0xFF44) stubbed to
+// 0x90, so LY reads as 0x90 while logging is enabled.
+func (e *Emulator) SetDoctorLog(w io.Writer) {
+	e.doctorLog = w
+	e.Memory.SetLYStub(w != nil)
+}
+
+// logDoctorState writes the current CPU state in Gameboy Doctor format:
+// A:00 F:00 B:00 C:00 D:00 E:00 H:00 L:00 SP:0000 PC:0100 PCMEM:00,00,00,00.
+// Write errors are ignored; logging must never affect emulation.
+func (e *Emulator) logDoctorState() {
+	r := e.CPU.Registers
+	pc := r.PC
+	_, _ = fmt.Fprintf(e.doctorLog,
+		"A:%02X F:%02X B:%02X C:%02X D:%02X E:%02X H:%02X L:%02X SP:%04X PC:%04X PCMEM:%02X,%02X,%02X,%02X\n",
+		r.A, r.F, r.B, r.C, r.D, r.E, r.H, r.L, r.SP, pc,
+		e.Memory.Peek(pc), e.Memory.Peek(pc+1), e.Memory.Peek(pc+2), e.Memory.Peek(pc+3))
+}
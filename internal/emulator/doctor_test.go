@@ -0,0 +1,102 @@
+package emulator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// newTestROM creates a minimal 32 KiB ROM-only cartridge with a valid header.
+func newTestROM() []byte {
+	rom := make([]byte, 0x8000)
+	copy(rom[0x0134:], []byte("TEST"))
+
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+
+	return rom
+}
+
+func TestDoctorLogFormat(t *testing.T) {
+	rom := newTestROM()
+	rom[0x0100] = 0x00 // NOP
+	rom[0x0101] = 0xC3 // JP $0150
+	rom[0x0102] = 0x50
+	rom[0x0103] = 0x01
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	emu.SetDoctorLog(&buf)
+	emu.Step()
+
+	want := "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,C3,50,01\n"
+	if buf.String() != want {
+		t.Errorf("doctor log:\n got %q\nwant %q", buf.String(), want)
+	}
+}
+
+func TestDoctorLogBeforeInterrupt(t *testing.T) {
+	rom := newTestROM()
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Pending V-Blank interrupt with IME enabled
+	emu.CPU.IME = true
+	emu.Memory.Write(0xFFFF, 0x01)
+	emu.Memory.RequestInterrupt(0)
+
+	var buf bytes.Buffer
+	emu.SetDoctorLog(&buf)
+	emu.Step()
+
+	// The state is logged before the interrupt is serviced
+	want := "A:01 F:B0 B:00 C:13 D:00 E:D8 H:01 L:4D SP:FFFE PC:0100 PCMEM:00,00,00,00\n"
+	if buf.String() != want {
+		t.Errorf("doctor log:\n got %q\nwant %q", buf.String(), want)
+	}
+	if emu.CPU.Registers.PC != 0x0040 {
+		t.Errorf("PC after step = 0x%04X, want 0x0040", emu.CPU.Registers.PC)
+	}
+}
+
+func TestDoctorLogStubsLY(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0xF0, 0x44, // LDH A,($44)
+		0x00, // NOP
+	})
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	emu.SetDoctorLog(&buf)
+	emu.Step()
+	emu.Step()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "A:90 ") {
+		t.Errorf("doctor log = %q, want the second line to start with A:90", buf.String())
+	}
+	if ly := emu.PPU.LY(); ly == 0x90 {
+		t.Fatalf("PPU LY = 0x%02X, test needs the real LY to differ from the stub", ly)
+	}
+
+	// Disabling the log restores the real LY
+	emu.SetDoctorLog(nil)
+	if got, want := emu.Memory.Read(0xFF44), emu.PPU.LY(); got != want {
+		t.Errorf("LY after disabling the log = 0x%02X, want 0x%02X", got, want)
+	}
+}
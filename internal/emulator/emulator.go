@@ -6,12 +6,17 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/richardwooding/nostalgiza/internal/apu"
 	"github.com/richardwooding/nostalgiza/internal/cartridge"
 	"github.com/richardwooding/nostalgiza/internal/cpu"
 	"github.com/richardwooding/nostalgiza/internal/input"
+	"github.com/richardwooding/nostalgiza/internal/link"
 	"github.com/richardwooding/nostalgiza/internal/memory"
 	"github.com/richardwooding/nostalgiza/internal/ppu"
 	"github.com/richardwooding/nostalgiza/internal/timer"
@@ -30,17 +35,53 @@ const (
 
 	// stableOutputDuration is how long to wait with no new output before considering it stable.
 	stableOutputDuration = 3 * time.Second
+
+	// stableOutputCycles is stableOutputDuration in emulated cycles, used by
+	// RunUntilOutputCycles.
+	stableOutputCycles = 3 * ClockSpeed
 )
 
 var (
 	// ErrTimeout indicates the operation timed out.
 	ErrTimeout = errors.New("timeout waiting for serial output")
 
-	// Test ROM completion markers.
-	passedBytes = []byte("Passed")
-	failedBytes = []byte("Failed")
+	// ErrCGBOnly indicates the ROM only runs on a Game Boy Color.
+	ErrCGBOnly = errors.New("ROM requires a Game Boy Color")
+
+	// ErrCPUStuck indicates the CPU can never run again: it is halted with
+	// no interrupts enabled, or locked up after an undefined opcode.
+	ErrCPUStuck = errors.New("CPU is stuck")
+
+	// Default test ROM completion markers (Blargg).
+	defaultMarkers = []string{"Passed", "Failed"}
 )
 
+// RunOptions configures how RunUntilOutputWithOptions decides a test ROM has finished.
+type RunOptions struct {
+	// Markers are serial output substrings that signal completion.
+	Markers []string
+
+	// StableDuration is how long to wait with no new serial output before
+	// considering the output complete.
+	StableDuration time.Duration
+
+	// StableCycles is like StableDuration but in emulated cycles, used by
+	// RunUntilOutputCyclesWithOptions. Zero selects 3 seconds of emulated time.
+	StableCycles uint64
+
+	// StopOnHalt stops the run when the CPU is halted, even without serial output.
+	StopOnHalt bool
+}
+
+// DefaultRunOptions returns the run options used by RunUntilOutput. Each call
+// returns a fresh Markers slice, so callers may modify it.
+func DefaultRunOptions() RunOptions {
+	return RunOptions{
+		Markers:        slices.Clone(defaultMarkers),
+		StableDuration: stableOutputDuration,
+	}
+}
+
 // Emulator represents a Game Boy emulator instance.
 type Emulator struct {
 	CPU    *cpu.CPU
@@ -49,79 +90,170 @@ type Emulator struct {
 	Joypad *input.Joypad
 	Timer  *timer.Timer
 	APU    *apu.APU
-	Cart   cartridge.Cartridge // nolint:unused // Reserved for future save state/MBC features
+	Cart   cartridge.Cartridge
 
 	// Serial output buffer for test ROMs
 	serialOutput []byte
 
-	// Interrupt flags (0xFF0F)
-	interruptFlags uint8
+	// Bytes queued by FeedSerialInput for external-clock receives
+	serialInput []byte
+
+	// Gameboy Doctor log output (nil = disabled)
+	doctorLog io.Writer
+
+	// Result signalled by a Mooneye test ROM breakpoint
+	mooneye MooneyeResult
+
+	// How the CPU handles undefined opcodes (kept across Reset)
+	illegalOpcodeMode cpu.IllegalOpcodeMode
+
+	// apuDisabled skips APU updates (see SetAPUEnabled)
+	apuDisabled bool
+
+	// powerOnRAM fills RAM with the power-on pattern (kept across Reset)
+	powerOnRAM bool
+
+	// cgb reports whether CGB hardware is enabled (see Options.CGB)
+	cgb bool
+
+	// Link cable port (nil = not linked) and the cycle count at which the
+	// current lockstep quantum ends
+	link           *link.Port
+	lockstepCycles uint64
+}
+
+// Options configures optional emulator behavior.
+type Options struct {
+	// Cartridge configures cartridge loading.
+	Cartridge cartridge.Options
+
+	// CGB enables CGB-only hardware (currently the KEY1 speed switch).
+	CGB bool
+
+	// ForceDMG runs CGB-only ROMs in DMG mode instead of returning ErrCGBOnly.
+	// Such ROMs usually display a garbled screen or refuse to start.
+	ForceDMG bool
+
+	// IllegalOpcodeMode selects how the CPU handles undefined opcodes.
+	// The zero value locks up the CPU, as on hardware.
+	IllegalOpcodeMode cpu.IllegalOpcodeMode
+
+	// PowerOnRAM fills WRAM, VRAM and OAM with a deterministic non-zero
+	// pattern, like the garbage real hardware powers up with, instead of
+	// zeros. It is reapplied on Reset.
+	PowerOnRAM bool
 }
 
 // New creates a new emulator instance with the given ROM data.
 func New(romData []byte) (*Emulator, error) {
+	return NewWithOptions(romData, Options{})
+}
+
+// NewWithOptions creates a new emulator instance with the given ROM data and options.
+func NewWithOptions(romData []byte, opts Options) (*Emulator, error) {
 	// Load cartridge
-	cart, err := cartridge.New(romData)
+	cart, err := cartridge.NewWithOptions(romData, opts.Cartridge)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load cartridge: %w", err)
 	}
+	slog.Debug("loaded cartridge", "title", cart.Header().GetTitle(),
+		"type", cartridge.CartridgeType(cart.Header().CartridgeType), "size", len(romData))
+
+	// CGB-only ROMs need CGB hardware; CGB-enhanced ROMs run in DMG mode
+	if cart.Header().IsCGBOnly() && !opts.CGB && !opts.ForceDMG {
+		return nil, fmt.Errorf("%w (CGB flag 0x%02X)", ErrCGBOnly, cart.Header().CGBFlag)
+	}
 
 	// Create emulator instance
 	e := &Emulator{
-		Cart:         cart,
-		serialOutput: make([]byte, 0, initialSerialBufferCapacity),
+		Cart:              cart,
+		serialOutput:      make([]byte, 0, initialSerialBufferCapacity),
+		illegalOpcodeMode: opts.IllegalOpcodeMode,
+		powerOnRAM:        opts.PowerOnRAM,
+		cgb:               opts.CGB,
 	}
 
+	// Create memory bus and attach the cartridge
+	// The bus owns the interrupt flag register (0xFF0F), so all hardware
+	// interrupt requests are routed through it.
+	mem := memory.NewBus()
+	mem.SetCartridge(cart)
+	mem.SetCGBMode(opts.CGB)
+	e.Memory = mem
+
 	// Create PPU with interrupt callback
-	e.PPU = ppu.New(e.requestInterrupt)
+	e.PPU = ppu.New(mem.RequestInterrupt)
+	e.PPU.SetCGBMode(opts.CGB)
 
 	// Create joypad with interrupt callback
-	e.Joypad = input.New(e.requestInterrupt)
+	e.Joypad = input.New(func(uint8) {
+		mem.RequestInterrupt(cpu.InterruptJoypad)
+	})
 
 	// Create timer with interrupt callback
 	e.Timer = timer.New(func() {
-		e.requestInterrupt(cpu.InterruptTimer)
+		mem.RequestInterrupt(cpu.InterruptTimer)
 	})
 
-	// Create APU
+	// Create APU, with its frame sequencer clocked from the timer's DIV counter
 	e.APU = apu.New()
+	e.APU.SetDIVClocked(true)
+	e.Timer.SetFrameSequencerCallback(func() {
+		if !e.apuDisabled {
+			e.APU.ClockFrameSequencer()
+		}
+	})
 
-	// Create memory bus and load ROM
-	mem := memory.NewBus()
-	if err := mem.LoadROM(romData); err != nil {
-		return nil, fmt.Errorf("failed to load ROM into memory: %w", err)
-	}
 	mem.SetPPU(e.PPU)
 	mem.SetJoypad(e.Joypad)
 	mem.SetTimer(e.Timer)
 	mem.SetAPU(e.APU)
-	e.Memory = mem
+
+	// There is no boot ROM, so start from the state it leaves behind
+	mem.ApplyPostBootState()
+	if e.powerOnRAM {
+		e.fillPowerOnRAM()
+	}
 
 	// Create CPU
 	e.CPU = cpu.New(mem)
+	e.CPU.SetIllegalOpcodeMode(opts.IllegalOpcodeMode)
 
 	return e, nil
 }
 
-// requestInterrupt requests an interrupt.
-func (e *Emulator) requestInterrupt(interrupt uint8) {
-	e.interruptFlags |= (1 << interrupt)
-	// Write to memory (0xFF0F)
-	e.Memory.Write(0xFF0F, e.interruptFlags)
-}
-
 // Step executes one CPU instruction and returns the number of cycles taken.
 func (e *Emulator) Step() uint8 {
+	// Log state before the CPU services interrupts or executes the next instruction.
+	// A halted CPU executes no instructions, so nothing is logged while halted.
+	if e.doctorLog != nil && !e.CPU.Halted() {
+		e.logDoctorState()
+	}
+
 	cycles := e.CPU.Step()
 
-	// Advance PPU by the same number of cycles
-	e.PPU.Step(cycles)
+	// STOP performs a CGB speed switch if one was prepared via KEY1
+	if e.CPU.TakeStop() && e.Memory.SwitchSpeed() {
+		e.Timer.SetDoubleSpeed(e.Memory.DoubleSpeed())
+	}
 
-	// Advance timer by the same number of cycles
+	// In double-speed mode the CPU, timer and DMA run twice as fast,
+	// while the PPU and APU keep running at normal speed
+	dots := cycles
+	if e.Memory.DoubleSpeed() {
+		dots = cycles / 2
+	}
+
+	// Advance PPU by the elapsed dots
+	e.PPU.Step(dots)
+
+	// Advance timer by the same number of cycles as the CPU
 	e.Timer.Update(uint16(cycles))
 
-	// Advance APU by the same number of cycles
-	e.APU.Update(uint16(cycles))
+	// Advance APU by the elapsed dots
+	if !e.apuDisabled {
+		e.APU.Update(uint16(dots))
+	}
 
 	// Advance DMA transfer if active (DMA operates in M-cycles)
 	// Each CPU cycle is 4 clock cycles, so cycles/4 = M-cycles
@@ -142,10 +274,61 @@ func (e *Emulator) RunCycles(cycles uint64) {
 	e.handleSerialOutput()
 }
 
+// Cycles returns the total number of CPU cycles run since power-on.
+func (e *Emulator) Cycles() uint64 {
+	return e.CPU.Cycles
+}
+
+// RunFrame runs the emulator until the PPU completes a frame (enters V-Blank)
+// and returns the framebuffer. If the LCD is disabled no frame completes, so
+// it runs for one frame's worth of cycles instead and returns a blank
+// framebuffer (color 0), as the screen shows while the LCD is off.
+func (e *Emulator) RunFrame() *[ppu.ScreenWidth * ppu.ScreenHeight]uint8 {
+	// The CPU runs twice as many cycles per frame in double-speed mode
+	budget := uint64(CyclesPerFrame)
+	if e.Memory.DoubleSpeed() {
+		budget *= 2
+	}
+
+	startFrame := e.PPU.FrameCount()
+	targetCycles := e.CPU.Cycles + budget
+	for e.PPU.FrameCount() == startFrame && e.CPU.Cycles < targetCycles {
+		e.Step()
+	}
+	e.handleSerialOutput()
+
+	fb := e.PPU.GetFramebuffer()
+	if e.PPU.FrameCount() == startFrame && e.PPU.LCDC()&ppu.LCDCLCDEnable == 0 {
+		clear(fb[:])
+	}
+	return fb
+}
+
+// FrameHash returns a 32-bit FNV-1a hash of the current framebuffer. Equal
+// frames always hash equally, so rendering regression tests can compare
+// against a golden value instead of a full image.
+func (e *Emulator) FrameHash() uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write(e.PPU.GetFramebuffer()[:]) // hash.Hash writes never fail
+	return h.Sum32()
+}
+
 // RunUntilOutput runs the emulator until serial output appears or timeout is reached.
 // This is useful for test ROMs that output results via serial port.
+// It also stops when a Mooneye test ROM reaches its LD B,B breakpoint; the
+// outcome is then available from MooneyeResult.
+// If the CPU records an error (see cpu.IllegalOpcodeError) the run stops and
+// the error is returned along with the output so far.
 // Returns the serial output and any error.
 func (e *Emulator) RunUntilOutput(timeout time.Duration) (string, error) {
+	return e.RunUntilOutputWithOptions(timeout, DefaultRunOptions())
+}
+
+// RunUntilOutputWithOptions is like RunUntilOutput but with configurable
+// completion markers, stable-output duration and stop-on-HALT behavior.
+func (e *Emulator) RunUntilOutputWithOptions(timeout time.Duration, opts RunOptions) (string, error) {
+	markers := markerBytes(opts.Markers)
+
 	absoluteDeadline := time.Now().Add(timeout)
 	lastOutputLen := 0
 	lastOutputTime := time.Now()
@@ -163,48 +346,247 @@ func (e *Emulator) RunUntilOutput(timeout time.Duration) (string, error) {
 		// Execute some cycles
 		e.RunCycles(cyclesPerIteration)
 
-		// Check if we got new output - only convert to string when data changes
-		if len(e.serialOutput) > lastOutputLen {
+		// Check if we got new output
+		newOutput := len(e.serialOutput) > lastOutputLen
+		if newOutput {
 			lastOutputLen = len(e.serialOutput)
 			lastOutputTime = time.Now()
+		}
 
-			// Check if output is complete (only when new data arrives)
-			// Blargg's test ROMs output "Passed" or "Failed" when complete
-			// Use bytes.Contains to avoid string allocation (Issue #13)
-			if bytes.Contains(e.serialOutput, passedBytes) || bytes.Contains(e.serialOutput, failedBytes) {
-				return string(e.serialOutput), nil
-			}
+		if done, err := e.checkRunStop(markers, opts, newOutput); done {
+			return string(e.serialOutput), err
 		}
 
 		// Also check for stable output (no new data for a while)
 		// This handles ROMs that output continuously without completion markers
-		if len(e.serialOutput) > 0 && time.Since(lastOutputTime) > stableOutputDuration {
+		if len(e.serialOutput) > 0 && time.Since(lastOutputTime) > opts.StableDuration {
 			return string(e.serialOutput), nil
 		}
 	}
 }
 
+// RunUntilOutputCycles is like RunUntilOutput but measures time in emulated
+// cycles instead of wall-clock time, so runs are reproducible. It runs for at
+// most maxCycles and treats output as stable after stableOutputCycles without
+// new data.
+func (e *Emulator) RunUntilOutputCycles(maxCycles uint64) (string, error) {
+	return e.RunUntilOutputCyclesWithOptions(maxCycles, DefaultRunOptions())
+}
+
+// RunUntilOutputCyclesWithOptions is like RunUntilOutputCycles but with
+// configurable completion markers, stable-output cycles and stop-on-HALT
+// behavior. RunOptions.StableDuration is ignored.
+func (e *Emulator) RunUntilOutputCyclesWithOptions(maxCycles uint64, opts RunOptions) (string, error) {
+	markers := markerBytes(opts.Markers)
+	stableCycles := opts.StableCycles
+	if stableCycles == 0 {
+		stableCycles = stableOutputCycles
+	}
+
+	start := e.CPU.Cycles
+	lastOutputLen := 0
+	lastOutputCycle := start
+
+	for {
+		elapsed := e.CPU.Cycles - start
+		if elapsed >= maxCycles {
+			if len(e.serialOutput) > 0 {
+				return string(e.serialOutput), nil
+			}
+			return "", ErrTimeout
+		}
+
+		// Execute some cycles, without overshooting the budget
+		e.RunCycles(min(cyclesPerIteration, maxCycles-elapsed))
+
+		newOutput := len(e.serialOutput) > lastOutputLen
+		if newOutput {
+			lastOutputLen = len(e.serialOutput)
+			lastOutputCycle = e.CPU.Cycles
+		}
+
+		if done, err := e.checkRunStop(markers, opts, newOutput); done {
+			return string(e.serialOutput), err
+		}
+
+		if len(e.serialOutput) > 0 && e.CPU.Cycles-lastOutputCycle >= stableCycles {
+			return string(e.serialOutput), nil
+		}
+	}
+}
+
+// checkRunStop checks the completion conditions shared by the run-until-output
+// loops after a batch of cycles. newOutput reports whether serial output
+// arrived during the batch. It returns true if the run should stop, along
+// with any CPU error.
+func (e *Emulator) checkRunStop(markers [][]byte, opts RunOptions, newOutput bool) (bool, error) {
+	// Stop on CPU errors such as an illegal opcode in error mode
+	if err := e.CPU.LastError(); err != nil {
+		return true, fmt.Errorf("cpu: %w", err)
+	}
+
+	// Mooneye test ROMs signal completion with a LD B,B breakpoint
+	if e.CPU.TakeBreakpoint() {
+		e.mooneye = checkMooneyeRegisters(e.CPU.Registers)
+		return true, nil
+	}
+
+	// Check if output is complete (only when new data arrives)
+	// Blargg's test ROMs output "Passed" or "Failed" when complete
+	// Use bytes.Contains to avoid string allocation (Issue #13)
+	if newOutput {
+		for _, marker := range markers {
+			if bytes.Contains(e.serialOutput, marker) {
+				return true, nil
+			}
+		}
+	}
+
+	// Some test ROMs finish by halting without a completion marker
+	if opts.StopOnHalt && e.CPU.Halted() {
+		return true, nil
+	}
+
+	// A stuck CPU will produce no more output, so stop instead of waiting
+	// for the deadline. Output so far is the result; without any, the run
+	// failed.
+	if reason := e.stuckReason(); reason != "" {
+		if len(e.serialOutput) > 0 {
+			return true, nil
+		}
+		return true, fmt.Errorf("%w: %s at $%04X", ErrCPUStuck, reason, e.CPU.Registers.PC)
+	}
+	return false, nil
+}
+
+// stuckReason describes why the CPU can never run again, or returns "" if
+// it can. HALT ends only when an enabled interrupt is requested, whatever
+// IME is, so a CPU halted with IE clear stays halted.
+func (e *Emulator) stuckReason() string {
+	switch {
+	case e.CPU.LockedUp():
+		return "locked up after an undefined opcode"
+	case e.CPU.Halted() && e.Memory.Peek(0xFFFF)&0x1F == 0:
+		return "halted with no interrupts enabled"
+	default:
+		return ""
+	}
+}
+
+// markerBytes converts completion markers to byte slices for matching.
+func markerBytes(markers []string) [][]byte {
+	result := make([][]byte, len(markers))
+	for i, marker := range markers {
+		result[i] = []byte(marker)
+	}
+	return result
+}
+
 // handleSerialOutput checks for serial output and captures it.
 // Game Boy serial transfer uses:
 // - 0xFF01 (SB): Serial transfer data
 // - 0xFF02 (SC): Serial transfer control.
 func (e *Emulator) handleSerialOutput() {
 	// Read serial control register
-	sc := e.Memory.Read(0xFF02)
+	sc := e.Memory.Peek(0xFF02)
 
 	// Check if transfer is requested (bit 7 set)
 	if sc&0x80 != 0 {
 		// Read serial data
-		sb := e.Memory.Read(0xFF01)
+		sb := e.Memory.Peek(0xFF01)
 
 		// Append to output buffer (with size limit to prevent unbounded growth)
 		if len(e.serialOutput) < maxSerialBufferSize {
 			e.serialOutput = append(e.serialOutput, sb)
 		}
 
+		// An external-clock transfer receives the next queued input byte,
+		// as if a peer had clocked it in
+		received := sc&0x01 == 0 && len(e.serialInput) > 0
+		if received {
+			e.Memory.Poke(0xFF01, e.serialInput[0])
+			e.serialInput = e.serialInput[1:]
+		}
+
 		// Clear transfer flag
-		e.Memory.Write(0xFF02, sc&0x7F)
+		e.Memory.Poke(0xFF02, sc&0x7F)
+		if received {
+			e.Memory.RequestInterrupt(cpu.InterruptSerial)
+		}
+	}
+}
+
+// FeedSerialInput queues bytes to be received over the serial port. Each
+// transfer the ROM starts with the external clock (SC = 0x80) receives the
+// next byte into SB and requests the serial interrupt. Bytes the ROM never
+// receives stay queued until Reset; at most 64 KiB are queued and the rest
+// are dropped.
+func (e *Emulator) FeedSerialInput(b []byte) {
+	n := min(len(b), maxSerialBufferSize-len(e.serialInput))
+	e.serialInput = append(e.serialInput, b[:n]...)
+}
+
+// PendingSerialInput returns the number of queued serial input bytes not
+// yet received by the ROM.
+func (e *Emulator) PendingSerialInput() int {
+	return len(e.serialInput)
+}
+
+// ReadROMBank reads offset (0x0000-0x3FFF) of ROM bank bank directly from the
+// cartridge, without changing the banking state. It returns 0xFF for
+// out-of-range banks or if the cartridge does not support bank reads.
+func (e *Emulator) ReadROMBank(bank int, offset uint16) uint8 {
+	if br, ok := e.Cart.(cartridge.BankReader); ok {
+		return br.ReadROMBank(bank, offset)
 	}
+	return 0xFF
+}
+
+// ReadRAMBank reads offset (0x0000-0x1FFF) of cartridge RAM bank bank directly,
+// without changing the banking state or requiring RAM to be enabled. It
+// returns 0xFF for out-of-range banks or if the cartridge does not support
+// bank reads.
+func (e *Emulator) ReadRAMBank(bank int, offset uint16) uint8 {
+	if br, ok := e.Cart.(cartridge.BankReader); ok {
+		return br.ReadRAMBank(bank, offset)
+	}
+	return 0xFF
+}
+
+// SetAPUEnabled enables or disables APU updates. Disabling them saves time
+// when audio is not played: no samples are generated and channel timers and
+// the frame sequencer (length counters, envelopes and sweep) stop, but the
+// sound registers stay readable and writable.
+func (e *Emulator) SetAPUEnabled(enabled bool) {
+	e.apuDisabled = !enabled
+}
+
+// SetPC sets the program counter, to start execution somewhere other than
+// the cartridge entry point (0x0100), such as raw code blobs or boot ROMs.
+func (e *Emulator) SetPC(addr uint16) {
+	e.CPU.Registers.PC = addr
+}
+
+// PressButton presses a joypad button ("A", "B", "Start", "Select", "Up",
+// "Down", "Left" or "Right"), requesting a joypad interrupt if it was released.
+func (e *Emulator) PressButton(name string) {
+	e.Joypad.PressButton(name)
+}
+
+// ReleaseButton releases a joypad button.
+func (e *Emulator) ReleaseButton(name string) {
+	e.Joypad.ReleaseButton(name)
+}
+
+// Press presses a joypad button, requesting a joypad interrupt if it was
+// released.
+func (e *Emulator) Press(button input.Button) {
+	e.Joypad.Press(button)
+}
+
+// Release releases a joypad button.
+func (e *Emulator) Release(button input.Button) {
+	e.Joypad.Release(button)
 }
 
 // GetSerialOutput returns the accumulated serial output.
@@ -214,9 +596,22 @@ func (e *Emulator) GetSerialOutput() string {
 
 // Reset resets the emulator to initial state.
 func (e *Emulator) Reset() {
+	e.Cart.Reset()
 	e.Memory.Reset()
 	e.PPU.Reset()
+	e.Timer.Reset()
+	e.APU.Reset()
+	e.Joypad.Reset()
+	e.Memory.ApplyPostBootState()
+	if e.powerOnRAM {
+		e.fillPowerOnRAM()
+	}
+	prev := e.CPU
 	e.CPU = cpu.New(e.Memory)
+	e.CPU.SetIllegalOpcodeMode(e.illegalOpcodeMode)
+	e.CPU.SetTracer(prev.Tracer())
+	e.CPU.OnInterrupt = prev.OnInterrupt
 	e.serialOutput = make([]byte, 0, initialSerialBufferCapacity)
-	e.interruptFlags = 0
+	e.serialInput = nil
+	e.mooneye = MooneyeNone
 }
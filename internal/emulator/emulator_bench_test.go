@@ -0,0 +1,89 @@
+package emulator
+
+import (
+	"testing"
+	"time"
+)
+
+// benchmarkROM creates a tiny ROM that loops over common instructions
+// (loads, ALU, memory access and a relative jump).
+func benchmarkROM() []byte {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0x21, 0x00, 0xC0, // LD HL, $C000
+		0x3C,       // INC A
+		0x77,       // LD (HL), A
+		0x80,       // ADD A, B
+		0x2C,       // INC L
+		0x18, 0xFA, // JR -6
+	})
+	return rom
+}
+
+func BenchmarkEmulatorStep(b *testing.B) {
+	emu, err := New(benchmarkROM())
+	if err != nil {
+		b.Fatalf("New() error = %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		emu.Step()
+	}
+	b.StopTimer()
+
+	if emu.CPU.Cycles == 0 {
+		b.Fatal("no cycles executed")
+	}
+	b.ReportMetric(float64(emu.CPU.Cycles)/b.Elapsed().Seconds(), "cycles/s")
+}
+
+func BenchmarkEmulatorRunFrame(b *testing.B) {
+	emu, err := New(benchmarkROM())
+	if err != nil {
+		b.Fatalf("New() error = %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		emu.RunFrame()
+	}
+	b.StopTimer()
+
+	if got := emu.PPU.FrameCount(); got < uint64(b.N) {
+		b.Fatalf("FrameCount() = %d, want at least %d", got, b.N)
+	}
+}
+
+func TestRunFor(t *testing.T) {
+	emu, err := New(benchmarkROM())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	result := emu.RunFor(20 * time.Millisecond)
+	if result.Frames == 0 || result.Cycles == 0 {
+		t.Fatalf("RunFor() = %+v, want frames and cycles", result)
+	}
+	if result.Elapsed < 20*time.Millisecond {
+		t.Errorf("Elapsed = %v, want at least 20ms", result.Elapsed)
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	result := Throughput{Cycles: 2 * ClockSpeed, Frames: 120, Elapsed: time.Second}
+
+	if got := result.CyclesPerSecond(); got != 2*ClockSpeed {
+		t.Errorf("CyclesPerSecond() = %v, want %v", got, 2*ClockSpeed)
+	}
+	if got := result.FramesPerSecond(); got != 120 {
+		t.Errorf("FramesPerSecond() = %v, want 120", got)
+	}
+	if got := result.RealTimeRatio(); got != 2 {
+		t.Errorf("RealTimeRatio() = %v, want 2", got)
+	}
+
+	if got := (Throughput{}).RealTimeRatio(); got != 0 {
+		t.Errorf("RealTimeRatio() with no elapsed time = %v, want 0", got)
+	}
+}
@@ -0,0 +1,941 @@
+package emulator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/richardwooding/nostalgiza/internal/cpu"
+	"github.com/richardwooding/nostalgiza/internal/memory"
+	"github.com/richardwooding/nostalgiza/internal/ppu"
+	"github.com/richardwooding/nostalgiza/internal/timer"
+)
+
+// serialROM creates a ROM that writes msg to the serial port one byte at a
+// time, then finishes with tail (e.g. an infinite loop or HALT).
+func serialROM(msg string, tail ...byte) []byte {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0xC3, 0x50, 0x01}) // JP $0150
+
+	code := make([]byte, 0, len(msg)*14+len(tail))
+	for i := range len(msg) {
+		code = append(code,
+			0x3E, msg[i], // LD A, char
+			0xE0, 0x01, // LDH (SB), A
+			0x3E, 0x81, // LD A, $81
+			0xE0, 0x02, // LDH (SC), A
+			0xF0, 0x02, // LDH A, (SC)
+			0xCB, 0x7F, // BIT 7, A
+			0x20, 0xFA, // JR NZ, -6 (wait for transfer)
+		)
+	}
+	code = append(code, tail...)
+	copy(rom[0x0150:], code)
+	return rom
+}
+
+func TestDefaultRunOptionsCopy(t *testing.T) {
+	opts := DefaultRunOptions()
+	want := opts.Markers[0]
+	opts.Markers[0] = "changed"
+
+	if got := DefaultRunOptions().Markers[0]; got != want {
+		t.Errorf("DefaultRunOptions().Markers[0] = %q after modifying a copy, want %q", got, want)
+	}
+}
+
+func TestRunUntilOutputCustomMarker(t *testing.T) {
+	emu, err := New(serialROM("test DONE", 0x18, 0xFE)) // JR -2
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	opts := DefaultRunOptions()
+	opts.Markers = []string{"DONE"}
+	opts.StableDuration = time.Minute
+
+	start := time.Now()
+	output, err := emu.RunUntilOutputWithOptions(10*time.Second, opts)
+	if err != nil {
+		t.Fatalf("RunUntilOutputWithOptions() error = %v", err)
+	}
+	if output != "test DONE" {
+		t.Errorf("output = %q, want %q", output, "test DONE")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("run took %v, want early termination on marker", elapsed)
+	}
+}
+
+func TestRunUntilOutputStableDuration(t *testing.T) {
+	emu, err := New(serialROM("no marker", 0x18, 0xFE)) // JR -2
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	opts := DefaultRunOptions()
+	opts.StableDuration = 50 * time.Millisecond
+
+	start := time.Now()
+	output, err := emu.RunUntilOutputWithOptions(10*time.Second, opts)
+	if err != nil {
+		t.Fatalf("RunUntilOutputWithOptions() error = %v", err)
+	}
+	if output != "no marker" {
+		t.Errorf("output = %q, want %q", output, "no marker")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("run took %v, want early termination after stable output", elapsed)
+	}
+}
+
+func TestRunUntilOutputStopOnHalt(t *testing.T) {
+	// DI; HALT never wakes because no interrupts are enabled
+	rom := serialROM("", 0xF3, 0x76, 0x18, 0xFE)
+
+	tests := []struct {
+		name       string
+		stopOnHalt bool
+		wantErr    error
+	}{
+		{"Stop on halt", true, nil},
+		{"Stuck in halt", false, ErrCPUStuck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, err := New(rom)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			opts := DefaultRunOptions()
+			opts.StopOnHalt = tt.stopOnHalt
+
+			_, err = emu.RunUntilOutputWithOptions(200*time.Millisecond, opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RunUntilOutputWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunUntilOutputStuckCPU(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    []byte
+		output  string
+		wantErr error
+	}{
+		{"Illegal opcode", []byte{0xD3}, "", ErrCPUStuck},
+		{"Halt without interrupts", []byte{0xF3, 0x76, 0x18, 0xFE}, "", ErrCPUStuck},
+		{"Stuck after output", []byte{0xD3}, "partial", nil},
+		// V-Blank is enabled, so the HALT wakes up and the CPU is not stuck
+		{"Halt with interrupts", []byte{0x3E, 0x01, 0xE0, 0xFF, 0x76, 0x18, 0xFD}, "", ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, err := New(serialROM(tt.output, tt.code...))
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			start := time.Now()
+			output, err := emu.RunUntilOutput(300 * time.Millisecond)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RunUntilOutput() error = %v, want %v", err, tt.wantErr)
+			}
+			if output != tt.output {
+				t.Errorf("output = %q, want %q", output, tt.output)
+			}
+			if tt.wantErr != ErrTimeout && time.Since(start) > 100*time.Millisecond {
+				t.Errorf("run took %v, want an early return", time.Since(start))
+			}
+
+			// The cycle-based loop stops the same way
+			emu.Reset()
+			if _, err := emu.RunUntilOutputCycles(ClockSpeed / 4); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RunUntilOutputCycles() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunUntilOutputCycles(t *testing.T) {
+	const msg = "test Passed"
+	run := func() (string, uint64) {
+		t.Helper()
+		emu, err := New(serialROM(msg, 0x18, 0xFE)) // JR -2
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		output, err := emu.RunUntilOutputCycles(ClockSpeed)
+		if err != nil {
+			t.Fatalf("RunUntilOutputCycles() error = %v", err)
+		}
+		return output, emu.CPU.Cycles
+	}
+
+	// Serial transfers complete at the end of a batch, so the ROM sends one
+	// byte per batch and the marker's last byte goes out at the end of batch
+	// len(msg). The run must stop at exactly that boundary.
+	ref, err := New(serialROM(msg, 0x18, 0xFE))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for range len(msg) - 1 {
+		ref.RunCycles(cyclesPerIteration)
+	}
+	if got := ref.GetSerialOutput(); got != msg[:len(msg)-1] {
+		t.Fatalf("output after %d batches = %q, want %q", len(msg)-1, got, msg[:len(msg)-1])
+	}
+	ref.RunCycles(cyclesPerIteration)
+	if got := ref.GetSerialOutput(); got != msg {
+		t.Fatalf("output after %d batches = %q, want %q", len(msg), got, msg)
+	}
+	want := ref.CPU.Cycles
+
+	output, cycles := run()
+	if output != msg {
+		t.Errorf("output = %q, want %q", output, msg)
+	}
+	if cycles != want {
+		t.Errorf("stopped after %d cycles, want %d (end of batch %d)", cycles, want, len(msg))
+	}
+
+	// The run is deterministic
+	if _, again := run(); again != cycles {
+		t.Errorf("second run stopped after %d cycles, want %d", again, cycles)
+	}
+}
+
+func TestRunUntilOutputCyclesStable(t *testing.T) {
+	run := func(stableCycles uint64) uint64 {
+		t.Helper()
+		emu, err := New(serialROM("no marker", 0x18, 0xFE)) // JR -2
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		opts := DefaultRunOptions()
+		opts.StableCycles = stableCycles
+
+		output, err := emu.RunUntilOutputCyclesWithOptions(10*ClockSpeed, opts)
+		if err != nil {
+			t.Fatalf("RunUntilOutputCyclesWithOptions() error = %v", err)
+		}
+		if output != "no marker" {
+			t.Errorf("output = %q, want %q", output, "no marker")
+		}
+		return emu.CPU.Cycles
+	}
+
+	// The stable window is measured from the last output, in whole batches
+	short, long := run(100000), run(200000)
+	if diff := long - short; diff < 100000-cyclesPerIteration || diff > 100000+cyclesPerIteration {
+		t.Errorf("doubling the stable window added %d cycles, want about 100000", diff)
+	}
+	if long >= 10*ClockSpeed {
+		t.Errorf("stopped after %d cycles, want early termination after stable output", long)
+	}
+}
+
+func TestRunUntilOutputCyclesTimeout(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0x18, 0xFE}) // JR -2
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	const maxCycles = 25000
+	if _, err := emu.RunUntilOutputCycles(maxCycles); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("RunUntilOutputCycles() error = %v, want ErrTimeout", err)
+	}
+
+	// JR takes 12 cycles, so the budget is overshot by less than one instruction
+	if emu.CPU.Cycles < maxCycles || emu.CPU.Cycles >= maxCycles+12 {
+		t.Errorf("stopped after %d cycles, want %d", emu.CPU.Cycles, maxCycles)
+	}
+}
+
+// speedSwitchROM creates a ROM that prepares a speed switch, executes STOP
+// and then loops forever.
+func speedSwitchROM() []byte {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0x3E, 0x01, // LD A, $01
+		0xE0, 0x4D, // LDH (KEY1), A
+		0x10, 0x00, // STOP
+		0x18, 0xFE, // JR -2
+	})
+	return rom
+}
+
+// measureSpeed runs the emulator from the next scanline boundary for the given
+// number of CPU cycles and returns the scanlines and DIV increments that elapsed.
+func measureSpeed(t *testing.T, emu *Emulator, cycles uint64) (lines, div int) {
+	t.Helper()
+
+	// Align to the start of a scanline so the line count is exact
+	startLY := emu.Memory.Read(0xFF44)
+	for emu.Memory.Read(0xFF44) == startLY {
+		emu.Step()
+	}
+	startLY = emu.Memory.Read(0xFF44)
+
+	emu.Timer.Write(timer.DIV, 0)
+	target := emu.CPU.Cycles + cycles
+	for emu.CPU.Cycles < target {
+		emu.Step()
+	}
+
+	return int(emu.Memory.Read(0xFF44)) - int(startLY), int(emu.Timer.Read(timer.DIV))
+}
+
+func TestIllegalOpcodeMode(t *testing.T) {
+	// Print "OK", then execute the undefined opcode 0xD3
+	rom := serialROM("OK", 0xD3)
+
+	tests := []struct {
+		name    string
+		mode    cpu.IllegalOpcodeMode
+		wantErr error
+	}{
+		{"Lockup", cpu.IllegalOpcodeLockup, nil},
+		{"Error", cpu.IllegalOpcodeError, cpu.ErrIllegalOpcode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, err := NewWithOptions(rom, Options{IllegalOpcodeMode: tt.mode})
+			if err != nil {
+				t.Fatalf("NewWithOptions() error = %v", err)
+			}
+
+			opts := DefaultRunOptions()
+			opts.StableDuration = 50 * time.Millisecond
+
+			output, err := emu.RunUntilOutputWithOptions(5*time.Second, opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RunUntilOutputWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if output != "OK" {
+				t.Errorf("output = %q, want %q", output, "OK")
+			}
+			if !emu.CPU.LockedUp() {
+				t.Error("CPU.LockedUp() = false, want true")
+			}
+
+			// The mode survives a reset
+			emu.Reset()
+			emu.CPU.Registers.PC = 0x0150 + 14*2
+			emu.Step()
+			if (emu.CPU.LastError() != nil) != (tt.mode == cpu.IllegalOpcodeError) {
+				t.Errorf("after Reset, LastError() = %v in mode %d", emu.CPU.LastError(), tt.mode)
+			}
+		})
+	}
+}
+
+func TestCGBOnlyROM(t *testing.T) {
+	// cgbROM returns a test ROM with the given CGB flag and a valid header checksum
+	cgbROM := func(flag byte) []byte {
+		rom := newTestROM()
+		rom[0x0143] = flag
+		checksum := byte(0)
+		for addr := 0x0134; addr <= 0x014C; addr++ {
+			checksum = checksum - rom[addr] - 1
+		}
+		rom[0x014D] = checksum
+		return rom
+	}
+
+	tests := []struct {
+		name    string
+		flag    byte
+		opts    Options
+		wantErr error
+	}{
+		{"CGB-only", 0xC0, Options{}, ErrCGBOnly},
+		{"CGB-only forced to DMG", 0xC0, Options{ForceDMG: true}, nil},
+		{"CGB-only on CGB", 0xC0, Options{CGB: true}, nil},
+		{"CGB-enhanced", 0x80, Options{}, nil},
+		{"DMG", 0x00, Options{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, err := NewWithOptions(cgbROM(tt.flag), tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewWithOptions() error = %v, want %v", err, tt.wantErr)
+			}
+			if (emu != nil) != (tt.wantErr == nil) {
+				t.Errorf("NewWithOptions() emulator = %v, want non-nil only without error", emu)
+			}
+		})
+	}
+}
+
+func TestDoubleSpeed(t *testing.T) {
+	// 20 scanlines worth of CPU cycles at normal speed
+	const cycles = 20 * ppu.DotsPerScanline
+
+	tests := []struct {
+		name            string
+		cgb             bool
+		wantDoubleSpeed bool
+		wantLines       int
+	}{
+		{"DMG ignores KEY1", false, false, 20},
+		{"CGB double speed", true, true, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, err := NewWithOptions(speedSwitchROM(), Options{CGB: tt.cgb})
+			if err != nil {
+				t.Fatalf("NewWithOptions() error = %v", err)
+			}
+
+			// Execute LD, LDH and STOP
+			for range 3 {
+				emu.Step()
+			}
+			if got := emu.Memory.DoubleSpeed(); got != tt.wantDoubleSpeed {
+				t.Fatalf("DoubleSpeed() = %v, want %v", got, tt.wantDoubleSpeed)
+			}
+
+			lines, div := measureSpeed(t, emu, cycles)
+
+			// The PPU runs at normal speed, so it sees half the lines in double speed
+			if lines != tt.wantLines {
+				t.Errorf("Scanlines elapsed = %d, want %d", lines, tt.wantLines)
+			}
+
+			// The timer is clocked by the CPU, so DIV advances the same per CPU cycle
+			if want := cycles / 256; div != want {
+				t.Errorf("DIV = %d, want %d", div, want)
+			}
+		})
+	}
+}
+
+func TestRunFrame(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0x18, 0xFE}) // JR -2
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// The first frame ends at V-Blank entry, 144 scanlines after power-on
+	if fb := emu.RunFrame(); fb == nil {
+		t.Fatal("RunFrame() returned nil framebuffer")
+	}
+	if got := emu.PPU.FrameCount(); got != 1 {
+		t.Errorf("FrameCount() = %d, want 1", got)
+	}
+
+	// Later frames take a full frame's worth of dots
+	start := emu.CPU.Cycles
+	if fb := emu.RunFrame(); fb == nil {
+		t.Fatal("RunFrame() returned nil framebuffer")
+	}
+	elapsed := emu.CPU.Cycles - start
+	if elapsed < ppu.DotsPerFrame-12 || elapsed > ppu.DotsPerFrame+12 {
+		t.Errorf("RunFrame() advanced %d cycles, want ~%d", elapsed, ppu.DotsPerFrame)
+	}
+	if got := emu.PPU.FrameCount(); got != 2 {
+		t.Errorf("FrameCount() = %d, want 2", got)
+	}
+}
+
+func TestRunCyclesFrame(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0x18, 0xFE}) // JR -2
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Skip the short first frame so the next one is a full frame
+	emu.RunFrame()
+	startCycles := emu.Cycles()
+	startFrame := emu.PPU.FrameCount()
+
+	emu.RunCycles(CyclesPerFrame)
+
+	// RunCycles finishes the instruction that crosses the target
+	elapsed := emu.Cycles() - startCycles
+	if elapsed < CyclesPerFrame || elapsed > CyclesPerFrame+12 {
+		t.Errorf("Cycles() advanced %d, want ~%d", elapsed, CyclesPerFrame)
+	}
+	if got := emu.PPU.FrameCount() - startFrame; got != 1 {
+		t.Errorf("RunCycles(CyclesPerFrame) completed %d frames, want 1", got)
+	}
+	if emu.Cycles() != emu.CPU.Cycles {
+		t.Errorf("Cycles() = %d, want CPU.Cycles %d", emu.Cycles(), emu.CPU.Cycles)
+	}
+}
+
+func TestRunFrameLCDOff(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0xAF,       // XOR A
+		0xE0, 0x40, // LDH (LCDC), A
+		0x18, 0xFE, // JR -2
+	})
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Leave something on screen from before the LCD was switched off
+	emu.PPU.GetFramebuffer()[0] = 3
+
+	start := emu.CPU.Cycles
+	fb := emu.RunFrame()
+	if fb == nil {
+		t.Fatal("RunFrame() returned nil framebuffer")
+	}
+	for i, shade := range fb {
+		if shade != 0 {
+			t.Fatalf("framebuffer[%d] = %d, want blank (0)", i, shade)
+		}
+	}
+
+	// No frame completes, so the frame's cycle budget is used
+	if elapsed := emu.CPU.Cycles - start; elapsed < ppu.DotsPerFrame {
+		t.Errorf("RunFrame() advanced %d cycles, want at least %d", elapsed, ppu.DotsPerFrame)
+	}
+	if got := emu.PPU.FrameCount(); got != 0 {
+		t.Errorf("FrameCount() = %d, want 0", got)
+	}
+}
+
+func TestAccessStatsCountProgramOnly(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0xFB,       // EI
+		0x00,       // NOP
+		0x18, 0xFD, // JR -3
+	})
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	emu.Memory.SetAccessStats(true)
+
+	// The CPU polls IE and IF between instructions once IME is set, but the
+	// program itself never touches I/O
+	emu.RunFrame()
+
+	if !emu.CPU.IME {
+		t.Fatal("IME not set after EI")
+	}
+	stats := emu.Memory.AccessStats()
+	if got := stats[memory.RegionIO]; got.Reads != 0 || got.Writes != 0 {
+		t.Errorf("IO accesses = %+v, want none", got)
+	}
+	if got := stats[memory.RegionROM]; got.Reads == 0 {
+		t.Error("ROM reads = 0, want the program's opcode fetches")
+	}
+}
+
+func TestPowerUpState(t *testing.T) {
+	emu, err := New(newTestROM())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	check := func(t *testing.T) {
+		t.Helper()
+
+		r := emu.CPU.Registers
+		if r.AF() != 0x01B0 || r.BC() != 0x0013 || r.DE() != 0x00D8 || r.HL() != 0x014D {
+			t.Errorf("AF=%04X BC=%04X DE=%04X HL=%04X, want AF=01B0 BC=0013 DE=00D8 HL=014D",
+				r.AF(), r.BC(), r.DE(), r.HL())
+		}
+		if r.SP != 0xFFFE || r.PC != 0x0100 {
+			t.Errorf("SP=%04X PC=%04X, want SP=FFFE PC=0100", r.SP, r.PC)
+		}
+
+		ioTests := []struct {
+			name string
+			addr uint16
+			want uint8
+		}{
+			{"P1", 0xFF00, 0xCF},
+			{"SC", 0xFF02, 0x7E},
+			{"TAC", 0xFF07, 0xF8},
+			{"IF", 0xFF0F, 0xE1},
+			{"NR11", 0xFF11, 0xBF},
+			{"NR12", 0xFF12, 0xF3},
+			{"NR50", 0xFF24, 0x77},
+			{"NR51", 0xFF25, 0xF3},
+			{"NR52", 0xFF26, 0xF0},
+			{"LCDC", 0xFF40, 0x91},
+			{"SCY", 0xFF42, 0x00},
+			{"SCX", 0xFF43, 0x00},
+			{"LY", 0xFF44, 0x00},
+			{"LYC", 0xFF45, 0x00},
+			{"DMA", 0xFF46, 0xFF},
+			{"BGP", 0xFF47, 0xFC},
+			{"WY", 0xFF4A, 0x00},
+			{"WX", 0xFF4B, 0x00},
+			{"IE", 0xFFFF, 0x00},
+		}
+		for _, tt := range ioTests {
+			if got := emu.Memory.Read(tt.addr); got != tt.want {
+				t.Errorf("%s = 0x%02X, want 0x%02X", tt.name, got, tt.want)
+			}
+		}
+	}
+
+	t.Run("New", check)
+
+	emu.RunCycles(ppu.DotsPerFrame)
+	emu.Reset()
+	t.Run("Reset", check)
+}
+
+func TestResetCartridgeBanking(t *testing.T) {
+	// 64 KiB MBC1 ROM (4 banks), each bank starting with its number
+	rom := make([]byte, 0x10000)
+	copy(rom, newTestROM())
+	for bank := 1; bank < 4; bank++ {
+		rom[bank*0x4000] = byte(bank)
+	}
+	rom[0x0147] = 0x01 // MBC1
+	rom[0x0148] = 0x01 // 64 KiB
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	emu.Memory.Write(0x2000, 0x03)
+	if got := emu.Memory.Read(0x4000); got != 0x03 {
+		t.Fatalf("Setup failed: bank at 0x4000 = %d, want 3", got)
+	}
+
+	emu.Reset()
+	if got := emu.Memory.Read(0x4000); got != 0x01 {
+		t.Errorf("after Reset, bank at 0x4000 = %d, want 1", got)
+	}
+}
+
+func TestResetKeepsHooks(t *testing.T) {
+	rom := newTestROM()
+	rom[0x0040] = 0xD9 // V-Blank handler: RETI
+	copy(rom[0x0100:], []byte{
+		0x3E, 0x01, // LD A, $01
+		0xE0, 0xFF, // LDH (IE), A
+		0xFB,       // EI
+		0x18, 0xFE, // JR -2
+	})
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var trace bytes.Buffer
+	interrupts := 0
+	emu.CPU.SetTracer(&trace)
+	emu.CPU.OnInterrupt = func(uint8, uint16, uint16) { interrupts++ }
+
+	emu.Reset()
+	trace.Reset()
+
+	// IF is 0xE1 after boot, so V-Blank is serviced as soon as IME is set
+	emu.RunCycles(ppu.DotsPerFrame)
+	if interrupts == 0 {
+		t.Error("OnInterrupt not called after Reset")
+	}
+	if trace.Len() == 0 {
+		t.Error("no trace output after Reset")
+	}
+}
+
+func TestFeedSerialInput(t *testing.T) {
+	// Sends 0x99 on the external clock and loads the received byte into B
+	emu, err := New(newSerialROM(0x99, 0x80))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	emu.FeedSerialInput([]byte{0x5A, 0x6B})
+	emu.RunCycles(1000) // Starts the transfer
+	emu.RunCycles(1000) // Reads the received byte
+
+	if got := emu.Memory.Read(0xFF01); got != 0x5A {
+		t.Errorf("SB = 0x%02X, want 0x5A", got)
+	}
+	if got := emu.CPU.Registers.B; got != 0x5A {
+		t.Errorf("B = 0x%02X, want 0x5A", got)
+	}
+	if got := emu.Memory.Read(0xFF0F) & 0x08; got == 0 {
+		t.Error("serial interrupt not requested")
+	}
+	if got := emu.GetSerialOutput(); got != "\x99" {
+		t.Errorf("serial output = %q, want the sent byte 0x99", got)
+	}
+
+	// The ROM received one byte; the other stays queued until Reset
+	if got := emu.PendingSerialInput(); got != 1 {
+		t.Errorf("PendingSerialInput() = %d, want 1", got)
+	}
+	emu.Reset()
+	if got := emu.PendingSerialInput(); got != 0 {
+		t.Errorf("PendingSerialInput() after Reset = %d, want 0", got)
+	}
+}
+
+func TestFeedSerialInputInternalClock(t *testing.T) {
+	// Internal-clock transfers do not consume input
+	emu, err := New(newSerialROM(0x42, 0x81))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	emu.FeedSerialInput([]byte{0x5A})
+	emu.RunCycles(1000)
+	emu.RunCycles(1000)
+
+	if got := emu.PendingSerialInput(); got != 1 {
+		t.Errorf("PendingSerialInput() = %d, want 1", got)
+	}
+	if got := emu.Memory.Read(0xFF0F) & 0x08; got != 0 {
+		t.Error("serial interrupt requested without a receive")
+	}
+}
+
+func TestPressButton(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0x18, 0xFE}) // JR -2
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Select action buttons (P15 = 0)
+	emu.Memory.Write(0xFF00, 0x10)
+	emu.Memory.Write(0xFF0F, 0x00)
+
+	if got := emu.Memory.Read(0xFF00) & 0x0F; got != 0x0F {
+		t.Errorf("P1 before press = 0x%X, want 0xF", got)
+	}
+
+	emu.PressButton("A")
+	if got := emu.Memory.Read(0xFF00) & 0x0F; got != 0x0E {
+		t.Errorf("P1 with A pressed = 0x%X, want 0xE", got)
+	}
+	if got := emu.Memory.Read(0xFF0F); got&(1<<cpu.InterruptJoypad) == 0 {
+		t.Errorf("IF = 0x%02X, want joypad interrupt requested", got)
+	}
+
+	emu.ReleaseButton("A")
+	if got := emu.Memory.Read(0xFF00) & 0x0F; got != 0x0F {
+		t.Errorf("P1 after release = 0x%X, want 0xF", got)
+	}
+}
+
+func TestReadROMBank(t *testing.T) {
+	rom := make([]byte, 0x10000) // 64 KiB (4 banks)
+	copy(rom, newTestROM())
+	rom[0x0147] = 0x01 // MBC1
+	rom[0x0148] = 0x01 // 64 KiB
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+	rom[2*0x4000+0x0042] = 0x77
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := emu.ReadROMBank(2, 0x0042); got != 0x77 {
+		t.Errorf("ReadROMBank(2, 0x0042) = 0x%02X, want 0x77", got)
+	}
+	if got := emu.Memory.Read(0x4042); got != 0x00 {
+		t.Errorf("Read(0x4042) = 0x%02X, want 0x00 (bank 1 still active)", got)
+	}
+	if got := emu.ReadROMBank(4, 0); got != 0xFF {
+		t.Errorf("ReadROMBank(4, 0) = 0x%02X, want 0xFF", got)
+	}
+	if got := emu.ReadRAMBank(0, 0); got != 0xFF {
+		t.Errorf("ReadRAMBank(0, 0) without RAM = 0x%02X, want 0xFF", got)
+	}
+}
+
+func TestSetPC(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0x3E, 0x11}) // LD A,$11 at the entry point
+	copy(rom[0x0200:], []byte{0x3E, 0x42}) // LD A,$42
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	emu.SetPC(0x0200)
+	emu.Step()
+
+	if got := emu.CPU.Registers.A; got != 0x42 {
+		t.Errorf("A = 0x%02X, want 0x42 (instruction at 0x0200)", got)
+	}
+	if got := emu.CPU.Registers.PC; got != 0x0202 {
+		t.Errorf("PC = 0x%04X, want 0x0202", got)
+	}
+}
+
+func TestSetAPUEnabled(t *testing.T) {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{0x18, 0xFE}) // JR -2
+
+	emu, err := New(rom)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	emu.Memory.Write(0xFF26, 0x80) // NR52: sound on
+	emu.Memory.Write(0xFF16, 0x3E) // NR21: 2 length steps left
+	emu.Memory.Write(0xFF17, 0xF0) // NR22: volume 15, DAC on
+	emu.Memory.Write(0xFF19, 0xC0) // NR24: trigger with length enabled
+
+	emu.SetAPUEnabled(false)
+	emu.RunFrame()
+	if got := len(emu.APU.GetSampleBuffer()); got != 0 {
+		t.Errorf("samples with APU disabled = %d, want 0", got)
+	}
+	// The frame sequencer is stopped too, so the length counter does not expire
+	if got := emu.Memory.Read(0xFF26); got&0x02 == 0 {
+		t.Errorf("NR52 with APU disabled = $%02X, want channel 2 still active", got)
+	}
+	if emu.PPU.FrameCount() != 1 {
+		t.Errorf("FrameCount() = %d, want 1", emu.PPU.FrameCount())
+	}
+
+	emu.SetAPUEnabled(true)
+	emu.RunFrame()
+	if got := len(emu.APU.GetSampleBuffer()); got == 0 {
+		t.Error("samples with APU enabled = 0, want some")
+	}
+	if got := emu.Memory.Read(0xFF26); got&0x02 != 0 {
+		t.Errorf("NR52 with APU enabled = $%02X, want channel 2 expired", got)
+	}
+}
+
+func TestPowerOnRAM(t *testing.T) {
+	// snapshot returns the contents of VRAM, WRAM and OAM
+	snapshot := func(emu *Emulator) []byte {
+		emu.PPU.SetAccessBlocking(false)
+		defer emu.PPU.SetAccessBlocking(true)
+
+		var data []byte
+		for addr := uint16(0); addr < ppu.VRAMSize; addr++ {
+			data = append(data, emu.PPU.ReadVRAM(addr))
+		}
+		for addr := uint16(0xC000); addr < 0xE000; addr++ {
+			data = append(data, emu.Memory.Read(addr))
+		}
+		for addr := uint16(0); addr < ppu.OAMSize; addr++ {
+			data = append(data, emu.PPU.ReadOAM(addr))
+		}
+		return data
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		emu, err := New(newTestROM())
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		for i, b := range snapshot(emu) {
+			if b != 0 {
+				t.Fatalf("byte %d = 0x%02X, want 0x00", i, b)
+			}
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		emu, err := NewWithOptions(newTestROM(), Options{PowerOnRAM: true})
+		if err != nil {
+			t.Fatalf("NewWithOptions() error = %v", err)
+		}
+		first := snapshot(emu)
+
+		nonZero := 0
+		for _, b := range first {
+			if b != 0 {
+				nonZero++
+			}
+		}
+		if nonZero < len(first)*9/10 {
+			t.Errorf("%d of %d bytes non-zero, want at least 90%%", nonZero, len(first))
+		}
+
+		// The pattern is deterministic, and reapplied on reset
+		other, err := NewWithOptions(newTestROM(), Options{PowerOnRAM: true})
+		if err != nil {
+			t.Fatalf("NewWithOptions() error = %v", err)
+		}
+		if !bytes.Equal(snapshot(other), first) {
+			t.Error("power-on pattern differs between emulators")
+		}
+		emu.Memory.Write(0xC000, first[ppu.VRAMSize]+1)
+		emu.Reset()
+		if !bytes.Equal(snapshot(emu), first) {
+			t.Error("power-on pattern not reapplied on Reset")
+		}
+	})
+
+	t.Run("CGB banks", func(t *testing.T) {
+		emu, err := NewWithOptions(newTestROM(), Options{CGB: true, PowerOnRAM: true})
+		if err != nil {
+			t.Fatalf("NewWithOptions() error = %v", err)
+		}
+
+		// Bank 0 stays selected
+		if got := emu.Memory.Read(0xFF4F); got != 0xFE {
+			t.Errorf("VBK = 0x%02X, want 0xFE", got)
+		}
+		if got := emu.Memory.Read(0xFF70); got != 0xF8 {
+			t.Errorf("SVBK = 0x%02X, want 0xF8", got)
+		}
+
+		// The last VRAM and WRAM banks are filled too
+		emu.Memory.Write(0xFF4F, 1)
+		emu.Memory.Write(0xFF70, 7)
+		var banked []byte
+		banked = append(banked, snapshot(emu)[:ppu.VRAMSize]...)
+		for addr := uint16(0xD000); addr < 0xE000; addr++ {
+			banked = append(banked, emu.Memory.Read(addr))
+		}
+		nonZero := 0
+		for _, b := range banked {
+			if b != 0 {
+				nonZero++
+			}
+		}
+		if nonZero < len(banked)*9/10 {
+			t.Errorf("%d of %d bytes in VRAM bank 1 and WRAM bank 7 non-zero, want at least 90%%", nonZero, len(banked))
+		}
+	})
+}
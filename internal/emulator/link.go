@@ -0,0 +1,66 @@
+package emulator
+
+import (
+	"log/slog"
+
+	"github.com/richardwooding/nostalgiza/internal/cpu"
+	"github.com/richardwooding/nostalgiza/internal/link"
+)
+
+// LockstepQuantum is the number of CPU cycles a linked emulator runs between
+// sync points. It is the length of one byte transfer at the DMG's 8192 Hz
+// serial clock, so a transfer completes at the first sync point after it
+// starts.
+const LockstepQuantum = 4096
+
+// SetLink connects the serial port to one end of a link cable, or
+// disconnects it if port is nil. Linked emulators must be run with
+// RunLockstep.
+func (e *Emulator) SetLink(port *link.Port) {
+	e.link = port
+	e.lockstepCycles = e.CPU.Cycles
+}
+
+// RunLockstep runs the emulator for the given number of cycles in quanta of
+// LockstepQuantum, exchanging a sync token with the linked peer after each
+// quantum. Both emulators must run the same number of cycles, so they stay
+// aligned however fast each one runs. Serial transfers complete at sync
+// points. If the peer stalls past the link timeout, the cable degrades to
+// disconnected and transfers receive 0xFF.
+func (e *Emulator) RunLockstep(cycles uint64) {
+	for range (cycles + LockstepQuantum - 1) / LockstepQuantum {
+		// Quanta end at fixed cycle counts, so overshoot does not accumulate
+		e.lockstepCycles += LockstepQuantum
+		for e.CPU.Cycles < e.lockstepCycles {
+			e.Step()
+		}
+		e.syncLink()
+	}
+}
+
+// syncLink exchanges serial state with the linked peer and completes any
+// transfer clocked by either side.
+func (e *Emulator) syncLink() {
+	if e.link == nil {
+		return
+	}
+
+	sc := e.Memory.Peek(0xFF02)
+	tok := link.Token{
+		Master: sc&0x81 == 0x81,
+		Ready:  sc&0x80 != 0,
+		Data:   e.Memory.Peek(0xFF01),
+	}
+	peer, err := e.link.Exchange(tok)
+	if err != nil {
+		slog.Warn("link cable disconnected", "error", err)
+	}
+
+	// A transfer completes if this side drives the clock, or the peer drives
+	// it while this side waits on the external clock
+	if tok.Master || (tok.Ready && peer.Master) {
+		e.Memory.Poke(0xFF01, peer.Data)
+		e.Memory.Poke(0xFF02, sc&0x7F)
+		e.Memory.RequestInterrupt(cpu.InterruptSerial)
+	}
+}
@@ -0,0 +1,93 @@
+package emulator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richardwooding/nostalgiza/internal/link"
+)
+
+// newSerialROM returns a ROM that sends data with the given SC value, waits
+// for the transfer to finish and loads the received byte into B.
+func newSerialROM(data, sc uint8) []byte {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0x3E, data, // LD A,data
+		0xE0, 0x01, // LDH ($01),A
+		0x3E, sc, // LD A,sc
+		0xE0, 0x02, // LDH ($02),A
+		0xF0, 0x02, // loop: LDH A,($02)
+		0xCB, 0x7F, // BIT 7,A
+		0x20, 0xFA, // JR NZ,loop
+		0xF0, 0x01, // LDH A,($01)
+		0x47,       // LD B,A
+		0x18, 0xFE, // JR -2
+	})
+	return rom
+}
+
+func TestRunLockstep(t *testing.T) {
+	master, err := New(newSerialROM(0x42, 0x81))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	slave, err := New(newSerialROM(0x99, 0x80))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	a, b := link.Pipe(time.Second)
+	master.SetLink(a)
+	slave.SetLink(b)
+
+	const quanta = 10
+	var wg sync.WaitGroup
+	for _, e := range []*Emulator{master, slave} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.RunLockstep(quanta * LockstepQuantum)
+		}()
+	}
+	wg.Wait()
+
+	if got := master.CPU.Registers.B; got != 0x99 {
+		t.Errorf("master received 0x%02X, want 0x99", got)
+	}
+	if got := slave.CPU.Registers.B; got != 0x42 {
+		t.Errorf("slave received 0x%02X, want 0x42", got)
+	}
+	if a.Syncs() != quanta || b.Syncs() != quanta {
+		t.Errorf("syncs = %d/%d, want %d", a.Syncs(), b.Syncs(), quanta)
+	}
+
+	// Both sides stop at the same quantum boundary, give or take one instruction
+	for _, e := range []*Emulator{master, slave} {
+		if c := e.CPU.Cycles; c < quanta*LockstepQuantum || c >= quanta*LockstepQuantum+24 {
+			t.Errorf("cycles = %d, want just past %d", c, quanta*LockstepQuantum)
+		}
+	}
+	if got := master.Memory.Read(0xFF0F) & 0x08; got == 0 {
+		t.Error("master serial interrupt not requested")
+	}
+}
+
+func TestRunLockstepTimeout(t *testing.T) {
+	emu, err := New(newSerialROM(0x42, 0x81))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// The peer never runs, so the first sync times out
+	port, _ := link.Pipe(10 * time.Millisecond)
+	emu.SetLink(port)
+	emu.RunLockstep(2 * LockstepQuantum)
+
+	if port.Connected() {
+		t.Error("port still connected after timeout")
+	}
+	if got := emu.CPU.Registers.B; got != link.DisconnectedData {
+		t.Errorf("received 0x%02X, want 0x%02X", got, link.DisconnectedData)
+	}
+}
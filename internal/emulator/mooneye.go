@@ -0,0 +1,41 @@
+package emulator
+
+import "github.com/richardwooding/nostalgiza/internal/cpu"
+
+// MooneyeResult is the outcome signalled by a Mooneye test ROM.
+type MooneyeResult int
+
+// Mooneye test ROM outcomes.
+const (
+	MooneyeNone   MooneyeResult = iota // No result signalled yet
+	MooneyePassed                      // Fibonacci register pattern at the breakpoint
+	MooneyeFailed                      // Any other register pattern at the breakpoint
+)
+
+// String returns a human-readable name for the result.
+func (r MooneyeResult) String() string {
+	switch r {
+	case MooneyePassed:
+		return "PASSED"
+	case MooneyeFailed:
+		return "FAILED"
+	default:
+		return "NONE"
+	}
+}
+
+// checkMooneyeRegisters interprets the register state at a Mooneye breakpoint.
+// Mooneye test ROMs load the Fibonacci sequence B=3, C=5, D=8, E=13, H=21, L=34
+// and execute LD B,B to signal a pass. Failures load 0x42 into every register,
+// so any other pattern is reported as a failure.
+func checkMooneyeRegisters(r *cpu.Registers) MooneyeResult {
+	if r.B == 3 && r.C == 5 && r.D == 8 && r.E == 13 && r.H == 21 && r.L == 34 {
+		return MooneyePassed
+	}
+	return MooneyeFailed
+}
+
+// MooneyeResult returns the result signalled by a Mooneye test ROM, if any.
+func (e *Emulator) MooneyeResult() MooneyeResult {
+	return e.mooneye
+}
@@ -0,0 +1,71 @@
+package emulator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richardwooding/nostalgiza/internal/cpu"
+)
+
+func TestCheckMooneyeRegisters(t *testing.T) {
+	tests := []struct {
+		name string
+		regs cpu.Registers
+		want MooneyeResult
+	}{
+		{"Fibonacci", cpu.Registers{B: 3, C: 5, D: 8, E: 13, H: 21, L: 34}, MooneyePassed},
+		{"Failure pattern", cpu.Registers{B: 0x42, C: 0x42, D: 0x42, E: 0x42, H: 0x42, L: 0x42}, MooneyeFailed},
+		{"Partial match", cpu.Registers{B: 3, C: 5, D: 8, E: 13, H: 21, L: 0}, MooneyeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkMooneyeRegisters(&tt.regs); got != tt.want {
+				t.Errorf("checkMooneyeRegisters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mooneyeROM creates a ROM that loads the given registers and hits LD B,B.
+func mooneyeROM(b, c, d, e, h, l uint8) []byte {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0x06, b, // LD B, b
+		0x0E, c, // LD C, c
+		0x16, d, // LD D, d
+		0x1E, e, // LD E, e
+		0x26, h, // LD H, h
+		0x2E, l, // LD L, l
+		0x40,       // LD B, B (breakpoint)
+		0x18, 0xFE, // JR -2
+	})
+	return rom
+}
+
+func TestRunUntilOutputMooneye(t *testing.T) {
+	tests := []struct {
+		name string
+		rom  []byte
+		want MooneyeResult
+	}{
+		{"Pass", mooneyeROM(3, 5, 8, 13, 21, 34), MooneyePassed},
+		{"Fail", mooneyeROM(0x42, 0x42, 0x42, 0x42, 0x42, 0x42), MooneyeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emu, err := New(tt.rom)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if _, err := emu.RunUntilOutput(time.Second); err != nil {
+				t.Fatalf("RunUntilOutput() error = %v", err)
+			}
+			if got := emu.MooneyeResult(); got != tt.want {
+				t.Errorf("MooneyeResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package emulator
+
+import "github.com/richardwooding/nostalgiza/internal/ppu"
+
+// powerOnSeed seeds the power-on RAM pattern. A fixed seed keeps runs
+// reproducible.
+const powerOnSeed = 0x2D8F_4C1B
+
+// fillPowerOnRAM fills WRAM, VRAM and OAM with a deterministic pseudo-random
+// pattern. Without a boot ROM, DMG RAM powers up holding semi-random data
+// rather than zeros, and some intros and test ROMs read it before writing.
+// In CGB mode every VRAM and WRAM bank is filled, and VBK and SVBK are left
+// selecting bank 0.
+func (e *Emulator) fillPowerOnRAM() {
+	state := uint32(powerOnSeed)
+	next := func() uint8 {
+		// xorshift32
+		state ^= state << 13
+		state ^= state >> 17
+		state ^= state << 5
+		return uint8(state) //nolint:gosec // G115: Intentional truncation to a byte
+	}
+
+	// Write VRAM and OAM whatever mode the PPU is in
+	e.PPU.SetAccessBlocking(false)
+	defer e.PPU.SetAccessBlocking(true)
+
+	vramBanks, wramBanks := 1, 1
+	if e.cgb {
+		vramBanks, wramBanks = 2, 7
+	}
+
+	for bank := range vramBanks {
+		e.PPU.WriteVBK(uint8(bank)) //nolint:gosec // G115: bank is 0 or 1
+		for addr := uint16(0); addr < ppu.VRAMSize; addr++ {
+			e.PPU.WriteVRAM(addr, next())
+		}
+	}
+	e.PPU.WriteVBK(0)
+
+	// Bank 0 at C000-CFFF, then each switchable bank at D000-DFFF
+	for addr := uint16(0xC000); addr < 0xD000; addr++ {
+		e.Memory.Poke(addr, next())
+	}
+	for bank := 1; bank <= wramBanks; bank++ {
+		e.Memory.Poke(0xFF70, uint8(bank)) //nolint:gosec // G115: bank is 1-7
+		for addr := uint16(0xD000); addr < 0xE000; addr++ {
+			e.Memory.Poke(addr, next())
+		}
+	}
+	e.Memory.Poke(0xFF70, 0)
+	for addr := uint16(0); addr < ppu.OAMSize; addr++ {
+		e.PPU.WriteOAM(addr, next())
+	}
+}
@@ -0,0 +1,121 @@
+package emulator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrInvalidState indicates data that is not a save state of a supported version.
+	ErrInvalidState = errors.New("not a valid save state")
+
+	// ErrStateMismatch indicates a save state made with a different ROM.
+	ErrStateMismatch = errors.New("save state is for a different ROM")
+)
+
+// errTrailingData indicates bytes left over after every component loaded.
+var errTrailingData = errors.New("trailing data")
+
+// stateMagic identifies save state data.
+var stateMagic = [4]byte{'N', 'Z', 'S', 'T'}
+
+// stateVersion is bumped whenever the save state layout changes.
+const stateVersion = 1
+
+// stateHeader starts every save state and identifies the ROM it belongs to.
+type stateHeader struct {
+	Magic          [4]byte
+	Version        uint16
+	Title          [16]byte
+	HeaderChecksum uint8
+	GlobalChecksum [2]byte
+}
+
+// stateComponent is a part of the machine that saves its own state.
+type stateComponent interface {
+	SaveState(w io.Writer) error
+	LoadState(r io.Reader) error
+}
+
+// stateComponents returns the components in save state order.
+func (e *Emulator) stateComponents() []stateComponent {
+	return []stateComponent{e.CPU, e.Memory, e.PPU, e.APU, e.Timer, e.Joypad, e.Cart}
+}
+
+// stateHeader returns the save state header for the loaded ROM.
+func (e *Emulator) stateHeader() stateHeader {
+	h := e.Cart.Header()
+	return stateHeader{
+		Magic:          stateMagic,
+		Version:        stateVersion,
+		Title:          h.Title,
+		HeaderChecksum: h.HeaderChecksum,
+		GlobalChecksum: h.GlobalChecksum,
+	}
+}
+
+// SaveState serializes the machine state: CPU, memory, PPU, APU, timer,
+// joypad selection and cartridge RAM and banking. Configuration, serial
+// output and held buttons are not included.
+func (e *Emulator) SaveState() ([]byte, error) {
+	var buf bytes.Buffer
+	header := e.stateHeader()
+	if err := binary.Write(&buf, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to write save state header: %w", err)
+	}
+	for _, c := range e.stateComponents() {
+		if err := c.SaveState(&buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadState restores a state written by SaveState for the same ROM. If the
+// state cannot be loaded, the emulator is left as it was.
+func (e *Emulator) LoadState(data []byte) error {
+	r := bytes.NewReader(data)
+	var header stateHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+	if header.Magic != stateMagic || header.Version != stateVersion {
+		return fmt.Errorf("%w: unsupported format or version %d", ErrInvalidState, header.Version)
+	}
+	if want := e.stateHeader(); header != want {
+		return ErrStateMismatch
+	}
+
+	// Keep the current state to roll back to if the data is truncated or
+	// inconsistent partway through
+	backup, err := e.SaveState()
+	if err != nil {
+		return err
+	}
+	err = e.loadComponents(r)
+	if err == nil && r.Len() != 0 {
+		err = errTrailingData
+	}
+	if err != nil {
+		// Restoring a state this emulator just saved does not fail
+		_ = e.loadComponents(bytes.NewReader(backup[binary.Size(header):]))
+		return fmt.Errorf("%w: %w", ErrInvalidState, err)
+	}
+
+	e.serialInput = nil
+	e.mooneye = MooneyeNone
+	return nil
+}
+
+// loadComponents loads each component's state from r.
+func (e *Emulator) loadComponents(r io.Reader) error {
+	for _, c := range e.stateComponents() {
+		if err := c.LoadState(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package emulator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// newStateROM returns a ROM that keeps the timer, APU, PPU and Work RAM
+// busy: it starts channel 1 and the timer, then loops copying TIMA to SCX
+// and incrementing $C000.
+func newStateROM() []byte {
+	rom := newTestROM()
+	copy(rom[0x0100:], []byte{
+		0x3E, 0x80, // LD A,$80
+		0xE0, 0x26, // LDH ($26),A (APU on)
+		0x3E, 0xF3, // LD A,$F3
+		0xE0, 0x12, // LDH ($12),A (CH1 envelope)
+		0x3E, 0x87, // LD A,$87
+		0xE0, 0x14, // LDH ($14),A (CH1 trigger)
+		0x3E, 0x05, // LD A,$05
+		0xE0, 0x07, // LDH ($07),A (timer on, 262144 Hz)
+		0x21, 0x00, 0xC0, // LD HL,$C000
+		0xF0, 0x05, // loop: LDH A,($05)
+		0xE0, 0x43, // LDH ($43),A
+		0x34,       // INC (HL)
+		0x18, 0xF9, // JR loop
+	})
+	return rom
+}
+
+func TestSaveStateRoundTrip(t *testing.T) {
+	emu, err := New(newStateROM())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for range 5 {
+		emu.RunFrame()
+	}
+
+	state, err := emu.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	// Run ahead, then go back and run the same frames again
+	for range 10 {
+		emu.RunFrame()
+	}
+	want, err := emu.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	wantHash := emu.FrameHash()
+
+	if err := emu.LoadState(state); err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	for range 10 {
+		emu.RunFrame()
+	}
+	got, err := emu.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Error("state after replaying from a save state differs from the original run")
+	}
+	if got := emu.FrameHash(); got != wantHash {
+		t.Errorf("FrameHash() = 0x%08X, want 0x%08X", got, wantHash)
+	}
+	if emu.Memory.Read(0xC000) == 0 {
+		t.Error("$C000 = 0, want the ROM's loop counter")
+	}
+}
+
+func TestLoadStateErrors(t *testing.T) {
+	emu, err := New(newStateROM())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	emu.RunFrame()
+	state, err := emu.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	otherROM := newStateROM()
+	otherROM[0x014E] = 0x12 // Global checksum
+	other, err := New(otherROM)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	otherState, err := other.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrInvalidState},
+		{"not a state", bytes.Repeat([]byte{0xAA}, 64), ErrInvalidState},
+		{"truncated", state[:len(state)/2], ErrInvalidState},
+		{"trailing data", append(bytes.Clone(state), 0x00), ErrInvalidState},
+		{"different ROM", otherState, ErrStateMismatch},
+	}
+
+	emu.RunFrame()
+	before, err := emu.SaveState()
+	if err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := emu.LoadState(tt.data); !errors.Is(err, tt.want) {
+				t.Fatalf("LoadState() error = %v, want %v", err, tt.want)
+			}
+
+			// A failed load leaves the emulator untouched
+			after, err := emu.SaveState()
+			if err != nil {
+				t.Fatalf("SaveState() error = %v", err)
+			}
+			if !bytes.Equal(after, before) {
+				t.Error("failed LoadState() changed the emulator state")
+			}
+		})
+	}
+}
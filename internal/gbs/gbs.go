@@ -0,0 +1,157 @@
+// Package gbs loads and plays GBS (Game Boy Sound System) music rips.
+//
+// A GBS file is a 0x70-byte header followed by the music driver code, which
+// is mapped into the ROM address space at the header's load address. The
+// player calls the init routine once with the track number in A, then calls
+// the play routine at the header's timer rate.
+package gbs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+)
+
+// HeaderSize is the size of the GBS header; the code follows it.
+const HeaderSize = 0x70
+
+// Header field offsets.
+const (
+	offsetVersion      = 0x03
+	offsetSongs        = 0x04
+	offsetFirstSong    = 0x05
+	offsetLoadAddr     = 0x06
+	offsetInitAddr     = 0x08
+	offsetPlayAddr     = 0x0A
+	offsetStackPointer = 0x0C
+	offsetTimerModulo  = 0x0E
+	offsetTimerControl = 0x0F
+	offsetTitle        = 0x10
+	offsetAuthor       = 0x30
+	offsetCopyright    = 0x50
+	stringSize         = 0x20
+)
+
+// magic identifies a GBS file.
+var magic = []byte("GBS")
+
+var (
+	// ErrTooShort indicates the data is smaller than a GBS header.
+	ErrTooShort = errors.New("GBS file too short")
+
+	// ErrInvalidMagic indicates the data does not start with "GBS".
+	ErrInvalidMagic = errors.New("not a GBS file")
+
+	// ErrUnsupportedVersion indicates a GBS version other than 1.
+	ErrUnsupportedVersion = errors.New("unsupported GBS version")
+
+	// ErrInvalidAddress indicates a load, init or play address outside the
+	// ROM area the code can occupy (0x0400-0x7FFF).
+	ErrInvalidAddress = errors.New("invalid GBS address")
+
+	// ErrNoSongs indicates the header declares no songs.
+	ErrNoSongs = errors.New("GBS file has no songs")
+)
+
+// Header is a parsed GBS header.
+type Header struct {
+	Version   uint8
+	Songs     uint8 // Number of songs
+	FirstSong uint8 // First song to play, 1-based
+
+	LoadAddr     uint16 // Where the code is mapped
+	InitAddr     uint16 // Called once with the song number (0-based) in A
+	PlayAddr     uint16 // Called at the timer rate
+	StackPointer uint16
+
+	TimerModulo  uint8 // TMA value
+	TimerControl uint8 // TAC value; bit 2 clear selects the V-Blank rate
+
+	Title     string
+	Author    string
+	Copyright string
+}
+
+// ParseHeader parses the header at the start of a GBS file.
+func ParseHeader(data []byte) (*Header, error) {
+	if len(data) < HeaderSize {
+		return nil, fmt.Errorf("%w: got %d bytes", ErrTooShort, len(data))
+	}
+	if !bytes.Equal(data[:len(magic)], magic) {
+		return nil, ErrInvalidMagic
+	}
+
+	h := &Header{
+		Version:      data[offsetVersion],
+		Songs:        data[offsetSongs],
+		FirstSong:    data[offsetFirstSong],
+		LoadAddr:     binary.LittleEndian.Uint16(data[offsetLoadAddr:]),
+		InitAddr:     binary.LittleEndian.Uint16(data[offsetInitAddr:]),
+		PlayAddr:     binary.LittleEndian.Uint16(data[offsetPlayAddr:]),
+		StackPointer: binary.LittleEndian.Uint16(data[offsetStackPointer:]),
+		TimerModulo:  data[offsetTimerModulo],
+		TimerControl: data[offsetTimerControl],
+		Title:        headerString(data[offsetTitle : offsetTitle+stringSize]),
+		Author:       headerString(data[offsetAuthor : offsetAuthor+stringSize]),
+		Copyright:    headerString(data[offsetCopyright : offsetCopyright+stringSize]),
+	}
+
+	if h.Version != 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, h.Version)
+	}
+	if h.Songs == 0 {
+		return nil, ErrNoSongs
+	}
+	for _, addr := range []struct {
+		name  string
+		value uint16
+	}{{"load", h.LoadAddr}, {"init", h.InitAddr}, {"play", h.PlayAddr}} {
+		if addr.value < 0x0400 || addr.value >= 0x8000 {
+			return nil, fmt.Errorf("%w: %s address $%04X", ErrInvalidAddress, addr.name, addr.value)
+		}
+	}
+
+	return h, nil
+}
+
+// headerString decodes a zero-padded header string.
+func headerString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// PlayPeriod returns the number of CPU cycles between play routine calls:
+// one frame at the V-Blank rate, or one timer overflow if TAC enables the
+// timer. TAC bit 7 selects CGB double speed, which halves the period.
+func (h *Header) PlayPeriod() uint64 {
+	if h.TimerControl&0x04 == 0 {
+		return emulator.CyclesPerFrame
+	}
+
+	clocks := [4]uint64{1024, 16, 64, 256}[h.TimerControl&0x03]
+	period := clocks * (256 - uint64(h.TimerModulo))
+	if h.TimerControl&0x80 != 0 {
+		period /= 2
+	}
+	return period
+}
+
+// File is a loaded GBS file.
+type File struct {
+	Header *Header
+	Code   []byte
+}
+
+// Load parses a GBS file.
+func Load(data []byte) (*File, error) {
+	header, err := ParseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Header: header, Code: data[HeaderSize:]}, nil
+}
@@ -0,0 +1,259 @@
+package gbs
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// newTestGBS returns a GBS file whose init routine stores A at 0xC000 and
+// whose play routine increments 0xC001.
+func newTestGBS(songs uint8, timerModulo, timerControl uint8) []byte {
+	data := make([]byte, HeaderSize)
+	copy(data, "GBS")
+	data[offsetVersion] = 1
+	data[offsetSongs] = songs
+	data[offsetFirstSong] = 1
+	binary.LittleEndian.PutUint16(data[offsetLoadAddr:], 0x0400)
+	binary.LittleEndian.PutUint16(data[offsetInitAddr:], 0x0400)
+	binary.LittleEndian.PutUint16(data[offsetPlayAddr:], 0x0404)
+	binary.LittleEndian.PutUint16(data[offsetStackPointer:], 0xDFFF)
+	data[offsetTimerModulo] = timerModulo
+	data[offsetTimerControl] = timerControl
+	copy(data[offsetTitle:], "Test Tune")
+	copy(data[offsetAuthor:], "A. Composer")
+	copy(data[offsetCopyright:], "2026 Nobody")
+
+	return append(data,
+		0xEA, 0x00, 0xC0, // init: LD ($C000),A
+		0xC9,             // RET
+		0x21, 0x01, 0xC0, // play: LD HL,$C001
+		0x34, // INC (HL)
+		0xC9, // RET
+	)
+}
+
+func TestParseHeader(t *testing.T) {
+	h, err := ParseHeader(newTestGBS(3, 0xC0, 0x04))
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+
+	if h.Version != 1 || h.Songs != 3 || h.FirstSong != 1 {
+		t.Errorf("version/songs/first = %d/%d/%d, want 1/3/1", h.Version, h.Songs, h.FirstSong)
+	}
+	if h.LoadAddr != 0x0400 || h.InitAddr != 0x0400 || h.PlayAddr != 0x0404 {
+		t.Errorf("load/init/play = $%04X/$%04X/$%04X, want $0400/$0400/$0404", h.LoadAddr, h.InitAddr, h.PlayAddr)
+	}
+	if h.StackPointer != 0xDFFF {
+		t.Errorf("stack pointer = $%04X, want $DFFF", h.StackPointer)
+	}
+	if h.TimerModulo != 0xC0 || h.TimerControl != 0x04 {
+		t.Errorf("TMA/TAC = 0x%02X/0x%02X, want 0xC0/0x04", h.TimerModulo, h.TimerControl)
+	}
+	if h.Title != "Test Tune" || h.Author != "A. Composer" || h.Copyright != "2026 Nobody" {
+		t.Errorf("strings = %q/%q/%q", h.Title, h.Author, h.Copyright)
+	}
+}
+
+func TestParseHeaderFullStrings(t *testing.T) {
+	// Strings that fill all 32 bytes have no terminator
+	data := newTestGBS(1, 0, 0)
+	for i := range stringSize {
+		data[offsetTitle+i] = 'T'
+	}
+	h, err := ParseHeader(data)
+	if err != nil {
+		t.Fatalf("ParseHeader() error = %v", err)
+	}
+	if len(h.Title) != stringSize || h.Author != "A. Composer" {
+		t.Errorf("title length = %d, author = %q, want %d, \"A. Composer\"", len(h.Title), h.Author, stringSize)
+	}
+}
+
+func TestParseHeaderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func([]byte) []byte
+		wantErr error
+	}{
+		{"too short", func(d []byte) []byte { return d[:HeaderSize-1] }, ErrTooShort},
+		{"magic", func(d []byte) []byte { d[0] = 'X'; return d }, ErrInvalidMagic},
+		{"version", func(d []byte) []byte { d[offsetVersion] = 2; return d }, ErrUnsupportedVersion},
+		{"no songs", func(d []byte) []byte { d[offsetSongs] = 0; return d }, ErrNoSongs},
+		{"low load address", func(d []byte) []byte {
+			binary.LittleEndian.PutUint16(d[offsetLoadAddr:], 0x0200)
+			return d
+		}, ErrInvalidAddress},
+		{"play address in RAM", func(d []byte) []byte {
+			binary.LittleEndian.PutUint16(d[offsetPlayAddr:], 0xC000)
+			return d
+		}, ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseHeader(tt.modify(newTestGBS(1, 0, 0)))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseHeader() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlayPeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		tma, tac uint8
+		want     uint64
+	}{
+		{"V-Blank", 0x00, 0x00, 70224},
+		{"V-Blank ignores TMA", 0xC0, 0x03, 70224},
+		{"4096 Hz", 0x00, 0x04, 1024 * 256},
+		{"262144 Hz", 0xC0, 0x05, 16 * 64},
+		{"65536 Hz", 0xFF, 0x06, 64},
+		{"16384 Hz", 0x80, 0x07, 256 * 128},
+		{"Double speed", 0xC0, 0x85, 16 * 64 / 2},
+	}
+	for _, tt := range tests {
+		h := &Header{TimerModulo: tt.tma, TimerControl: tt.tac}
+		if got := h.PlayPeriod(); got != tt.want {
+			t.Errorf("%s: PlayPeriod() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	f, err := Load(newTestGBS(3, 0, 0))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	player, err := NewPlayer(f)
+	if err != nil {
+		t.Fatalf("NewPlayer() error = %v", err)
+	}
+
+	if err := player.Start(4); !errors.Is(err, ErrInvalidTrack) {
+		t.Errorf("Start(4) error = %v, want ErrInvalidTrack", err)
+	}
+	if err := player.Start(2); err != nil {
+		t.Fatalf("Start(2) error = %v", err)
+	}
+
+	emu := player.Emulator()
+	if got := emu.Memory.Read(0xC000); got != 1 {
+		t.Errorf("init received A = %d, want 1 (track 2, 0-based)", got)
+	}
+
+	// Play runs once per frame at the V-Blank rate
+	player.Run(10 * 70224)
+	if got := emu.Memory.Read(0xC001); got != 10 {
+		t.Errorf("play calls = %d, want 10", got)
+	}
+	if sp := emu.CPU.Registers.SP; sp != 0xDFFF {
+		t.Errorf("SP = $%04X, want $DFFF (balanced calls)", sp)
+	}
+}
+
+func TestPlayerRestart(t *testing.T) {
+	f, err := Load(newTestGBS(2, 0, 0))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	player, err := NewPlayer(f)
+	if err != nil {
+		t.Fatalf("NewPlayer() error = %v", err)
+	}
+	emu := player.Emulator()
+
+	// Play track 1 for a while and leave a note sounding on channel 2
+	if err := player.Start(1); err != nil {
+		t.Fatalf("Start(1) error = %v", err)
+	}
+	player.Run(10 * 70224)
+	emu.Memory.Write(0xFF17, 0xF0) // NR22: volume 15, DAC on
+	emu.Memory.Write(0xFF19, 0x80) // NR24: trigger
+	if got := emu.Memory.Read(0xFF26); got&0x02 == 0 {
+		t.Fatalf("NR52 = $%02X, want channel 2 active", got)
+	}
+
+	if err := player.Start(2); err != nil {
+		t.Fatalf("Start(2) error = %v", err)
+	}
+
+	// Track 2 starts from the same timer and APU state as track 1 did
+	if got := emu.Memory.Read(0xFF26); got != 0xF0 {
+		t.Errorf("NR52 = $%02X, want $F0 (no channels carried over)", got)
+	}
+	if got := emu.Memory.Read(0xFF04); got != 0 {
+		t.Errorf("DIV = $%02X, want $00 after restart", got)
+	}
+	player.Run(10 * 70224)
+	if got := emu.Memory.Read(0xC001); got != 10 {
+		t.Errorf("play calls = %d, want 10", got)
+	}
+}
+
+func TestPlayerInterrupts(t *testing.T) {
+	// An init routine that enables the V-Blank interrupt, as some drivers do
+	data := newTestGBS(1, 0, 0)[:HeaderSize]
+	binary.LittleEndian.PutUint16(data[offsetPlayAddr:], 0x0406)
+	data = append(data,
+		0x3E, 0x01, // init: LD A,$01
+		0xE0, 0xFF, // LDH (IE),A
+		0xFB, // EI
+		0xC9, // RET
+		0xC9, // play: RET
+	)
+	f, err := Load(data)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	player, err := NewPlayer(f)
+	if err != nil {
+		t.Fatalf("NewPlayer() error = %v", err)
+	}
+	if err := player.Start(1); err != nil {
+		t.Fatalf("Start(1) error = %v", err)
+	}
+	player.Run(10 * 70224)
+
+	// Each interrupt returns with RETI, leaving the stack balanced and IME on
+	emu := player.Emulator()
+	if sp := emu.CPU.Registers.SP; sp != 0xDFFF {
+		t.Errorf("SP = $%04X, want $DFFF (balanced interrupts)", sp)
+	}
+	if !emu.CPU.IME {
+		t.Error("IME = false, want true after the interrupts return")
+	}
+}
+
+func TestROM(t *testing.T) {
+	f, err := Load(newTestGBS(1, 0, 0))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	rom := f.ROM()
+
+	if len(rom) != 0x8000 {
+		t.Errorf("len(ROM()) = 0x%X, want 0x8000", len(rom))
+	}
+	if rom[0x0400] != 0xEA {
+		t.Errorf("code not mapped at the load address: 0x%02X", rom[0x0400])
+	}
+	// RST $38 jumps to load+$38
+	if rom[0x38] != 0xC3 || rom[0x39] != 0x38 || rom[0x3A] != 0x04 {
+		t.Errorf("RST $38 vector = % X, want C3 38 04", rom[0x38:0x3B])
+	}
+	for vector := 0x40; vector <= 0x60; vector += 8 {
+		if rom[vector] != 0xD9 {
+			t.Errorf("interrupt vector $%02X = 0x%02X, want RETI (0xD9)", vector, rom[vector])
+		}
+	}
+
+	// Code past 32 KiB grows the image to a power-of-two bank count
+	f.Code = make([]byte, 0x9000)
+	if got := len(f.ROM()); got != 0x10000 {
+		t.Errorf("len(ROM()) with 36 KiB of code = 0x%X, want 0x10000", got)
+	}
+}
@@ -0,0 +1,105 @@
+package gbs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/richardwooding/nostalgiza/internal/emulator"
+)
+
+// maxInitCycles bounds the init routine: one second of emulated time.
+const maxInitCycles = emulator.ClockSpeed
+
+var (
+	// ErrInvalidTrack indicates a track number outside 1 to the song count.
+	ErrInvalidTrack = errors.New("track out of range")
+
+	// ErrInitTimeout indicates the init routine did not return.
+	ErrInitTimeout = errors.New("GBS init routine did not return")
+)
+
+// Player plays a GBS file on an emulator, driving its APU.
+type Player struct {
+	emu    *emulator.Emulator
+	file   *File
+	period uint64 // Cycles between play calls
+
+	nextPlay uint64 // Cycle count at which play is next due
+	started  bool
+}
+
+// NewPlayer creates a player for f.
+func NewPlayer(f *File) (*Player, error) {
+	emu, err := emulator.NewWithOptions(f.ROM(), emulator.Options{ForceDMG: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create emulator: %w", err)
+	}
+	return &Player{emu: emu, file: f, period: f.Header.PlayPeriod()}, nil
+}
+
+// Emulator returns the emulator the music runs on; its APU produces the
+// audio samples.
+func (p *Player) Emulator() *emulator.Emulator {
+	return p.emu
+}
+
+// Start resets the emulator and runs the init routine for a track,
+// numbered from 1.
+func (p *Player) Start(track int) error {
+	if track < 1 || track > int(p.file.Header.Songs) {
+		return fmt.Errorf("%w: %d (1-%d)", ErrInvalidTrack, track, p.file.Header.Songs)
+	}
+
+	p.emu.Reset()
+	p.emu.Memory.Poke(0xFF06, p.file.Header.TimerModulo)
+	p.emu.Memory.Poke(0xFF07, p.file.Header.TimerControl)
+	p.emu.CPU.Registers.SP = p.file.Header.StackPointer
+	p.emu.CPU.Registers.A = uint8(track - 1) //nolint:gosec // G115: track is at most 255
+
+	p.call(p.file.Header.InitAddr)
+	limit := p.emu.CPU.Cycles + maxInitCycles
+	for !p.idle() {
+		if p.emu.CPU.Cycles >= limit {
+			return ErrInitTimeout
+		}
+		p.emu.Step()
+	}
+
+	p.nextPlay = p.emu.CPU.Cycles
+	p.started = true
+	return nil
+}
+
+// Run runs the emulator for the given number of cycles, calling the play
+// routine each time it is due. A call that overruns its period delays the
+// next one rather than queueing calls. Run does nothing before Start.
+func (p *Player) Run(cycles uint64) {
+	if !p.started {
+		return
+	}
+
+	target := p.emu.CPU.Cycles + cycles
+	for p.emu.CPU.Cycles < target {
+		if p.idle() && p.emu.CPU.Cycles >= p.nextPlay {
+			p.call(p.file.Header.PlayAddr)
+			for p.nextPlay <= p.emu.CPU.Cycles {
+				p.nextPlay += p.period
+			}
+		}
+		p.emu.Step()
+	}
+}
+
+// idle reports whether the CPU is in the idle loop between routine calls.
+func (p *Player) idle() bool {
+	return p.emu.CPU.Registers.PC == idleAddr
+}
+
+// call pushes the idle loop as the return address and jumps to addr.
+func (p *Player) call(addr uint16) {
+	r := p.emu.CPU.Registers
+	r.SP -= 2
+	p.emu.Memory.Poke(r.SP, idleAddr&0xFF)
+	p.emu.Memory.Poke(r.SP+1, idleAddr>>8)
+	r.PC = addr
+}
@@ -0,0 +1,54 @@
+package gbs
+
+// idleAddr is where routines return to. It holds a JR to itself, so the
+// player knows a routine has finished when the CPU reaches it.
+const idleAddr = 0x0150
+
+// ROM returns a cartridge image that maps the code at the load address,
+// with an MBC1 for the bank switching GBS drivers do by writing 0x2000.
+// The RST vectors jump to the matching offsets from the load address, as
+// the GBS format specifies. The player calls the play routine itself, so the
+// interrupt vectors just return with RETI, and the entry point idles.
+func (f *File) ROM() []byte {
+	const bankSize = 0x4000
+
+	end := int(f.Header.LoadAddr) + len(f.Code)
+	banks := 2
+	sizeCode := uint8(0)
+	for banks*bankSize < end {
+		banks *= 2
+		sizeCode++
+	}
+
+	rom := make([]byte, banks*bankSize)
+	copy(rom[f.Header.LoadAddr:], f.Code)
+
+	// RST vectors: JP load+vector
+	for vector := uint16(0); vector < 0x40; vector += 8 {
+		target := f.Header.LoadAddr + vector
+		rom[vector] = 0xC3
+		rom[vector+1] = uint8(target)      //nolint:gosec // G115: Intentional byte extraction from 16-bit value
+		rom[vector+2] = uint8(target >> 8) //nolint:gosec // G115: Intentional byte extraction from 16-bit value
+	}
+
+	// Interrupt vectors (V-Blank, STAT, timer, serial, joypad): RETI
+	for vector := 0x40; vector <= 0x60; vector += 8 {
+		rom[vector] = 0xD9
+	}
+
+	// Entry point: NOP; JP idle
+	copy(rom[0x0100:], []byte{0x00, 0xC3, idleAddr & 0xFF, idleAddr >> 8})
+	copy(rom[idleAddr:], []byte{0x18, 0xFE}) // JR -2
+
+	// Cartridge header
+	copy(rom[0x0134:], "GBS")
+	rom[0x0147] = 0x01 // MBC1
+	rom[0x0148] = sizeCode
+	checksum := byte(0)
+	for addr := 0x0134; addr <= 0x014C; addr++ {
+		checksum = checksum - rom[addr] - 1
+	}
+	rom[0x014D] = checksum
+
+	return rom
+}
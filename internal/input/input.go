@@ -1,6 +1,63 @@
 // Package input implements Game Boy joypad input handling.
 package input
 
+// Button identifies a joypad button.
+type Button uint8
+
+// Joypad buttons.
+const (
+	ButtonA Button = iota
+	ButtonB
+	ButtonSelect
+	ButtonStart
+	ButtonRight
+	ButtonLeft
+	ButtonUp
+	ButtonDown
+)
+
+// Buttons lists every joypad button.
+var Buttons = [...]Button{ButtonA, ButtonB, ButtonSelect, ButtonStart, ButtonRight, ButtonLeft, ButtonUp, ButtonDown}
+
+// buttonNames holds the names used by PressButton, indexed by Button.
+var buttonNames = [...]string{"A", "B", "Select", "Start", "Right", "Left", "Up", "Down"}
+
+// String returns the button's name, as accepted by ParseButton.
+func (b Button) String() string {
+	if int(b) < len(buttonNames) {
+		return buttonNames[b]
+	}
+	return "Unknown"
+}
+
+// ParseButton returns the button with the given name ("A", "B", "Start",
+// "Select", "Up", "Down", "Left" or "Right").
+func ParseButton(name string) (Button, bool) {
+	for i, buttonName := range buttonNames {
+		if buttonName == name {
+			return Button(i), true //nolint:gosec // G115: i indexes the 8 button names
+		}
+	}
+	return 0, false
+}
+
+// opposite returns the D-pad direction opposite b. It returns false for
+// action buttons.
+func (b Button) opposite() (Button, bool) {
+	switch b {
+	case ButtonUp:
+		return ButtonDown, true
+	case ButtonDown:
+		return ButtonUp, true
+	case ButtonLeft:
+		return ButtonRight, true
+	case ButtonRight:
+		return ButtonLeft, true
+	default:
+		return 0, false
+	}
+}
+
 // Joypad represents the Game Boy joypad state and P1/JOYP register.
 type Joypad struct {
 	// Selection bits (written by CPU)
@@ -42,6 +99,12 @@ func (j *Joypad) Read() uint8 {
 		result |= 0x10 // P14
 	}
 
+	return result | j.lines()
+}
+
+// lines returns the P1 input lines (bits 0-3) for the selected button groups.
+// A line is 0 while a selected button on it is pressed.
+func (j *Joypad) lines() uint8 {
 	// Initialize button bits as all released (1)
 	buttonBits := uint8(0x0F)
 
@@ -77,81 +140,96 @@ func (j *Joypad) Read() uint8 {
 		}
 	}
 
-	result |= buttonBits
-	return result
+	return buttonBits
+}
+
+// updateInterrupt requests the joypad interrupt if any input line went from
+// high to low since before was sampled.
+func (j *Joypad) updateInterrupt(before uint8) {
+	if before&^j.lines() != 0 && j.requestInterrupt != nil {
+		j.requestInterrupt(4)
+	}
 }
 
 // Write updates the P1/JOYP register (only bits 4-5 are writable).
+// Selecting a group with a button held pulls its line low and requests the
+// joypad interrupt.
 func (j *Joypad) Write(value uint8) {
+	before := j.lines()
 	j.selectAction = (value & 0x20) != 0
 	j.selectDirection = (value & 0x10) != 0
+	j.updateInterrupt(before)
 }
 
-// PressButton sets a button as pressed and requests joypad interrupt on state change.
-// Only triggers interrupt when button transitions from released to pressed.
-func (j *Joypad) PressButton(button string) {
-	// Check current state before update
-	wasPressed := false
+// Press sets a button as pressed. A direction is ignored while its opposite
+// is held, as the D-pad cannot press both. The joypad interrupt is requested
+// only if this pulls a P1 input line low, which requires the button's group
+// to be selected and the line not to be held low already.
+func (j *Joypad) Press(button Button) {
+	pressed := j.state(button)
+	if pressed == nil {
+		return
+	}
+	if opposite, ok := button.opposite(); ok && *j.state(opposite) {
+		return // Block opposite directions
+	}
 
-	switch button {
-	case "A":
-		wasPressed = j.buttonA
-		j.buttonA = true
-	case "B":
-		wasPressed = j.buttonB
-		j.buttonB = true
-	case "Start":
-		wasPressed = j.buttonStart
-		j.buttonStart = true
-	case "Select":
-		wasPressed = j.buttonSelect
-		j.buttonSelect = true
-	case "Up":
-		wasPressed = j.buttonUp
-		if !j.buttonDown { // Block opposite directions
-			j.buttonUp = true
-		}
-	case "Down":
-		wasPressed = j.buttonDown
-		if !j.buttonUp { // Block opposite directions
-			j.buttonDown = true
-		}
-	case "Left":
-		wasPressed = j.buttonLeft
-		if !j.buttonRight { // Block opposite directions
-			j.buttonLeft = true
-		}
-	case "Right":
-		wasPressed = j.buttonRight
-		if !j.buttonLeft { // Block opposite directions
-			j.buttonRight = true
-		}
+	before := j.lines()
+	*pressed = true
+	j.updateInterrupt(before)
+}
+
+// Release sets a button as released.
+func (j *Joypad) Release(button Button) {
+	if pressed := j.state(button); pressed != nil {
+		*pressed = false
 	}
+}
 
-	// Only request interrupt on state transition (released -> pressed)
-	if !wasPressed && j.requestInterrupt != nil {
-		j.requestInterrupt(4)
+// PressButton presses the button with the given name ("A", "B", "Start",
+// "Select", "Up", "Down", "Left" or "Right"). Unknown names are ignored.
+func (j *Joypad) PressButton(name string) {
+	if button, ok := ParseButton(name); ok {
+		j.Press(button)
+	}
+}
+
+// ReleaseButton releases the button with the given name.
+func (j *Joypad) ReleaseButton(name string) {
+	if button, ok := ParseButton(name); ok {
+		j.Release(button)
+	}
+}
+
+// Reset releases all buttons and deselects both button groups, as at power-on.
+func (j *Joypad) Reset() {
+	*j = Joypad{
+		selectAction:     true,
+		selectDirection:  true,
+		requestInterrupt: j.requestInterrupt,
 	}
 }
 
-// ReleaseButton sets a button as released.
-func (j *Joypad) ReleaseButton(button string) {
+// state returns the pressed flag of a button, or nil for an invalid button.
+func (j *Joypad) state(button Button) *bool {
 	switch button {
-	case "A":
-		j.buttonA = false
-	case "B":
-		j.buttonB = false
-	case "Start":
-		j.buttonStart = false
-	case "Select":
-		j.buttonSelect = false
-	case "Up":
-		j.buttonUp = false
-	case "Down":
-		j.buttonDown = false
-	case "Left":
-		j.buttonLeft = false
-	case "Right":
-		j.buttonRight = false
+	case ButtonA:
+		return &j.buttonA
+	case ButtonB:
+		return &j.buttonB
+	case ButtonSelect:
+		return &j.buttonSelect
+	case ButtonStart:
+		return &j.buttonStart
+	case ButtonRight:
+		return &j.buttonRight
+	case ButtonLeft:
+		return &j.buttonLeft
+	case ButtonUp:
+		return &j.buttonUp
+	case ButtonDown:
+		return &j.buttonDown
+	default:
+		return nil
 	}
 }
@@ -110,6 +110,54 @@ func TestJoypadRead_NoSelectionBits(t *testing.T) {
 	}
 }
 
+func TestJoypadRead_NeitherGroupSelected(t *testing.T) {
+	j := New(nil)
+
+	// Deselect both groups (P15 = P14 = 1)
+	j.Write(0xFF)
+
+	// Pressed buttons are invisible while neither group is selected
+	for _, button := range []string{"A", "B", "Start", "Select", "Up", "Left"} {
+		j.PressButton(button)
+	}
+
+	if result := j.Read(); result != 0xFF {
+		t.Errorf("Read() = 0x%02X, want 0xFF (low nibble 0x0F)", result)
+	}
+}
+
+func TestJoypadRead_BothGroupsSelected(t *testing.T) {
+	tests := []struct {
+		name     string
+		buttons  []string
+		expected uint8
+	}{
+		{"none", nil, 0xCF},
+		{"action only", []string{"Start"}, 0xC7},
+		{"direction only", []string{"Left"}, 0xCD},
+		{"same line", []string{"B", "Left"}, 0xCD},
+		{"different lines", []string{"Select", "Right"}, 0xCA},
+		{"all lines", []string{"A", "B", "Up", "Start"}, 0xC0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := New(nil)
+
+			// Select both groups (P15 = P14 = 0)
+			j.Write(0xCF)
+			for _, button := range tt.buttons {
+				j.PressButton(button)
+			}
+
+			// A line reads 0 if a button from either group on it is pressed
+			if result := j.Read(); result != tt.expected {
+				t.Errorf("Read() = 0x%02X, want 0x%02X", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestJoypadWrite_SelectionBits(t *testing.T) {
 	j := New(nil)
 
@@ -200,6 +248,7 @@ func TestJoypadInterrupt(t *testing.T) {
 		interruptCalled = true
 		interruptBit = bit
 	})
+	j.Write(0x10) // Select action buttons
 
 	// Press a button
 	j.PressButton("A")
@@ -220,6 +269,7 @@ func TestJoypadInterrupt_OnlyOnPress(t *testing.T) {
 	j := New(func(_ uint8) {
 		callCount++
 	})
+	j.Write(0x10) // Select action buttons
 
 	// First press should trigger interrupt
 	j.PressButton("A")
@@ -241,6 +291,54 @@ func TestJoypadInterrupt_OnlyOnPress(t *testing.T) {
 	}
 }
 
+func TestJoypadInterrupt_UnselectedGroup(t *testing.T) {
+	callCount := 0
+	j := New(func(_ uint8) {
+		callCount++
+	})
+	j.Write(0x10) // Select action buttons
+
+	// A direction press does not change the selected lines
+	j.PressButton("Up")
+	if callCount != 0 {
+		t.Errorf("Expected no interrupt for unselected direction button, got %d", callCount)
+	}
+
+	// Selecting directions pulls the held Up line low
+	j.Write(0x20)
+	if callCount != 1 {
+		t.Errorf("Expected 1 interrupt after selecting directions, got %d", callCount)
+	}
+
+	// Pressing a direction on another line fires
+	j.PressButton("Right")
+	if callCount != 2 {
+		t.Errorf("Expected 2 interrupts after pressing Right, got %d", callCount)
+	}
+
+	// Nothing selected: no lines can go low
+	j.Write(0x30)
+	j.PressButton("A")
+	if callCount != 2 {
+		t.Errorf("Expected no interrupt with no group selected, got %d", callCount)
+	}
+}
+
+func TestJoypadInterrupt_SharedLine(t *testing.T) {
+	callCount := 0
+	j := New(func(_ uint8) {
+		callCount++
+	})
+	j.Write(0x00) // Select both groups
+
+	// A and Right share line 0
+	j.PressButton("A")
+	j.PressButton("Right")
+	if callCount != 1 {
+		t.Errorf("Expected 1 interrupt for a line already low, got %d", callCount)
+	}
+}
+
 func TestReleaseButton(t *testing.T) {
 	j := New(nil)
 
@@ -293,6 +391,107 @@ func TestPressButton_AllButtons(t *testing.T) {
 	}
 }
 
+func TestPress_AllButtons(t *testing.T) {
+	j := New(nil)
+
+	tests := []struct {
+		button Button
+		check  func() bool
+	}{
+		{ButtonA, func() bool { return j.buttonA }},
+		{ButtonB, func() bool { return j.buttonB }},
+		{ButtonStart, func() bool { return j.buttonStart }},
+		{ButtonSelect, func() bool { return j.buttonSelect }},
+		{ButtonUp, func() bool { return j.buttonUp }},
+		{ButtonDown, func() bool { return j.buttonDown }},
+		{ButtonLeft, func() bool { return j.buttonLeft }},
+		{ButtonRight, func() bool { return j.buttonRight }},
+	}
+
+	for _, tt := range tests {
+		// Reset joypad
+		j = New(nil)
+
+		j.Press(tt.button)
+		if !tt.check() {
+			t.Errorf("Button %s was not pressed", tt.button)
+		}
+
+		j.Release(tt.button)
+		if tt.check() {
+			t.Errorf("Button %s was not released", tt.button)
+		}
+	}
+}
+
+func TestPress_OppositeDirectionBlocking(t *testing.T) {
+	pairs := []struct{ first, second Button }{
+		{ButtonDown, ButtonUp},
+		{ButtonUp, ButtonDown},
+		{ButtonLeft, ButtonRight},
+		{ButtonRight, ButtonLeft},
+	}
+
+	for _, tt := range pairs {
+		j := New(nil)
+		j.Press(tt.first)
+		j.Press(tt.second)
+		if !*j.state(tt.first) || *j.state(tt.second) {
+			t.Errorf("%s held: %s should be blocked", tt.first, tt.second)
+		}
+
+		j.Release(tt.first)
+		j.Press(tt.second)
+		if !*j.state(tt.second) {
+			t.Errorf("%s should be pressed after %s is released", tt.second, tt.first)
+		}
+	}
+}
+
+func TestPress_Interrupt(t *testing.T) {
+	requested := 0
+	j := New(func(uint8) { requested++ })
+	j.Write(0xDF) // Select action buttons
+
+	j.Press(ButtonA)
+	if requested != 1 {
+		t.Errorf("interrupts after Press(ButtonA) = %d, want 1", requested)
+	}
+
+	// Direction buttons are not selected, so their lines stay high
+	j.Press(ButtonRight)
+	if requested != 1 {
+		t.Errorf("interrupts after Press(ButtonRight) = %d, want 1", requested)
+	}
+}
+
+func TestPress_InvalidButton(t *testing.T) {
+	j := New(nil)
+	j.Write(0xCF) // Select both groups
+
+	j.Press(Button(0xFF))
+	j.Release(Button(0xFF))
+	if got := j.Read() & 0x0F; got != 0x0F {
+		t.Errorf("lines after invalid button = 0x%X, want 0xF", got)
+	}
+}
+
+func TestParseButton(t *testing.T) {
+	for _, button := range Buttons {
+		got, ok := ParseButton(button.String())
+		if !ok || got != button {
+			t.Errorf("ParseButton(%q) = %v, %v, want %v, true", button.String(), got, ok, button)
+		}
+	}
+
+	if _, ok := ParseButton("Turbo"); ok {
+		t.Error("ParseButton(\"Turbo\") ok = true, want false")
+	}
+	if got := Button(0xFF).String(); got != "Unknown" {
+		t.Errorf("Button(0xFF).String() = %q, want \"Unknown\"", got)
+	}
+}
+
 func TestJoypadRead_ButtonMapping(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,38 @@
+package input
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// joypadState is the joypad state written by SaveState.
+type joypadState struct {
+	SelectAction    bool
+	SelectDirection bool
+}
+
+// SaveState writes the P1 selection bits to w. Button states are not saved:
+// they follow the keys held when the state is loaded.
+func (j *Joypad) SaveState(w io.Writer) error {
+	s := joypadState{
+		SelectAction:    j.selectAction,
+		SelectDirection: j.selectDirection,
+	}
+	if err := binary.Write(w, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to write joypad state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState.
+func (j *Joypad) LoadState(r io.Reader) error {
+	var s joypadState
+	if err := binary.Read(r, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to read joypad state: %w", err)
+	}
+
+	j.selectAction = s.SelectAction
+	j.selectDirection = s.SelectDirection
+	return nil
+}
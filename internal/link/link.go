@@ -0,0 +1,89 @@
+// Package link provides a virtual link cable for connecting two emulators.
+package link
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTimeout indicates the peer did not reach a sync point in time. The port
+// is disconnected afterwards.
+var ErrTimeout = errors.New("link peer timed out")
+
+// DisconnectedData is the byte received over a disconnected link cable.
+const DisconnectedData = 0xFF
+
+// Token is exchanged by both ends of the cable at every lockstep sync point.
+type Token struct {
+	// Master is true if a transfer using the internal clock is pending
+	// (SC = 0x81): this side drives the clock.
+	Master bool
+
+	// Ready is true if a transfer is pending on either clock (SC bit 7).
+	Ready bool
+
+	// Data is the value of the serial data register (SB).
+	Data uint8
+}
+
+// disconnectedToken is what a disconnected port receives at each sync point.
+var disconnectedToken = Token{Data: DisconnectedData}
+
+// Port is one end of a link cable.
+type Port struct {
+	send    chan<- Token
+	recv    <-chan Token
+	timeout time.Duration
+
+	disconnected bool
+	syncs        uint64
+}
+
+// Pipe returns the two ends of an in-process link cable. Each Exchange waits
+// at most timeout for the peer before the port disconnects.
+func Pipe(timeout time.Duration) (*Port, *Port) {
+	aToB := make(chan Token, 1)
+	bToA := make(chan Token, 1)
+	a := &Port{send: aToB, recv: bToA, timeout: timeout}
+	b := &Port{send: bToA, recv: aToB, timeout: timeout}
+	return a, b
+}
+
+// Exchange sends tok to the peer and waits for the peer's token from the same
+// sync point. If the peer does not answer within the timeout, the port
+// disconnects and returns ErrTimeout; from then on every exchange returns a
+// token with DisconnectedData immediately.
+func (p *Port) Exchange(tok Token) (Token, error) {
+	if p.disconnected {
+		return disconnectedToken, nil
+	}
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case p.send <- tok:
+	case <-timer.C:
+		p.disconnected = true
+		return disconnectedToken, ErrTimeout
+	}
+
+	select {
+	case peer := <-p.recv:
+		p.syncs++
+		return peer, nil
+	case <-timer.C:
+		p.disconnected = true
+		return disconnectedToken, ErrTimeout
+	}
+}
+
+// Connected reports whether the port is still connected to its peer.
+func (p *Port) Connected() bool {
+	return !p.disconnected
+}
+
+// Syncs returns the number of sync points completed with the peer.
+func (p *Port) Syncs() uint64 {
+	return p.syncs
+}
@@ -0,0 +1,58 @@
+package link
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPipeExchange(t *testing.T) {
+	a, b := Pipe(time.Second)
+
+	done := make(chan Token)
+	go func() {
+		tok, err := b.Exchange(Token{Ready: true, Data: 0x99})
+		if err != nil {
+			t.Errorf("b.Exchange() error = %v", err)
+		}
+		done <- tok
+	}()
+
+	got, err := a.Exchange(Token{Master: true, Ready: true, Data: 0x42})
+	if err != nil {
+		t.Fatalf("a.Exchange() error = %v", err)
+	}
+	if want := (Token{Ready: true, Data: 0x99}); got != want {
+		t.Errorf("a received %+v, want %+v", got, want)
+	}
+	if want := (Token{Master: true, Ready: true, Data: 0x42}); <-done != want {
+		t.Errorf("b did not receive %+v", want)
+	}
+	if a.Syncs() != 1 || b.Syncs() != 1 {
+		t.Errorf("syncs = %d/%d, want 1/1", a.Syncs(), b.Syncs())
+	}
+}
+
+func TestPipeTimeout(t *testing.T) {
+	a, _ := Pipe(10 * time.Millisecond)
+
+	got, err := a.Exchange(Token{Master: true, Data: 0x42})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Exchange() error = %v, want ErrTimeout", err)
+	}
+	if got.Data != DisconnectedData || got.Master {
+		t.Errorf("received %+v, want disconnected token", got)
+	}
+	if a.Connected() {
+		t.Error("Connected() = true after timeout")
+	}
+
+	// Later exchanges return immediately without an error
+	got, err = a.Exchange(Token{})
+	if err != nil || got.Data != DisconnectedData {
+		t.Errorf("Exchange() after disconnect = %+v, %v", got, err)
+	}
+	if a.Syncs() != 0 {
+		t.Errorf("Syncs() = %d, want 0", a.Syncs())
+	}
+}
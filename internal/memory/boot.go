@@ -0,0 +1,33 @@
+package memory
+
+// postBootIO lists the I/O register writes that reproduce the state the DMG
+// boot ROM leaves behind, in the order they are applied. Registers whose
+// power-on value already matches are omitted.
+//
+// Channel 1 is configured but not triggered, so the boot sound is not
+// replayed and NR52 reads 0xF0 rather than 0xF1. DIV (0xAB after boot) is
+// left at 0, since moving the counter would shift timer and APU frame
+// sequencer timing.
+var postBootIO = []struct {
+	addr  uint16
+	value uint8
+}{
+	{0xFF00, 0x00}, // P1: both button groups selected (reads 0xCF)
+	{0xFF02, 0x7E}, // SC: no transfer, unused bits set
+	{0xFF0F, 0xE1}, // IF: V-Blank pending
+	{0xFF26, 0x80}, // NR52: APU on (first, so the writes below take effect)
+	{0xFF11, 0x80}, // NR11: 50% duty
+	{0xFF12, 0xF3}, // NR12: volume 15, decreasing, period 3
+	{0xFF24, 0x77}, // NR50: full volume on both sides
+	{0xFF25, 0xF3}, // NR51: channel panning
+	{0xFF46, 0xFF}, // DMA: last source written (no transfer)
+}
+
+// ApplyPostBootState sets the I/O registers to the values the DMG boot ROM
+// leaves them in. Call it after the components are attached, when starting
+// at the cartridge entry point without running a boot ROM.
+func (b *Bus) ApplyPostBootState() {
+	for _, reg := range postBootIO {
+		b.writeIO(reg.addr, reg.value)
+	}
+}
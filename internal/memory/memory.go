@@ -2,8 +2,10 @@
 package memory
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/richardwooding/nostalgiza/internal/apu"
 	"github.com/richardwooding/nostalgiza/internal/cartridge"
@@ -18,6 +20,8 @@ type PPU interface {
 	WriteOAM(addr uint16, value uint8)
 	ReadRegister(addr uint16) uint8
 	WriteRegister(addr uint16, value uint8)
+	ReadVBK() uint8
+	WriteVBK(value uint8)
 }
 
 // Joypad is an interface for joypad input handling.
@@ -43,8 +47,10 @@ type Bus struct {
 	// APU for audio registers
 	apu *apu.APU
 
-	// Work RAM (8 KiB)
-	wram [0x2000]uint8 // C000-DFFF: Work RAM
+	// Work RAM (8 banks of 4 KiB)
+	// Bank 0 is fixed at C000-CFFF; D000-DFFF maps the bank selected by SVBK.
+	// DMG only uses banks 0 and 1.
+	wram [8][0x1000]uint8 // C000-DFFF: Work RAM
 
 	// I/O Registers (128 bytes)
 	io [0x80]uint8 // FF00-FF7F: I/O Registers
@@ -52,13 +58,36 @@ type Bus struct {
 	// High RAM (127 bytes)
 	hram [0x7F]uint8 // FF80-FFFE: High RAM
 
+	// Interrupt Flag Register (1 byte)
+	// Only the low 5 bits are stored; the upper 3 bits always read as 1.
+	interruptFlag uint8 // FF0F: Interrupt Flag
+
 	// Interrupt Enable Register (1 byte)
 	ie uint8 // FFFF: Interrupt Enable
 
+	// CGB speed switch (KEY1, 0xFF4D); only present in CGB mode
+	cgbMode      bool
+	speedPrepare bool  // KEY1 bit 0: prepare speed switch
+	doubleSpeed  bool  // KEY1 bit 7: current speed
+	svbk         uint8 // SVBK (0xFF70) bits 0-2: WRAM bank select
+
 	// DMA state (Phase 3.5)
 	dmaActive bool   // DMA transfer in progress
 	dmaSource uint16 // DMA source address (XX00)
 	dmaCycles uint16 // Remaining DMA cycles (160 total)
+
+	// lyStub makes LY read as StubbedLY (see SetLYStub)
+	lyStub bool
+
+	// Access counts per region for profiling (nil = disabled, see SetAccessStats)
+	stats *accessStats
+
+	// OnDMAStart, if set, is called when an OAM DMA transfer starts, with
+	// its source address.
+	OnDMAStart func(source uint16)
+
+	// OnDMAComplete, if set, is called when an OAM DMA transfer finishes.
+	OnDMAComplete func()
 }
 
 // NewBus creates a new memory bus.
@@ -91,8 +120,23 @@ func (b *Bus) SetAPU(a *apu.APU) {
 	b.apu = a
 }
 
-// Read reads a byte from the memory bus.
+// Read reads a byte from the memory bus as a CPU access.
 func (b *Bus) Read(addr uint16) uint8 {
+	if b.stats != nil {
+		b.stats[regionIndex(addr)].Reads++
+	}
+	return b.read(addr)
+}
+
+// Peek reads a byte like Read but is not counted as a CPU access. It is for
+// reads the program did not make: the CPU's interrupt checks, and tracing
+// and debugging tools.
+func (b *Bus) Peek(addr uint16) uint8 {
+	return b.read(addr)
+}
+
+// read reads a byte from the memory bus.
+func (b *Bus) read(addr uint16) uint8 {
 	// During DMA transfer, only HRAM (0xFF80-0xFFFE) is accessible to CPU
 	// All other reads return 0xFF (including OAM)
 	if b.dmaActive && (addr < 0xFF80 || addr == 0xFFFF) {
@@ -122,17 +166,13 @@ func (b *Bus) Read(addr uint16) uint8 {
 		}
 		return 0xFF
 
-	// Work RAM Bank 0 (C000-CFFF)
-	case addr < 0xD000:
-		return b.wram[addr-0xC000]
-
-	// Work RAM Bank 1 (D000-DFFF)
+	// Work RAM (C000-DFFF)
 	case addr < 0xE000:
-		return b.wram[addr-0xC000]
+		return b.readWRAM(addr - 0xC000)
 
 	// Echo RAM (E000-FDFF) - Mirror of C000-DDFF
 	case addr < 0xFE00:
-		return b.wram[addr-0xE000]
+		return b.readWRAM(addr - 0xE000)
 
 	// OAM (FE00-FE9F)
 	case addr < 0xFEA0:
@@ -162,8 +202,23 @@ func (b *Bus) Read(addr uint16) uint8 {
 	}
 }
 
-// Write writes a byte to the memory bus.
+// Write writes a byte to the memory bus as a CPU access.
 func (b *Bus) Write(addr uint16, value uint8) {
+	if b.stats != nil {
+		b.stats[regionIndex(addr)].Writes++
+	}
+	b.write(addr, value)
+}
+
+// Poke writes a byte like Write but is not counted as a CPU access. It is
+// for writes the program did not make, such as the CPU acknowledging an
+// interrupt or the emulator completing a serial transfer.
+func (b *Bus) Poke(addr uint16, value uint8) {
+	b.write(addr, value)
+}
+
+// write writes a byte to the memory bus.
+func (b *Bus) write(addr uint16, value uint8) {
 	switch {
 	// ROM Bank 00 & 01 (0000-7FFF) - MBC control
 	// Handled by cartridge
@@ -184,17 +239,13 @@ func (b *Bus) Write(addr uint16, value uint8) {
 			b.cartridge.Write(addr, value)
 		}
 
-	// Work RAM Bank 0 (C000-CFFF)
-	case addr < 0xD000:
-		b.wram[addr-0xC000] = value
-
-	// Work RAM Bank 1 (D000-DFFF)
+	// Work RAM (C000-DFFF)
 	case addr < 0xE000:
-		b.wram[addr-0xC000] = value
+		b.writeWRAM(addr-0xC000, value)
 
 	// Echo RAM (E000-FDFF) - Mirror of C000-DDFF
 	case addr < 0xFE00:
-		b.wram[addr-0xE000] = value
+		b.writeWRAM(addr-0xE000, value)
 
 	// OAM (FE00-FE9F)
 	case addr < 0xFEA0:
@@ -220,6 +271,32 @@ func (b *Bus) Write(addr uint16, value uint8) {
 	}
 }
 
+// ioReadMask holds, for each I/O address handled by the generic io[] storage,
+// the bits that always read as 1. Unmapped addresses read as 0xFF.
+var ioReadMask = func() [0x80]uint8 {
+	var mask [0x80]uint8
+	mask[0x02] = 0x7E // SC: bits 1-6 are unused on DMG
+	for _, offset := range []int{0x03, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x15, 0x1F} {
+		mask[offset] = 0xFF
+	}
+	for offset := 0x27; offset <= 0x2F; offset++ {
+		mask[offset] = 0xFF
+	}
+	for offset := 0x4C; offset <= 0x7F; offset++ {
+		mask[offset] = 0xFF
+	}
+	return mask
+}()
+
+// isCGBIO reports whether addr is a CGB-only register kept in the generic io[]
+// storage (HDMA, infrared, palettes, object priority and the undocumented
+// 0xFF72-0xFF75). They read as 0xFF in DMG mode.
+func isCGBIO(addr uint16) bool {
+	return (addr >= 0xFF51 && addr <= 0xFF56) ||
+		(addr >= 0xFF68 && addr <= 0xFF6C) ||
+		(addr >= 0xFF72 && addr <= 0xFF75)
+}
+
 // readIO reads from I/O registers.
 func (b *Bus) readIO(addr uint16) uint8 {
 	// TODO: Implement proper I/O register handlers in later phases
@@ -240,7 +317,7 @@ func (b *Bus) readIO(addr uint16) uint8 {
 		}
 		return b.io[offset]
 	case 0xFF0F: // IF - Interrupt flags
-		return b.io[offset]
+		return b.readIF()
 	case 0xFF10, 0xFF11, 0xFF12, 0xFF13, 0xFF14, // Audio channel 1
 		0xFF16, 0xFF17, 0xFF18, 0xFF19, // Audio channel 2
 		0xFF1A, 0xFF1B, 0xFF1C, 0xFF1D, 0xFF1E, // Audio channel 3
@@ -258,14 +335,29 @@ func (b *Bus) readIO(addr uint16) uint8 {
 		return b.io[offset-0x30]
 	case 0xFF40, 0xFF41, 0xFF42, 0xFF43, 0xFF44, 0xFF45, 0xFF47, 0xFF48, 0xFF49, 0xFF4A, 0xFF4B:
 		// PPU registers (0xFF40-0xFF4B except 0xFF46)
+		if addr == 0xFF44 && b.lyStub {
+			return StubbedLY
+		}
 		if b.ppu != nil {
 			return b.ppu.ReadRegister(addr)
 		}
 		return 0xFF
 	case 0xFF46: // DMA - DMA transfer
 		return b.io[offset]
+	case 0xFF4D: // KEY1 - CGB speed switch
+		return b.readKEY1()
+	case 0xFF4F: // VBK - CGB VRAM bank
+		if b.ppu != nil {
+			return b.ppu.ReadVBK()
+		}
+		return 0xFF
+	case 0xFF70: // SVBK - CGB WRAM bank
+		return b.readSVBK()
 	default:
-		return b.io[offset]
+		if b.cgbMode && isCGBIO(addr) {
+			return b.io[offset]
+		}
+		return b.io[offset] | ioReadMask[offset]
 	}
 }
 
@@ -293,6 +385,8 @@ func (b *Bus) writeIO(addr uint16, value uint8) {
 				b.io[offset] = value
 			}
 		}
+	case 0xFF0F: // IF - Interrupt flags
+		b.writeIF(value)
 	case 0xFF10, 0xFF11, 0xFF12, 0xFF13, 0xFF14, // Audio channel 1
 		0xFF16, 0xFF17, 0xFF18, 0xFF19, // Audio channel 2
 		0xFF1A, 0xFF1B, 0xFF1C, 0xFF1D, 0xFF1E, // Audio channel 3
@@ -323,13 +417,145 @@ func (b *Bus) writeIO(addr uint16, value uint8) {
 			b.dmaActive = true
 			b.dmaSource = uint16(value) << 8 // Source address is XX00
 			b.dmaCycles = 160                // DMA takes 160 M-cycles
+
+			// Checked first to keep the hot path free of logging overhead
+			if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+				slog.Debug("DMA started", "source", fmt.Sprintf("0x%04X", b.dmaSource))
+			}
+			if b.OnDMAStart != nil {
+				b.OnDMAStart(b.dmaSource)
+			}
 		}
 		b.io[offset] = value
+	case 0xFF4D: // KEY1 - CGB speed switch
+		b.writeKEY1(value)
+	case 0xFF4F: // VBK - CGB VRAM bank
+		if b.ppu != nil {
+			b.ppu.WriteVBK(value)
+		}
+	case 0xFF70: // SVBK - CGB WRAM bank
+		b.writeSVBK(value)
 	default:
 		b.io[offset] = value
 	}
 }
 
+// StubbedLY is the value LY reads as while SetLYStub is enabled.
+const StubbedLY = 0x90
+
+// SetLYStub makes LY (0xFF44) always read as StubbedLY, the start of V-Blank,
+// as Gameboy Doctor reference logs assume. The PPU keeps running normally.
+func (b *Bus) SetLYStub(enabled bool) {
+	b.lyStub = enabled
+}
+
+// SetCGBMode enables CGB-only registers (KEY1 and SVBK).
+// In DMG mode they read as 0xFF and ignore writes.
+func (b *Bus) SetCGBMode(enabled bool) {
+	b.cgbMode = enabled
+}
+
+// readKEY1 reads the KEY1 register: bit 7 is the current speed, bit 0 the prepare bit.
+func (b *Bus) readKEY1() uint8 {
+	if !b.cgbMode {
+		return 0xFF
+	}
+	value := uint8(0x7E) // Unused bits read as 1
+	if b.doubleSpeed {
+		value |= 0x80
+	}
+	if b.speedPrepare {
+		value |= 0x01
+	}
+	return value
+}
+
+// writeKEY1 writes the KEY1 register. Only the prepare bit is writable.
+func (b *Bus) writeKEY1(value uint8) {
+	if !b.cgbMode {
+		return
+	}
+	b.speedPrepare = value&0x01 != 0
+}
+
+// DoubleSpeed reports whether the CPU is running in CGB double-speed mode.
+func (b *Bus) DoubleSpeed() bool {
+	return b.doubleSpeed
+}
+
+// SwitchSpeed performs a speed switch if one was prepared via KEY1.
+// It is called when the CPU executes STOP and returns true if the speed changed.
+func (b *Bus) SwitchSpeed() bool {
+	if !b.cgbMode || !b.speedPrepare {
+		return false
+	}
+	b.speedPrepare = false
+	b.doubleSpeed = !b.doubleSpeed
+	return true
+}
+
+// WRAMBank returns the WRAM bank mapped at D000-DFFF (1-7).
+// It is always 1 in DMG mode.
+func (b *Bus) WRAMBank() int {
+	if !b.cgbMode || b.svbk == 0 {
+		return 1 // Selecting bank 0 maps bank 1
+	}
+	return int(b.svbk)
+}
+
+// readWRAM reads Work RAM at offset (0x0000-0x1FFF from C000).
+// Echo RAM passes offsets up to 0x1DFF (FDFF mirrors DDFF), so DE00-DFFF has
+// no mirror and the banked region is mirrored through the current SVBK bank.
+func (b *Bus) readWRAM(offset uint16) uint8 {
+	if offset < 0x1000 {
+		return b.wram[0][offset]
+	}
+	return b.wram[b.WRAMBank()][offset-0x1000]
+}
+
+// writeWRAM writes Work RAM at offset (0x0000-0x1FFF from C000).
+func (b *Bus) writeWRAM(offset uint16, value uint8) {
+	if offset < 0x1000 {
+		b.wram[0][offset] = value
+		return
+	}
+	b.wram[b.WRAMBank()][offset-0x1000] = value
+}
+
+// readSVBK reads the SVBK register. Only bits 0-2 are implemented.
+func (b *Bus) readSVBK() uint8 {
+	if !b.cgbMode {
+		return 0xFF
+	}
+	return 0xF8 | b.svbk
+}
+
+// writeSVBK writes the SVBK register, selecting the WRAM bank at D000-DFFF.
+func (b *Bus) writeSVBK(value uint8) {
+	if !b.cgbMode {
+		return
+	}
+	b.svbk = value & 0x07
+}
+
+// readIF reads the interrupt flag register.
+// Only 5 interrupt sources exist, so the upper 3 bits always read as 1.
+func (b *Bus) readIF() uint8 {
+	return b.interruptFlag | 0xE0
+}
+
+// writeIF writes the interrupt flag register, keeping only the 5 interrupt bits.
+func (b *Bus) writeIF(value uint8) {
+	b.interruptFlag = value & 0x1F
+}
+
+// RequestInterrupt requests an interrupt by setting its bit in IF (0xFF0F).
+// The bus is the single source of truth for IF, so hardware requests and
+// game writes to IF always operate on the same register.
+func (b *Bus) RequestInterrupt(bit uint8) {
+	b.interruptFlag |= (1 << bit) & 0x1F
+}
+
 // ErrROMLoadFailed indicates ROM loading failed.
 var ErrROMLoadFailed = errors.New("ROM loading failed")
 
@@ -352,8 +578,9 @@ func (b *Bus) GetCartridge() cartridge.Cartridge {
 // Reset clears all RAM while keeping the cartridge and PPU loaded.
 // Note: Cartridge RAM is not cleared as it may be battery-backed.
 func (b *Bus) Reset() {
-	// Clear Work RAM
+	// Clear Work RAM and select bank 1
 	clear(b.wram[:])
+	b.svbk = 0
 
 	// Clear I/O registers
 	clear(b.io[:])
@@ -361,9 +588,14 @@ func (b *Bus) Reset() {
 	// Clear High RAM
 	clear(b.hram[:])
 
-	// Clear Interrupt Enable
+	// Clear Interrupt Flag and Interrupt Enable
+	b.interruptFlag = 0
 	b.ie = 0
 
+	// Return to normal speed
+	b.speedPrepare = false
+	b.doubleSpeed = false
+
 	// Clear DMA state
 	b.dmaActive = false
 	b.dmaSource = 0
@@ -396,6 +628,9 @@ func (b *Bus) StepDMA() bool {
 	// Check if transfer complete
 	if b.dmaCycles == 0 {
 		b.dmaActive = false
+		if b.OnDMAComplete != nil {
+			b.OnDMAComplete()
+		}
 		return false
 	}
 
@@ -426,17 +661,13 @@ func (b *Bus) dmaRead(addr uint16) uint8 {
 		}
 		return 0xFF
 
-	// Work RAM Bank 0 (C000-CFFF)
-	case addr < 0xD000:
-		return b.wram[addr-0xC000]
-
-	// Work RAM Bank 1 (D000-DFFF)
+	// Work RAM (C000-DFFF)
 	case addr < 0xE000:
-		return b.wram[addr-0xC000]
+		return b.readWRAM(addr - 0xC000)
 
 	// Echo RAM (E000-FDFF)
 	case addr < 0xFE00:
-		return b.wram[addr-0xE000]
+		return b.readWRAM(addr - 0xE000)
 
 	default:
 		return 0xFF
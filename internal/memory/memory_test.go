@@ -3,6 +3,7 @@ package memory
 import (
 	"testing"
 
+	"github.com/richardwooding/nostalgiza/internal/cpu"
 	"github.com/richardwooding/nostalgiza/internal/ppu"
 )
 
@@ -168,6 +169,81 @@ func TestInterruptEnableRegister(t *testing.T) {
 	}
 }
 
+func TestInterruptFlagRegister(t *testing.T) {
+	bus := NewBus()
+
+	// Upper 3 bits always read as 1
+	bus.Write(0xFF0F, 0x00)
+	if value := bus.Read(0xFF0F); value != 0xE0 {
+		t.Errorf("Read(0xFF0F) after writing 0x00 = %02X, want 0xE0", value)
+	}
+
+	// Only the low 5 bits are writable
+	bus.Write(0xFF0F, 0xFF)
+	if value := bus.Read(0xFF0F); value != 0xFF {
+		t.Errorf("Read(0xFF0F) after writing 0xFF = %02X, want 0xFF", value)
+	}
+	if bus.interruptFlag != 0x1F {
+		t.Errorf("stored IF = %02X, want 0x1F", bus.interruptFlag)
+	}
+
+	// Reading IF and ORing it back must not set phantom bits
+	bus.Write(0xFF0F, 0x00)
+	bus.Write(0xFF0F, bus.Read(0xFF0F)|0x04)
+	if value := bus.Read(0xFF0F); value != 0xE4 {
+		t.Errorf("Read(0xFF0F) after read-modify-write = %02X, want 0xE4", value)
+	}
+}
+
+func TestRequestInterrupt(t *testing.T) {
+	bus := NewBus()
+
+	// A game write to IF and a hardware request share the same register
+	bus.Write(0xFF0F, 0x01) // Game sets V-Blank
+	bus.RequestInterrupt(2) // Timer requests an interrupt
+	if value := bus.Read(0xFF0F); value != 0xE5 {
+		t.Errorf("Read(0xFF0F) = %02X, want 0xE5", value)
+	}
+
+	// A game clearing IF clears hardware-requested bits too
+	bus.Write(0xFF0F, 0x00)
+	bus.RequestInterrupt(4) // Joypad
+	if value := bus.Read(0xFF0F); value != 0xF0 {
+		t.Errorf("Read(0xFF0F) after clear and joypad request = %02X, want 0xF0", value)
+	}
+
+	// Out-of-range bits are ignored
+	bus.Write(0xFF0F, 0x00)
+	bus.RequestInterrupt(5)
+	if value := bus.Read(0xFF0F); value != 0xE0 {
+		t.Errorf("Read(0xFF0F) after invalid request = %02X, want 0xE0", value)
+	}
+}
+
+func TestInterruptServicingClearsIF(t *testing.T) {
+	bus := NewBus()
+	c := cpu.New(bus)
+
+	// Place NOPs in HRAM and execute from there (no cartridge loaded)
+	c.Registers.PC = 0xFF80
+	c.Registers.SP = 0xFFFE
+	c.IME = true
+
+	// V-Blank and Timer enabled and pending
+	bus.Write(0xFFFF, 0x05)
+	bus.Write(0xFF0F, 0x05)
+
+	c.Step()
+
+	// V-Blank has priority; only its bit should be cleared
+	if c.Registers.PC != 0x0040 {
+		t.Errorf("PC after interrupt = 0x%04X, want 0x0040", c.Registers.PC)
+	}
+	if value := bus.Read(0xFF0F); value != 0xE4 {
+		t.Errorf("Read(0xFF0F) after servicing V-Blank = %02X, want 0xE4", value)
+	}
+}
+
 func TestExternalRAM(t *testing.T) {
 	bus := NewBus()
 
@@ -397,7 +473,7 @@ func TestMemoryBoundaries(t *testing.T) {
 
 		// I/O boundaries (skip 0xFF00 joypad - has special default value 0xFF)
 		{"I/O register", 0xFF01, 0x01, true, true},
-		{"I/O end", 0xFF7F, 0x02, true, true},
+		{"I/O end (unused, reads 0xFF)", 0xFF7F, 0x02, true, false},
 
 		// HRAM boundaries
 		{"HRAM start", 0xFF80, 0x03, true, true},
@@ -475,6 +551,59 @@ func TestEchoRAMMirroring(t *testing.T) {
 	}
 }
 
+// TestEchoRAMUpperBoundary tests that echo RAM ends at FDFF (mirroring DDFF)
+// and that DE00-DFFF has no mirror.
+func TestEchoRAMUpperBoundary(t *testing.T) {
+	bus := newBusWithPPU()
+
+	for addr := 0xDE00; addr <= 0xDFFF; addr++ {
+		bus.Write(uint16(addr), 0x77) //nolint:gosec // G115: addr is within the WRAM range
+	}
+	bus.Write(0xDDFF, 0x5A)
+
+	if got := bus.Read(0xFDFF); got != 0x5A {
+		t.Errorf("Read(0xFDFF) = 0x%02X, want 0x5A (mirror of 0xDDFF)", got)
+	}
+
+	// FE00 is OAM, not a mirror of DE00
+	if got := bus.Read(0xFE00); got != 0x00 {
+		t.Errorf("Read(0xFE00) = 0x%02X, want 0x00 (OAM)", got)
+	}
+	bus.Write(0xFE00, 0x11)
+	if got := bus.Read(0xDE00); got != 0x77 {
+		t.Errorf("Read(0xDE00) after OAM write = 0x%02X, want 0x77", got)
+	}
+
+	// Writing the last echo byte only changes DDFF
+	bus.Write(0xFDFF, 0xA5)
+	if got := bus.Read(0xDDFF); got != 0xA5 {
+		t.Errorf("Read(0xDDFF) = 0x%02X, want 0xA5", got)
+	}
+	if got := bus.Read(0xDE00); got != 0x77 {
+		t.Errorf("Read(0xDE00) = 0x%02X, want 0x77", got)
+	}
+}
+
+// TestEchoRAMBanked tests that echo RAM mirrors the selected CGB WRAM bank
+// up to its upper boundary.
+func TestEchoRAMBanked(t *testing.T) {
+	bus := NewBus()
+	bus.SetCGBMode(true)
+
+	bus.Write(0xFF70, 0x03)
+	bus.Write(0xDDFF, 0x33)
+	bus.Write(0xFF70, 0x05)
+	bus.Write(0xFDFF, 0x55) // Echo write lands in bank 5
+
+	if got := bus.Read(0xDDFF); got != 0x55 {
+		t.Errorf("bank 5 Read(0xDDFF) = 0x%02X, want 0x55", got)
+	}
+	bus.Write(0xFF70, 0x03)
+	if got := bus.Read(0xFDFF); got != 0x33 {
+		t.Errorf("bank 3 Read(0xFDFF) = 0x%02X, want 0x33", got)
+	}
+}
+
 // setupTestROMHeader sets up a minimal valid ROM header for testing.
 func setupTestROMHeader(rom []byte) {
 	// Title
@@ -496,3 +625,240 @@ func setupTestROMHeader(rom []byte) {
 	}
 	rom[0x014D] = checksum
 }
+
+func TestKEY1Register(t *testing.T) {
+	bus := NewBus()
+
+	// DMG mode: KEY1 is not present
+	bus.Write(0xFF4D, 0x01)
+	if got := bus.Read(0xFF4D); got != 0xFF {
+		t.Errorf("DMG KEY1 = 0x%02X, want 0xFF", got)
+	}
+	if bus.SwitchSpeed() {
+		t.Error("SwitchSpeed() in DMG mode = true, want false")
+	}
+
+	bus.SetCGBMode(true)
+	if got := bus.Read(0xFF4D); got != 0x7E {
+		t.Errorf("KEY1 = 0x%02X, want 0x7E", got)
+	}
+
+	// Switching without preparing does nothing
+	if bus.SwitchSpeed() {
+		t.Error("SwitchSpeed() without prepare = true, want false")
+	}
+
+	bus.Write(0xFF4D, 0x01)
+	if got := bus.Read(0xFF4D); got != 0x7F {
+		t.Errorf("KEY1 after prepare = 0x%02X, want 0x7F", got)
+	}
+
+	// Switching clears the prepare bit and sets the speed bit
+	if !bus.SwitchSpeed() {
+		t.Fatal("SwitchSpeed() after prepare = false, want true")
+	}
+	if !bus.DoubleSpeed() {
+		t.Error("DoubleSpeed() = false, want true")
+	}
+	if got := bus.Read(0xFF4D); got != 0xFE {
+		t.Errorf("KEY1 in double speed = 0x%02X, want 0xFE", got)
+	}
+
+	// Switching back to normal speed
+	bus.Write(0xFF4D, 0x01)
+	bus.SwitchSpeed()
+	if bus.DoubleSpeed() {
+		t.Error("DoubleSpeed() after second switch = true, want false")
+	}
+}
+
+func TestSVBKDMGMode(t *testing.T) {
+	bus := NewBus()
+
+	bus.Write(0xD000, 0x11)
+	bus.Write(0xFF70, 0x03)
+
+	if got := bus.Read(0xFF70); got != 0xFF {
+		t.Errorf("DMG SVBK = 0x%02X, want 0xFF", got)
+	}
+	if got := bus.WRAMBank(); got != 1 {
+		t.Errorf("DMG WRAMBank() = %d, want 1", got)
+	}
+	if got := bus.Read(0xD000); got != 0x11 {
+		t.Errorf("Read(0xD000) = 0x%02X, want 0x11", got)
+	}
+	if got := bus.Read(0xF000); got != 0x11 {
+		t.Errorf("Echo Read(0xF000) = 0x%02X, want 0x11", got)
+	}
+}
+
+func TestSVBKBankSwitching(t *testing.T) {
+	bus := NewBus()
+	bus.SetCGBMode(true)
+
+	if got := bus.Read(0xFF70); got != 0xF8 {
+		t.Errorf("SVBK = 0x%02X, want 0xF8", got)
+	}
+
+	// Fill each bank with its own number
+	for bank := uint8(1); bank < 8; bank++ {
+		bus.Write(0xFF70, bank)
+		bus.Write(0xD123, bank)
+	}
+	bus.Write(0xC123, 0xC0)
+
+	for bank := uint8(1); bank < 8; bank++ {
+		bus.Write(0xFF70, bank)
+		if got := bus.Read(0xD123); got != bank {
+			t.Errorf("bank %d: Read(0xD123) = 0x%02X, want 0x%02X", bank, got, bank)
+		}
+		if got := bus.Read(0xF123); got != bank {
+			t.Errorf("bank %d: Echo Read(0xF123) = 0x%02X, want 0x%02X", bank, got, bank)
+		}
+		// Bank 0 is not affected by SVBK
+		if got := bus.Read(0xC123); got != 0xC0 {
+			t.Errorf("bank %d: Read(0xC123) = 0x%02X, want 0xC0", bank, got)
+		}
+	}
+
+	// Selecting bank 0 maps bank 1
+	bus.Write(0xFF70, 0x00)
+	if got := bus.WRAMBank(); got != 1 {
+		t.Errorf("WRAMBank() after writing 0 = %d, want 1", got)
+	}
+	if got := bus.Read(0xD123); got != 0x01 {
+		t.Errorf("Read(0xD123) with SVBK=0 = 0x%02X, want 0x01", got)
+	}
+
+	// Only bits 0-2 are used
+	bus.Write(0xFF70, 0xFA)
+	if got := bus.Read(0xFF70); got != 0xFA {
+		t.Errorf("SVBK = 0x%02X, want 0xFA", got)
+	}
+	if got := bus.WRAMBank(); got != 2 {
+		t.Errorf("WRAMBank() = %d, want 2", got)
+	}
+
+	bus.Reset()
+	if got := bus.WRAMBank(); got != 1 {
+		t.Errorf("WRAMBank() after Reset = %d, want 1", got)
+	}
+}
+
+func TestVBKRouting(t *testing.T) {
+	bus := newBusWithPPU()
+
+	// DMG mode: VBK is not present
+	bus.Write(0xFF4F, 0x01)
+	if got := bus.Read(0xFF4F); got != 0xFF {
+		t.Errorf("DMG VBK = 0x%02X, want 0xFF", got)
+	}
+
+	p := ppu.New(nil)
+	p.SetCGBMode(true)
+	p.SetModeForTesting(0)
+	bus.SetPPU(p)
+
+	bus.Write(0x8000, 0x11)
+	bus.Write(0xFF4F, 0x01)
+	if got := bus.Read(0xFF4F); got != 0xFF {
+		t.Errorf("VBK = 0x%02X, want 0xFF", got)
+	}
+	bus.Write(0x8000, 0x22)
+	if got := bus.Read(0x8000); got != 0x22 {
+		t.Errorf("Read(0x8000) in bank 1 = 0x%02X, want 0x22", got)
+	}
+
+	bus.Write(0xFF4F, 0x00)
+	if got := bus.Read(0xFF4F); got != 0xFE {
+		t.Errorf("VBK = 0x%02X, want 0xFE", got)
+	}
+	if got := bus.Read(0x8000); got != 0x11 {
+		t.Errorf("Read(0x8000) in bank 0 = 0x%02X, want 0x11", got)
+	}
+}
+
+func TestDMAHooks(t *testing.T) {
+	bus := newBusWithPPU()
+	for i := range uint16(0xA0) {
+		bus.Write(0xC100+i, uint8(i)) //nolint:gosec // G115: i is below 0xA0
+	}
+
+	var sources []uint16
+	completions := 0
+	bus.OnDMAStart = func(source uint16) { sources = append(sources, source) }
+	bus.OnDMAComplete = func() { completions++ }
+
+	bus.Write(0xFF46, 0xC1)
+	if len(sources) != 1 || sources[0] != 0xC100 {
+		t.Fatalf("OnDMAStart sources = %v, want [0xC100]", sources)
+	}
+
+	for i := range 159 {
+		if !bus.StepDMA() {
+			t.Fatalf("StepDMA() = false after %d M-cycles, want true", i+1)
+		}
+	}
+	if completions != 0 {
+		t.Fatalf("OnDMAComplete called %d times before the last M-cycle, want 0", completions)
+	}
+
+	if bus.StepDMA() {
+		t.Error("StepDMA() = true on the 160th M-cycle, want false")
+	}
+	if completions != 1 {
+		t.Errorf("OnDMAComplete calls = %d, want 1", completions)
+	}
+	if got := bus.Read(0xFE9F); got != 0x9F {
+		t.Errorf("OAM[0x9F] = 0x%02X, want 0x9F", got)
+	}
+
+	// Stepping an idle DMA does not fire the hook again
+	bus.StepDMA()
+	if completions != 1 {
+		t.Errorf("OnDMAComplete calls after idle step = %d, want 1", completions)
+	}
+
+	// Invalid source pages start no transfer
+	bus.Write(0xFF46, 0xF2)
+	if len(sources) != 1 {
+		t.Errorf("OnDMAStart sources = %v after invalid source, want 1 call", sources)
+	}
+}
+
+func TestUnusedIORegisters(t *testing.T) {
+	bus := newBusWithPPU()
+
+	// Unmapped addresses read 0xFF whatever was written
+	for _, addr := range []uint16{0xFF03, 0xFF08, 0xFF0E, 0xFF1F, 0xFF27, 0xFF2F, 0xFF4C, 0xFF4E, 0xFF50, 0xFF57, 0xFF7F} {
+		bus.Write(addr, 0x00)
+		if got := bus.Read(addr); got != 0xFF {
+			t.Errorf("Read(0x%04X) = 0x%02X, want 0xFF", addr, got)
+		}
+	}
+
+	// Unused bits of mapped registers read as 1
+	bus.Write(0xFF02, 0x81)
+	if got := bus.Read(0xFF02); got != 0xFF {
+		t.Errorf("Read(SC) = 0x%02X, want 0xFF", got)
+	}
+	bus.Write(0xFF02, 0x00)
+	if got := bus.Read(0xFF02); got != 0x7E {
+		t.Errorf("Read(SC) = 0x%02X, want 0x7E", got)
+	}
+	bus.Write(0xFF01, 0x00)
+	if got := bus.Read(0xFF01); got != 0x00 {
+		t.Errorf("Read(SB) = 0x%02X, want 0x00", got)
+	}
+
+	// CGB-only registers read 0xFF on DMG but are stored in CGB mode
+	bus.Write(0xFF68, 0x05)
+	if got := bus.Read(0xFF68); got != 0xFF {
+		t.Errorf("DMG Read(BCPS) = 0x%02X, want 0xFF", got)
+	}
+	bus.SetCGBMode(true)
+	bus.Write(0xFF68, 0x05)
+	if got := bus.Read(0xFF68); got != 0x05 {
+		t.Errorf("CGB Read(BCPS) = 0x%02X, want 0x05", got)
+	}
+}
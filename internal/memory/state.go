@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// busState is the memory bus state written by SaveState.
+type busState struct {
+	WRAM          [8][0x1000]uint8
+	IO            [0x80]uint8
+	HRAM          [0x7F]uint8
+	InterruptFlag uint8
+	IE            uint8
+	SpeedPrepare  bool
+	DoubleSpeed   bool
+	SVBK          uint8
+	DMAActive     bool
+	DMASource     uint16
+	DMACycles     uint16
+}
+
+// SaveState writes Work RAM, High RAM, the I/O registers held by the bus and
+// the DMA state to w. Connected components save their own state.
+func (b *Bus) SaveState(w io.Writer) error {
+	s := busState{
+		WRAM:          b.wram,
+		IO:            b.io,
+		HRAM:          b.hram,
+		InterruptFlag: b.interruptFlag,
+		IE:            b.ie,
+		SpeedPrepare:  b.speedPrepare,
+		DoubleSpeed:   b.doubleSpeed,
+		SVBK:          b.svbk,
+		DMAActive:     b.dmaActive,
+		DMASource:     b.dmaSource,
+		DMACycles:     b.dmaCycles,
+	}
+	if err := binary.Write(w, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to write memory state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState. Connected components,
+// hooks and access statistics are kept.
+func (b *Bus) LoadState(r io.Reader) error {
+	var s busState
+	if err := binary.Read(r, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to read memory state: %w", err)
+	}
+
+	b.wram = s.WRAM
+	b.io = s.IO
+	b.hram = s.HRAM
+	b.interruptFlag = s.InterruptFlag
+	b.ie = s.IE
+	b.speedPrepare = s.SpeedPrepare
+	b.doubleSpeed = s.DoubleSpeed
+	b.svbk = s.SVBK
+	b.dmaActive = s.DMAActive
+	b.dmaSource = s.DMASource
+	b.dmaCycles = s.DMACycles
+	return nil
+}
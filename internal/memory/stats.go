@@ -0,0 +1,74 @@
+package memory
+
+// Memory regions counted by access statistics.
+const (
+	RegionROM    = "ROM"    // 0000-7FFF
+	RegionVRAM   = "VRAM"   // 8000-9FFF
+	RegionExtRAM = "ExtRAM" // A000-BFFF
+	RegionWRAM   = "WRAM"   // C000-FDFF, including echo RAM
+	RegionOAM    = "OAM"    // FE00-FEFF, including the unusable area
+	RegionIO     = "IO"     // FF00-FF7F and IE (FFFF)
+	RegionHRAM   = "HRAM"   // FF80-FFFE
+)
+
+// Regions lists the access statistics regions in address order.
+var Regions = []string{RegionROM, RegionVRAM, RegionExtRAM, RegionWRAM, RegionOAM, RegionIO, RegionHRAM}
+
+// AccessCount holds the CPU reads and writes of one memory region.
+type AccessCount struct {
+	Reads, Writes uint64
+}
+
+// accessStats counts accesses per region, indexed like Regions.
+type accessStats [7]AccessCount
+
+// regionIndex returns the index in Regions of the region containing addr.
+func regionIndex(addr uint16) int {
+	switch {
+	case addr < 0x8000:
+		return 0
+	case addr < 0xA000:
+		return 1
+	case addr < 0xC000:
+		return 2
+	case addr < 0xFE00:
+		return 3
+	case addr < 0xFF00:
+		return 4
+	case addr < 0xFF80 || addr == 0xFFFF:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// SetAccessStats enables or disables counting CPU memory accesses per region.
+// Enabling starts from zero counts. Counting is off by default, leaving a
+// single nil check per access.
+func (b *Bus) SetAccessStats(enabled bool) {
+	if enabled {
+		b.stats = &accessStats{}
+	} else {
+		b.stats = nil
+	}
+}
+
+// AccessStats returns the access counts per region (see Regions), or nil
+// if counting is disabled. DMA transfers are not counted.
+func (b *Bus) AccessStats() map[string]AccessCount {
+	if b.stats == nil {
+		return nil
+	}
+	stats := make(map[string]AccessCount, len(Regions))
+	for i, region := range Regions {
+		stats[region] = b.stats[i]
+	}
+	return stats
+}
+
+// ResetAccessStats zeroes the access counts.
+func (b *Bus) ResetAccessStats() {
+	if b.stats != nil {
+		*b.stats = accessStats{}
+	}
+}
@@ -0,0 +1,52 @@
+package memory
+
+import "testing"
+
+func TestAccessStats(t *testing.T) {
+	bus := NewBus()
+
+	if stats := bus.AccessStats(); stats != nil {
+		t.Errorf("AccessStats() while disabled = %v, want nil", stats)
+	}
+
+	bus.SetAccessStats(true)
+	bus.Read(0x0150)        // ROM
+	bus.Read(0x4000)        // ROM
+	bus.Write(0x2000, 0x01) // ROM (MBC control)
+	bus.Read(0x8000)        // VRAM
+	bus.Write(0xA000, 0x00) // ExtRAM
+	bus.Write(0xC000, 0x42) // WRAM
+	bus.Read(0xE000)        // WRAM (echo)
+	bus.Read(0xFE00)        // OAM
+	bus.Write(0xFF40, 0x91) // IO
+	bus.Read(0xFFFF)        // IO (IE)
+	bus.Write(0xFF80, 0x01) // HRAM
+	bus.Read(0xFFFE)        // HRAM
+
+	want := map[string]AccessCount{
+		RegionROM:    {Reads: 2, Writes: 1},
+		RegionVRAM:   {Reads: 1},
+		RegionExtRAM: {Writes: 1},
+		RegionWRAM:   {Reads: 1, Writes: 1},
+		RegionOAM:    {Reads: 1},
+		RegionIO:     {Reads: 1, Writes: 1},
+		RegionHRAM:   {Reads: 1, Writes: 1},
+	}
+	stats := bus.AccessStats()
+	for _, region := range Regions {
+		if stats[region] != want[region] {
+			t.Errorf("%s = %+v, want %+v", region, stats[region], want[region])
+		}
+	}
+
+	bus.ResetAccessStats()
+	if got := bus.AccessStats()[RegionROM]; got != (AccessCount{}) {
+		t.Errorf("ROM after reset = %+v, want zero", got)
+	}
+
+	bus.SetAccessStats(false)
+	bus.Read(0x0000)
+	if stats := bus.AccessStats(); stats != nil {
+		t.Errorf("AccessStats() after disabling = %v, want nil", stats)
+	}
+}
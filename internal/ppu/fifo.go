@@ -0,0 +1,283 @@
+package ppu
+
+import "sort"
+
+// Pixel FIFO renderer.
+//
+// The FIFO renderer is an optional, more accurate alternative to the scanline
+// renderer. Instead of drawing the whole line at the end of Mode 3, it runs
+// one dot at a time during Mode 3: a background fetcher pushes 8 pixels at a
+// time into the background FIFO, and one pixel per dot is shifted out to the
+// LCD. This reproduces mid-scanline effects the scanline renderer cannot:
+//   - SCX%8 pixels are fetched and discarded at the start of the line.
+//   - Each sprite fetch stalls pixel output for 6-11 dots.
+//   - Starting the window restarts the fetcher.
+//
+// All of these lengthen Mode 3 and shorten H-Blank accordingly.
+
+const (
+	// fifoFetchDots is the number of dots a background/window tile fetch takes
+	// (tile number, tile data low, tile data high).
+	fifoFetchDots = 6
+	// fifoInitialFetchDots is the delay of the discarded first tile fetch at the start of a line.
+	fifoInitialFetchDots = 6
+	// fifoSpriteFetchDots is the minimum stall of a sprite fetch.
+	fifoSpriteFetchDots = 6
+	// fifoMaxSpriteWait is the most a sprite fetch waits for a background fetch to finish.
+	fifoMaxSpriteWait = 5
+)
+
+// objPixel is a sprite pixel in the object FIFO.
+type objPixel struct {
+	color uint8 // Color index (0 = transparent)
+	attrs uint8 // Sprite attributes (palette and priority)
+}
+
+// pixelFIFO holds the per-scanline state of the FIFO renderer.
+type pixelFIFO struct {
+	bg     [8]uint8 // Background/window color indices
+	bgLen  int
+	obj    [8]objPixel // Sprite pixels, aligned with the front of the background FIFO
+	objLen int
+
+	lx        int    // Next LCD X position
+	discard   int    // Pixels left to discard for SCX fine scrolling
+	fetchX    uint16 // Tile column of the next fetch
+	fetchDots int    // Dots spent on the current tile fetch (negative during the initial fetch)
+	window    bool   // Fetcher is fetching window tiles
+	stall     int    // Dots left in the current sprite fetch
+
+	sprites    []sprite // Sprites on this line, sorted by X
+	nextSprite int
+
+	dots uint16 // Dots spent in Mode 3 so far
+}
+
+// SetFIFORenderer selects the pixel FIFO renderer (true) or the scanline renderer (false).
+// The FIFO renderer is slower but reproduces mid-scanline timing effects and
+// variable Mode 3 length.
+func (p *PPU) SetFIFORenderer(enabled bool) {
+	p.fifoEnabled = enabled
+}
+
+// fifoStartLine prepares the FIFO renderer at the start of Mode 3.
+func (p *PPU) fifoStartLine() {
+	spriteHeight := uint16(8)
+	if p.lcdc&LCDCOBJSize != 0 {
+		spriteHeight = 16
+	}
+	p.scanSprites(spriteHeight)
+
+	f := &p.fifo
+	sprites := f.sprites[:0]
+	for _, spr := range p.spriteBuffer {
+		// Sprites entirely off the left edge are never fetched
+		if spr.x > -8 {
+			sprites = append(sprites, spr)
+		}
+	}
+	// Sprites are fetched left to right; on DMG the leftmost sprite has priority
+	// and ties keep OAM order
+	sort.SliceStable(sprites, func(i, j int) bool {
+		return sprites[i].x < sprites[j].x
+	})
+
+	*f = pixelFIFO{
+		sprites:   sprites,
+		discard:   int(p.scx % 8),
+		fetchDots: -fifoInitialFetchDots,
+	}
+}
+
+// fifoDone reports whether the FIFO renderer has output the whole scanline.
+func (p *PPU) fifoDone() bool {
+	return p.fifo.lx >= ScreenWidth
+}
+
+// fifoTick advances the FIFO renderer by one dot.
+func (p *PPU) fifoTick() {
+	f := &p.fifo
+	f.dots++
+
+	if f.stall == 0 {
+		p.fifoCheckWindow()
+		p.fifoCheckSprite()
+	}
+
+	// A sprite fetch stalls both the fetcher and pixel output
+	if f.stall > 0 {
+		f.stall--
+		if f.stall == 0 {
+			p.fifoLoadSprite()
+		}
+		return
+	}
+
+	p.fifoShiftPixel()
+
+	// Background fetcher: fetch a tile, then push it once the FIFO is empty
+	if f.fetchDots < fifoFetchDots {
+		f.fetchDots++
+	}
+	if f.fetchDots >= fifoFetchDots && f.bgLen == 0 {
+		p.fifoFetchTile()
+		f.fetchDots = 0
+		f.fetchX++
+	}
+}
+
+// fifoCheckWindow restarts the fetcher on window tiles when the window starts on this line.
+func (p *PPU) fifoCheckWindow() {
+	f := &p.fifo
+	if f.window || p.lcdc&LCDCWindowEnable == 0 || p.ly < p.wy || p.wx >= windowXHidden {
+		return
+	}
+	if f.lx < int(p.wx)-7 {
+		return
+	}
+
+	f.window = true
+	f.bgLen = 0
+	f.fetchX = 0
+	f.fetchDots = 0
+	// WX 0-6 start the window left of the screen, hiding its first 7-WX columns
+	f.discard = max(0, 7-int(p.wx))
+}
+
+// fifoCheckSprite starts a sprite fetch when the next sprite reaches the output position.
+func (p *PPU) fifoCheckSprite() {
+	f := &p.fifo
+	if f.discard > 0 || p.lcdc&LCDCOBJEnable == 0 || f.nextSprite >= len(f.sprites) {
+		return
+	}
+	if int(f.sprites[f.nextSprite].x) > f.lx {
+		return
+	}
+
+	// The sprite fetch waits for the current background fetch to finish
+	wait := fifoFetchDots - f.fetchDots
+	if wait > fifoMaxSpriteWait {
+		wait = fifoMaxSpriteWait
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	f.fetchDots += wait
+	f.stall = fifoSpriteFetchDots + wait
+}
+
+// fifoLoadSprite fetches the current sprite's row and mixes it into the object FIFO.
+func (p *PPU) fifoLoadSprite() {
+	f := &p.fifo
+	spr := f.sprites[f.nextSprite]
+	f.nextSprite++
+
+	spriteHeight := uint16(8)
+	if p.lcdc&LCDCOBJSize != 0 {
+		spriteHeight = 16
+	}
+	tileAddr, spriteLine := p.spriteRow(spr, spriteHeight)
+
+	// Pixels left of the output position (off the left edge) are skipped
+	skip := f.lx - int(spr.x)
+	for i := skip; i < 8; i++ {
+		tileX := uint16(i) //nolint:gosec // i is 0-7
+		if spr.attrs&SpriteAttrXFlip != 0 {
+			tileX = 7 - tileX
+		}
+		pixel := objPixel{
+			color: p.getTilePixel(tileAddr, tileX, spriteLine),
+			attrs: spr.attrs,
+		}
+
+		// Earlier sprites keep priority over later ones
+		slot := i - skip
+		if slot >= f.objLen {
+			f.obj[slot] = pixel
+			f.objLen = slot + 1
+		} else if f.obj[slot].color == 0 {
+			f.obj[slot] = pixel
+		}
+	}
+}
+
+// fifoFetchTile fetches the next background or window tile row into the background FIFO.
+func (p *PPU) fifoFetchTile() {
+	f := &p.fifo
+
+	var tileMapBase, tileCol, tileY uint16
+	if f.window {
+		tileMapBase = 0x1800
+		if p.lcdc&LCDCWindowTileMap != 0 {
+			tileMapBase = 0x1C00
+		}
+		tileCol = f.fetchX % 32
+		tileY = uint16(p.windowLine)
+	} else {
+		tileMapBase = 0x1800
+		if p.lcdc&LCDCBGTileMap != 0 {
+			tileMapBase = 0x1C00
+		}
+		tileCol = (uint16(p.scx)/8 + f.fetchX) % 32
+		tileY = uint16(p.ly) + uint16(p.scy)
+	}
+
+	useSigned := p.lcdc&LCDCBGTileData == 0
+	tileDataBase := uint16(0x0000)
+	if useSigned {
+		tileDataBase = 0x0800
+	}
+
+	tileIndex := p.vram[0][tileMapBase+((tileY/8)%32)*32+tileCol]
+	tileAddr := p.getTileDataAddr(tileIndex, useSigned, tileDataBase)
+	for x := range uint16(8) {
+		f.bg[x] = p.getTilePixel(tileAddr, x, tileY%8)
+	}
+	f.bgLen = 8
+}
+
+// fifoShiftPixel shifts one pixel out of the FIFOs to the LCD.
+func (p *PPU) fifoShiftPixel() {
+	f := &p.fifo
+	if f.bgLen == 0 {
+		return
+	}
+
+	bgColor := f.bg[0]
+	copy(f.bg[:], f.bg[1:f.bgLen])
+	f.bgLen--
+
+	// Fine scroll: the first SCX%8 pixels of the line are discarded
+	if f.discard > 0 {
+		f.discard--
+		return
+	}
+
+	var obj objPixel
+	if f.objLen > 0 {
+		obj = f.obj[0]
+		copy(f.obj[:], f.obj[1:f.objLen])
+		f.objLen--
+	}
+
+	// With BG/window disabled, the background is white (color 0)
+	bgEnabled := p.lcdc&LCDCBGWindowEnable != 0
+	if !bgEnabled {
+		bgColor = 0
+	}
+
+	var color uint8
+	switch {
+	case obj.color != 0 && (obj.attrs&SpriteAttrPriority == 0 || bgColor == 0):
+		palette := p.obp0
+		if obj.attrs&SpriteAttrPalette != 0 {
+			palette = p.obp1
+		}
+		color = p.applyPalette(obj.color, palette)
+	case bgEnabled:
+		color = p.applyPalette(bgColor, p.bgp)
+	}
+
+	p.framebuffer[int(p.ly)*ScreenWidth+f.lx] = color
+	f.lx++
+}
@@ -0,0 +1,183 @@
+package ppu
+
+import "testing"
+
+// setupFIFOBackground fills tile 0 with a row pattern of colors 0,1,2,3,0,1,2,3
+// and uses an identity palette, so each pixel shows its position within the tile.
+func setupFIFOBackground(p *PPU) {
+	for row := 0; row < 8; row++ {
+		p.vram[0][row*2] = 0x55   // Low bits:  01010101
+		p.vram[0][row*2+1] = 0x33 // High bits: 00110011
+	}
+	p.lcdc = LCDCLCDEnable | LCDCBGWindowEnable | LCDCBGTileData | LCDCOBJEnable
+	p.bgp = 0xE4
+	p.obp0 = 0xE4
+}
+
+// measureLine steps a PPU at the start of a scanline one dot at a time and
+// returns the Mode 3 and H-Blank durations of that line.
+func measureLine(t *testing.T, p *PPU) (mode3, hblank int) {
+	t.Helper()
+
+	startLY := p.ly
+	total := 0
+	for p.ly == startLY {
+		if total > 2*DotsPerScanline {
+			t.Fatal("scanline did not complete")
+		}
+		switch p.mode {
+		case ModeDrawing:
+			mode3++
+		case ModeHBlank:
+			hblank++
+		}
+		p.Step(1)
+		total++
+	}
+
+	if total != DotsPerScanline {
+		t.Errorf("Scanline length = %d dots, want %d", total, DotsPerScanline)
+	}
+	return mode3, hblank
+}
+
+// TestFIFOBaseMode3Length tests Mode 3 length with no scrolling or sprites.
+func TestFIFOBaseMode3Length(t *testing.T) {
+	ppu := New(nil)
+	ppu.SetFIFORenderer(true)
+	setupFIFOBackground(ppu)
+
+	mode3, hblank := measureLine(t, ppu)
+	if mode3 != DotsDrawing {
+		t.Errorf("Mode 3 length = %d, want %d", mode3, DotsDrawing)
+	}
+	if hblank != DotsHBlank {
+		t.Errorf("H-Blank length = %d, want %d", hblank, DotsHBlank)
+	}
+}
+
+// TestFIFOSCXDiscard tests that SCX%8 pixels are discarded at the start of the line.
+func TestFIFOSCXDiscard(t *testing.T) {
+	for scx := uint8(0); scx < 16; scx++ {
+		ppu := New(nil)
+		ppu.SetFIFORenderer(true)
+		setupFIFOBackground(ppu)
+		ppu.scx = scx
+
+		mode3, _ := measureLine(t, ppu)
+
+		// Each discarded pixel costs one dot
+		if want := DotsDrawing + int(scx%8); mode3 != want {
+			t.Errorf("SCX=%d: Mode 3 length = %d, want %d", scx, mode3, want)
+		}
+
+		// The first visible pixel is pixel SCX%8 of the tile
+		if want := (scx % 8) % 4; ppu.framebuffer[0] != want {
+			t.Errorf("SCX=%d: first pixel = %d, want %d", scx, ppu.framebuffer[0], want)
+		}
+	}
+}
+
+// TestFIFOSpritesLengthenMode3 tests that each sprite on the line lengthens Mode 3.
+func TestFIFOSpritesLengthenMode3(t *testing.T) {
+	prevMode3 := 0
+	for count := 0; count <= DefaultSpriteLimit; count++ {
+		ppu := New(nil)
+		ppu.SetFIFORenderer(true)
+		setupFIFOBackground(ppu)
+		for i := 0; i < count; i++ {
+			ppu.oam[i*4] = 16                // Y = 0 on screen
+			ppu.oam[i*4+1] = uint8(8 + i*16) //nolint:gosec // Test values are small
+		}
+
+		mode3, hblank := measureLine(t, ppu)
+
+		if mode3+hblank != DotsPerScanline-DotsOAMScan {
+			t.Errorf("%d sprites: Mode 3 + H-Blank = %d, want %d", count, mode3+hblank, DotsPerScanline-DotsOAMScan)
+		}
+		if count > 0 {
+			if penalty := mode3 - prevMode3; penalty < 6 || penalty > 11 {
+				t.Errorf("%d sprites: sprite penalty = %d dots, want 6-11", count, penalty)
+			}
+		}
+		prevMode3 = mode3
+	}
+}
+
+// TestFIFOMatchesScanlineRenderer tests that both renderers draw the same pixels.
+func TestFIFOMatchesScanlineRenderer(t *testing.T) {
+	setup := func(p *PPU) {
+		setupFIFOBackground(p)
+		p.lcdc |= LCDCWindowEnable
+		p.scx = 3
+		p.wx = 87
+
+		// Tile 1 is solid color 3 for sprites and the window tile map
+		for i := 16; i < 32; i++ {
+			p.vram[0][i] = 0xFF
+		}
+		for i := 0; i < 32; i++ {
+			p.vram[0][0x1800+i] = 0
+		}
+
+		p.oam[0], p.oam[1], p.oam[2] = 16, 4, 1   // Partly off the left edge
+		p.oam[4], p.oam[5], p.oam[6] = 16, 40, 1  // Behind nothing
+		p.oam[8], p.oam[9], p.oam[10] = 16, 44, 1 // Overlapping the previous sprite
+		p.oam[12], p.oam[13], p.oam[14] = 16, 164, 1
+	}
+
+	scanline := New(nil)
+	setup(scanline)
+	stepMany(scanline, DotsPerScanline)
+
+	fifo := New(nil)
+	fifo.SetFIFORenderer(true)
+	setup(fifo)
+	stepMany(fifo, DotsPerScanline)
+
+	for x := 0; x < ScreenWidth; x++ {
+		if fifo.framebuffer[x] != scanline.framebuffer[x] {
+			t.Errorf("Pixel %d = %d, want %d (scanline renderer)", x, fifo.framebuffer[x], scanline.framebuffer[x])
+		}
+	}
+}
+
+// TestFIFOWindowXEdgeCases tests that both renderers agree on window placement
+// for WX values at the screen edges.
+func TestFIFOWindowXEdgeCases(t *testing.T) {
+	for _, wx := range []uint8{0, 3, 7, 166, 167} {
+		setup := func(p *PPU) {
+			setupFIFOBackground(p)
+			p.lcdc |= LCDCWindowEnable | LCDCWindowTileMap
+			p.scx = 5
+			p.wx = wx
+
+			// Window tiles use tile 1: pixel 7 of each row is color 3
+			for row := 0; row < 8; row++ {
+				p.vram[0][16+row*2] = 0x01
+				p.vram[0][16+row*2+1] = 0x01
+			}
+			for i := 0; i < 32; i++ {
+				p.vram[0][0x1C00+i] = 1
+			}
+		}
+
+		scanline := New(nil)
+		setup(scanline)
+		stepMany(scanline, DotsPerScanline)
+
+		fifo := New(nil)
+		fifo.SetFIFORenderer(true)
+		setup(fifo)
+		stepMany(fifo, DotsPerScanline)
+
+		for x := 0; x < ScreenWidth; x++ {
+			if fifo.framebuffer[x] != scanline.framebuffer[x] {
+				t.Errorf("WX=%d: pixel %d = %d, want %d (scanline renderer)", wx, x, fifo.framebuffer[x], scanline.framebuffer[x])
+			}
+		}
+		if fifo.windowLine != scanline.windowLine {
+			t.Errorf("WX=%d: window line = %d, want %d (scanline renderer)", wx, fifo.windowLine, scanline.windowLine)
+		}
+	}
+}
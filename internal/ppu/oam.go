@@ -0,0 +1,59 @@
+package ppu
+
+// OAMEntry is one decoded 4-byte sprite entry in OAM.
+type OAMEntry struct {
+	Y     uint8 // Y position + 16
+	X     uint8 // X position + 8
+	Tile  uint8 // Tile index (always 0x8000 addressing)
+	Attrs uint8 // Attribute flags (see SpriteAttr*)
+}
+
+// DecodeOAMEntry decodes the 4 OAM bytes of one sprite.
+func DecodeOAMEntry(b [4]uint8) OAMEntry {
+	return OAMEntry{Y: b[0], X: b[1], Tile: b[2], Attrs: b[3]}
+}
+
+// ScreenX returns the sprite's left edge in screen coordinates.
+func (e OAMEntry) ScreenX() int16 {
+	return int16(e.X) - 8
+}
+
+// ScreenY returns the sprite's top edge in screen coordinates.
+func (e OAMEntry) ScreenY() int16 {
+	return int16(e.Y) - 16
+}
+
+// Priority reports whether the sprite is drawn behind BG colors 1-3.
+func (e OAMEntry) Priority() bool {
+	return e.Attrs&SpriteAttrPriority != 0
+}
+
+// YFlip reports whether the sprite is flipped vertically.
+func (e OAMEntry) YFlip() bool {
+	return e.Attrs&SpriteAttrYFlip != 0
+}
+
+// XFlip reports whether the sprite is flipped horizontally.
+func (e OAMEntry) XFlip() bool {
+	return e.Attrs&SpriteAttrXFlip != 0
+}
+
+// Palette returns the sprite's palette number (0 = OBP0, 1 = OBP1).
+func (e OAMEntry) Palette() uint8 {
+	return (e.Attrs & SpriteAttrPalette) >> 4
+}
+
+// oamEntry decodes OAM entry i (0-39).
+func (p *PPU) oamEntry(i int) OAMEntry {
+	oamAddr := i * 4
+	return DecodeOAMEntry([4]uint8(p.oam[oamAddr : oamAddr+4]))
+}
+
+// ParseOAM returns all 40 OAM entries decoded, regardless of the current PPU mode.
+func (p *PPU) ParseOAM() []OAMEntry {
+	entries := make([]OAMEntry, oamSpriteCount)
+	for i := range entries {
+		entries[i] = p.oamEntry(i)
+	}
+	return entries
+}
@@ -0,0 +1,56 @@
+package ppu
+
+import "testing"
+
+func TestDecodeOAMEntry(t *testing.T) {
+	e := DecodeOAMEntry([4]uint8{0x20, 0x0C, 0x42, SpriteAttrPriority | SpriteAttrXFlip | SpriteAttrPalette})
+
+	if e.Y != 0x20 || e.X != 0x0C || e.Tile != 0x42 {
+		t.Errorf("Y, X, Tile = 0x%02X, 0x%02X, 0x%02X, want 0x20, 0x0C, 0x42", e.Y, e.X, e.Tile)
+	}
+	if got := e.ScreenY(); got != 16 {
+		t.Errorf("ScreenY() = %d, want 16", got)
+	}
+	if got := e.ScreenX(); got != 4 {
+		t.Errorf("ScreenX() = %d, want 4", got)
+	}
+	if !e.Priority() {
+		t.Error("Priority() = false, want true")
+	}
+	if e.YFlip() {
+		t.Error("YFlip() = true, want false")
+	}
+	if !e.XFlip() {
+		t.Error("XFlip() = false, want true")
+	}
+	if got := e.Palette(); got != 1 {
+		t.Errorf("Palette() = %d, want 1", got)
+	}
+
+	// Off-screen coordinates are negative
+	e = DecodeOAMEntry([4]uint8{0x00, 0x00, 0x00, SpriteAttrYFlip})
+	if e.ScreenX() != -8 || e.ScreenY() != -16 {
+		t.Errorf("ScreenX(), ScreenY() = %d, %d, want -8, -16", e.ScreenX(), e.ScreenY())
+	}
+	if !e.YFlip() || e.Palette() != 0 {
+		t.Errorf("YFlip(), Palette() = %v, %d, want true, 0", e.YFlip(), e.Palette())
+	}
+}
+
+func TestParseOAM(t *testing.T) {
+	ppu := New(nil)
+	ppu.oam[0], ppu.oam[1], ppu.oam[2], ppu.oam[3] = 0x10, 0x08, 0x01, 0x00
+	ppu.oam[156], ppu.oam[157], ppu.oam[158], ppu.oam[159] = 0x90, 0xA8, 0xFF, SpriteAttrYFlip
+	ppu.mode = ModeDrawing // OAM is blocked for the CPU, but not for ParseOAM
+
+	entries := ppu.ParseOAM()
+	if len(entries) != 40 {
+		t.Fatalf("len(ParseOAM()) = %d, want 40", len(entries))
+	}
+	if got := entries[0]; got != (OAMEntry{Y: 0x10, X: 0x08, Tile: 0x01}) {
+		t.Errorf("entry 0 = %+v, want {Y:16 X:8 Tile:1 Attrs:0}", got)
+	}
+	if got := entries[39]; got != (OAMEntry{Y: 0x90, X: 0xA8, Tile: 0xFF, Attrs: SpriteAttrYFlip}) {
+		t.Errorf("entry 39 = %+v, want {Y:144 X:168 Tile:255 Attrs:64}", got)
+	}
+}
@@ -39,6 +39,13 @@ const (
 	DotsPerFrame = 70224
 )
 
+const (
+	// DefaultSpriteLimit is the hardware limit of sprites drawn per scanline.
+	DefaultSpriteLimit = 10
+	// oamSpriteCount is the number of sprite entries in OAM.
+	oamSpriteCount = 40
+)
+
 const (
 	// VRAMSize is the size of VRAM in bytes (8KB).
 	VRAMSize = 0x2000
@@ -110,8 +117,13 @@ type sprite struct {
 // PPU represents the Game Boy Picture Processing Unit.
 type PPU struct {
 	// Video memory
-	vram [VRAMSize]uint8 // VRAM (0x8000-0x9FFF)
-	oam  [OAMSize]uint8  // Object Attribute Memory (0xFE00-0xFE9F)
+	// The renderer only uses bank 0; bank 1 holds CGB tiles and attributes.
+	vram [2][VRAMSize]uint8 // VRAM (0x8000-0x9FFF), banked by VBK
+	oam  [OAMSize]uint8     // Object Attribute Memory (0xFE00-0xFE9F)
+
+	// CGB VRAM bank select (VBK, 0xFF4F); only present in CGB mode
+	cgbMode bool
+	vbk     uint8
 
 	// Registers
 	lcdc uint8 // LCD Control (0xFF40)
@@ -127,16 +139,41 @@ type PPU struct {
 	wx   uint8 // Window X Position + 7 (0xFF4B)
 
 	// State
-	mode uint8  // Current PPU mode (0-3)
-	dots uint16 // Dot counter for current scanline
+	mode        uint8  // Current PPU mode (0-3)
+	dots        uint16 // Dot counter for current scanline
+	drawingDots uint16 // Duration of the current Mode 3 (lengthened by scrolling and sprites)
+	hblankDots  uint16 // Duration of the current H-Blank (shortened when Mode 3 is longer)
+	windowLine  uint8  // Window line counter: advances only on lines the window is drawn
 
 	// Framebuffer: 160x144 pixels, 2 bits per pixel (color index 0-3)
 	framebuffer [ScreenWidth * ScreenHeight]uint8
 
+	// BG/window color indices (before BGP mapping) for the current scanline,
+	// used for the sprite-behind-BG priority test
+	bgColorIndex [ScreenWidth]uint8
+
 	// Sprite buffer: pre-allocated buffer for sprite rendering (max 10 per scanline)
 	// Reused each scanline to reduce GC pressure
 	spriteBuffer []sprite
 
+	// Maximum sprites drawn per scanline (0 = unlimited)
+	spriteLimit int
+
+	// Layers hidden for debugging (see SetLayerMask)
+	hideBG      bool
+	hideWindow  bool
+	hideSprites bool
+
+	// VRAM/OAM accessible in every mode, for debugging (see SetAccessBlocking)
+	accessUnblocked bool
+
+	// Pixel FIFO renderer (optional, see fifo.go)
+	fifoEnabled bool
+	fifo        pixelFIFO
+
+	// Number of frames completed (incremented on V-Blank entry)
+	frameCount uint64
+
 	// Interrupt request callback
 	requestInterrupt func(interrupt uint8)
 }
@@ -148,7 +185,10 @@ func New(requestInterrupt func(uint8)) *PPU {
 		mode:             ModeOAMScan,
 		ly:               0,
 		dots:             0,
-		spriteBuffer:     make([]sprite, 0, 10), // Pre-allocate for 10 sprites
+		spriteBuffer:     make([]sprite, 0, oamSpriteCount), // Room for every OAM entry
+		spriteLimit:      DefaultSpriteLimit,
+		drawingDots:      DotsDrawing,
+		hblankDots:       DotsHBlank,
 	}
 
 	// Initialize registers to power-up state
@@ -168,7 +208,19 @@ func (p *PPU) Step(cycles uint8) {
 		return
 	}
 
-	p.dots += uint16(cycles)
+	// The FIFO renderer works one dot at a time
+	if p.fifoEnabled {
+		for range cycles {
+			p.advance(1)
+		}
+		return
+	}
+	p.advance(uint16(cycles))
+}
+
+// advance advances the PPU state machine by the given number of dots.
+func (p *PPU) advance(dots uint16) {
+	p.dots += dots
 
 	// Check if we need to transition modes or scanlines
 	switch p.mode {
@@ -176,23 +228,47 @@ func (p *PPU) Step(cycles uint8) {
 		if p.dots >= DotsOAMScan {
 			p.setMode(ModeDrawing)
 			p.dots -= DotsOAMScan
+			if p.fifoEnabled {
+				p.fifoStartLine()
+			} else {
+				p.drawingDots = p.mode3Length()
+			}
 		}
 
 	case ModeDrawing:
-		if p.dots >= DotsDrawing {
-			p.setMode(ModeHBlank)
-			p.dots -= DotsDrawing
-			// Render the current scanline
+		if p.fifoEnabled {
+			// The FIFO renderer draws during Mode 3 and decides when it ends
+			p.fifoTick()
+			if p.fifoDone() {
+				// H-Blank takes the rest of the scanline
+				p.hblankDots = 0
+				if p.fifo.dots < DotsPerScanline-DotsOAMScan {
+					p.hblankDots = DotsPerScanline - DotsOAMScan - p.fifo.dots
+				}
+				if p.fifo.window {
+					p.windowLine++
+				}
+				p.setMode(ModeHBlank)
+				p.dots = 0
+			}
+		} else if p.dots >= p.drawingDots {
+			// Render the current scanline before entering H-Blank, so a
+			// Mode 0 STAT handler's register writes only affect later lines
 			p.renderScanline()
+			p.setMode(ModeHBlank)
+			p.dots -= p.drawingDots
+			// H-Blank takes the rest of the scanline
+			p.hblankDots = DotsPerScanline - DotsOAMScan - p.drawingDots
 		}
 
 	case ModeHBlank:
-		if p.dots >= DotsHBlank {
-			p.dots -= DotsHBlank
+		if p.dots >= p.hblankDots {
+			p.dots -= p.hblankDots
 			p.ly++
 
 			if p.ly >= ScanlinesVisible {
 				// Enter V-Blank
+				p.frameCount++
 				p.setMode(ModeVBlank)
 				if p.requestInterrupt != nil {
 					p.requestInterrupt(InterruptVBlank)
@@ -211,6 +287,7 @@ func (p *PPU) Step(cycles uint8) {
 			if p.ly >= ScanlinesTotal {
 				// Start new frame
 				p.ly = 0
+				p.windowLine = 0
 				p.setMode(ModeOAMScan)
 			}
 		}
@@ -259,31 +336,53 @@ func (p *PPU) updateLYCFlag() {
 
 // ReadVRAM reads a byte from VRAM.
 func (p *PPU) ReadVRAM(addr uint16) uint8 {
-	// VRAM is inaccessible during mode 3 (drawing)
-	if p.mode == ModeDrawing {
+	if p.vramBlocked() {
 		return 0xFF
 	}
 	if addr < VRAMSize {
-		return p.vram[addr]
+		return p.vram[p.vbk][addr]
 	}
 	return 0xFF
 }
 
 // WriteVRAM writes a byte to VRAM.
 func (p *PPU) WriteVRAM(addr uint16, value uint8) {
-	// VRAM is inaccessible during mode 3 (drawing)
-	if p.mode == ModeDrawing {
+	if p.vramBlocked() {
 		return
 	}
 	if addr < VRAMSize {
-		p.vram[addr] = value
+		p.vram[p.vbk][addr] = value
+	}
+}
+
+// SetCGBMode enables the VBK register. In DMG mode VBK reads as 0xFF,
+// ignores writes and VRAM bank 0 is always selected.
+func (p *PPU) SetCGBMode(enabled bool) {
+	p.cgbMode = enabled
+	if !enabled {
+		p.vbk = 0
+	}
+}
+
+// ReadVBK reads the VBK register. Only bit 0 is implemented.
+func (p *PPU) ReadVBK() uint8 {
+	if !p.cgbMode {
+		return 0xFF
 	}
+	return 0xFE | p.vbk
+}
+
+// WriteVBK writes the VBK register, selecting the VRAM bank used by CPU accesses.
+func (p *PPU) WriteVBK(value uint8) {
+	if !p.cgbMode {
+		return
+	}
+	p.vbk = value & 0x01
 }
 
 // ReadOAM reads a byte from OAM.
 func (p *PPU) ReadOAM(addr uint16) uint8 {
-	// OAM is inaccessible during modes 2 (OAM scan) and 3 (drawing)
-	if p.mode == ModeOAMScan || p.mode == ModeDrawing {
+	if p.oamBlocked() {
 		return 0xFF
 	}
 	if addr < OAMSize {
@@ -294,8 +393,7 @@ func (p *PPU) ReadOAM(addr uint16) uint8 {
 
 // WriteOAM writes a byte to OAM.
 func (p *PPU) WriteOAM(addr uint16, value uint8) {
-	// OAM is inaccessible during modes 2 (OAM scan) and 3 (drawing)
-	if p.mode == ModeOAMScan || p.mode == ModeDrawing {
+	if p.oamBlocked() {
 		return
 	}
 	if addr < OAMSize {
@@ -303,13 +401,30 @@ func (p *PPU) WriteOAM(addr uint16, value uint8) {
 	}
 }
 
+// vramBlocked reports whether VRAM is inaccessible: during mode 3 (drawing),
+// unless access blocking is disabled.
+func (p *PPU) vramBlocked() bool {
+	return !p.accessUnblocked && p.mode == ModeDrawing
+}
+
+// oamBlocked reports whether OAM is inaccessible: during modes 2 (OAM scan)
+// and 3 (drawing), unless access blocking is disabled.
+func (p *PPU) oamBlocked() bool {
+	return !p.accessUnblocked && (p.mode == ModeOAMScan || p.mode == ModeDrawing)
+}
+
 // ReadRegister reads a PPU register.
 func (p *PPU) ReadRegister(addr uint16) uint8 {
 	switch addr {
 	case 0xFF40:
 		return p.lcdc
 	case 0xFF41:
-		return p.stat | 0x80 // Bit 7 is always 1
+		// Bit 7 is always 1. With the LCD off the PPU is idle, so the mode
+		// reads as 0 whatever state it was frozen in
+		if p.lcdc&LCDCLCDEnable == 0 {
+			return p.stat&^STATModeMask | 0x80
+		}
+		return p.stat | 0x80
 	case 0xFF42:
 		return p.scy
 	case 0xFF43:
@@ -369,9 +484,118 @@ func (p *PPU) GetFramebuffer() *[ScreenWidth * ScreenHeight]uint8 {
 	return &p.framebuffer
 }
 
+// LCDC returns the LCD control register (0xFF40).
+func (p *PPU) LCDC() uint8 {
+	return p.lcdc
+}
+
+// STAT returns the raw LCD status register (0xFF41), without the
+// always-set bit 7 that ReadRegister reports.
+func (p *PPU) STAT() uint8 {
+	return p.stat
+}
+
+// LY returns the current scanline (0xFF44).
+func (p *PPU) LY() uint8 {
+	return p.ly
+}
+
+// Mode returns the current PPU mode (ModeHBlank, ModeVBlank, ModeOAMScan or ModeDrawing).
+func (p *PPU) Mode() uint8 {
+	return p.mode
+}
+
+// ScrollX returns the background X scroll register (SCX, 0xFF43).
+func (p *PPU) ScrollX() uint8 {
+	return p.scx
+}
+
+// ScrollY returns the background Y scroll register (SCY, 0xFF42).
+func (p *PPU) ScrollY() uint8 {
+	return p.scy
+}
+
+// WindowX returns the window X position register (WX, 0xFF4B).
+func (p *PPU) WindowX() uint8 {
+	return p.wx
+}
+
+// WindowY returns the window Y position register (WY, 0xFF4A).
+func (p *PPU) WindowY() uint8 {
+	return p.wy
+}
+
+// BGP returns the background palette register (0xFF47).
+func (p *PPU) BGP() uint8 {
+	return p.bgp
+}
+
+// OBP0 returns object palette 0 (0xFF48).
+func (p *PPU) OBP0() uint8 {
+	return p.obp0
+}
+
+// OBP1 returns object palette 1 (0xFF49).
+func (p *PPU) OBP1() uint8 {
+	return p.obp1
+}
+
+// OAMSnapshot returns a copy of OAM, regardless of the current PPU mode.
+func (p *PPU) OAMSnapshot() [OAMSize]uint8 {
+	return p.oam
+}
+
+// VRAMSnapshot returns a copy of VRAM, regardless of the current PPU mode.
+// In CGB mode both banks are returned, bank 0 first.
+func (p *PPU) VRAMSnapshot() []byte {
+	banks := 1
+	if p.cgbMode {
+		banks = 2
+	}
+	data := make([]byte, 0, banks*VRAMSize)
+	for bank := range banks {
+		data = append(data, p.vram[bank][:]...)
+	}
+	return data
+}
+
+// FrameCount returns the number of frames completed since power-on.
+// A frame completes when the PPU enters V-Blank.
+func (p *PPU) FrameCount() uint64 {
+	return p.frameCount
+}
+
+// SetSpriteLimit sets the maximum number of sprites drawn per scanline.
+// The default is DefaultSpriteLimit, matching hardware. A limit of 0 removes
+// the limit, which reduces sprite flicker in some games but diverges from
+// hardware behavior.
+func (p *PPU) SetSpriteLimit(n int) {
+	if n < 0 {
+		n = 0
+	}
+	p.spriteLimit = n
+}
+
+// SetLayerMask selects which layers the scanline renderer draws, for
+// debugging rendering problems. A hidden background renders as color 0.
+// Timing is unaffected, and the FIFO renderer always draws all layers.
+func (p *PPU) SetLayerMask(bg, window, sprites bool) {
+	p.hideBG = !bg
+	p.hideWindow = !window
+	p.hideSprites = !sprites
+}
+
+// SetAccessBlocking selects whether CPU accesses to VRAM and OAM are blocked
+// during the PPU modes that use them. Blocking is enabled by default, as on
+// hardware; debugging tools can disable it to inspect memory in any mode.
+func (p *PPU) SetAccessBlocking(enabled bool) {
+	p.accessUnblocked = !enabled
+}
+
 // Reset resets the PPU to initial state.
 func (p *PPU) Reset() {
-	p.vram = [VRAMSize]uint8{}
+	p.vram = [2][VRAMSize]uint8{}
+	p.vbk = 0
 	p.oam = [OAMSize]uint8{}
 	p.lcdc = 0x91
 	p.stat = 0x00
@@ -384,7 +608,11 @@ func (p *PPU) Reset() {
 	p.obp1 = 0xFF
 	p.wy = 0
 	p.wx = 0
+	p.windowLine = 0
 	p.mode = ModeOAMScan
 	p.dots = 0
+	p.drawingDots = DotsDrawing
+	p.hblankDots = DotsHBlank
 	p.framebuffer = [ScreenWidth * ScreenHeight]uint8{}
+	p.bgColorIndex = [ScreenWidth]uint8{}
 }
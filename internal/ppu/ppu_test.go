@@ -183,6 +183,33 @@ func TestPPURegisterReadWrite(t *testing.T) {
 	}
 }
 
+// TestPPUSTATModeLCDOff tests that STAT reports mode 0 while the LCD is off.
+func TestPPUSTATModeLCDOff(t *testing.T) {
+	ppu := New(nil)
+	ppu.WriteRegister(0xFF41, STATLYCInterrupt)
+
+	// Run into mode 3 and turn the LCD off, freezing the PPU there
+	stepMany(ppu, DotsOAMScan+1)
+	if ppu.Mode() != ModeDrawing {
+		t.Fatalf("Setup failed: mode = %d, want %d (Drawing)", ppu.Mode(), ModeDrawing)
+	}
+	ppu.WriteRegister(0xFF40, ppu.LCDC()&^LCDCLCDEnable)
+
+	got := ppu.ReadRegister(0xFF41)
+	if got&STATModeMask != ModeHBlank {
+		t.Errorf("STAT mode with LCD off = %d, want 0", got&STATModeMask)
+	}
+	if got&0x80 == 0 || got&STATLYCInterrupt == 0 {
+		t.Errorf("STAT with LCD off = 0x%02X, want bit 7 and the interrupt enables kept", got)
+	}
+
+	// Turning the LCD back on reports the live mode again
+	ppu.WriteRegister(0xFF40, ppu.LCDC()|LCDCLCDEnable)
+	if got := ppu.ReadRegister(0xFF41) & STATModeMask; got != ModeDrawing {
+		t.Errorf("STAT mode with LCD on = %d, want %d (Drawing)", got, ModeDrawing)
+	}
+}
+
 // TestPPULYReadOnly tests that LY register is read-only (writes reset to 0).
 func TestPPULYReadOnly(t *testing.T) {
 	ppu := New(nil)
@@ -229,6 +256,41 @@ func TestPPUVRAMAccess(t *testing.T) {
 	}
 }
 
+// TestPPUAccessBlockingDisabled tests that VRAM and OAM are accessible in
+// every mode when access blocking is disabled.
+func TestPPUAccessBlockingDisabled(t *testing.T) {
+	ppu := New(nil)
+	ppu.mode = ModeHBlank
+	ppu.WriteVRAM(0x0000, 0x42)
+	ppu.WriteOAM(0x00, 0x12)
+
+	ppu.SetAccessBlocking(false)
+	for _, mode := range []uint8{ModeOAMScan, ModeDrawing} {
+		ppu.mode = mode
+		if got := ppu.ReadVRAM(0x0000); got != 0x42 {
+			t.Errorf("mode %d: VRAM read = 0x%02X, want 0x42", mode, got)
+		}
+		if got := ppu.ReadOAM(0x00); got != 0x12 {
+			t.Errorf("mode %d: OAM read = 0x%02X, want 0x12", mode, got)
+		}
+	}
+
+	ppu.WriteVRAM(0x0001, 0x43)
+	ppu.WriteOAM(0x01, 0x13)
+	if ppu.vram[0][0x0001] != 0x43 || ppu.oam[0x01] != 0x13 {
+		t.Errorf("writes in Drawing mode = 0x%02X/0x%02X, want 0x43/0x13", ppu.vram[0][0x0001], ppu.oam[0x01])
+	}
+
+	// Re-enabled blocking applies again
+	ppu.SetAccessBlocking(true)
+	if got := ppu.ReadVRAM(0x0000); got != 0xFF {
+		t.Errorf("VRAM read with blocking = 0x%02X, want 0xFF", got)
+	}
+	if got := ppu.ReadOAM(0x00); got != 0xFF {
+		t.Errorf("OAM read with blocking = 0x%02X, want 0xFF", got)
+	}
+}
+
 // TestPPUOAMAccess tests OAM read/write.
 func TestPPUOAMAccess(t *testing.T) {
 	ppu := New(nil)
@@ -261,6 +323,119 @@ func TestPPUOAMAccess(t *testing.T) {
 	}
 }
 
+// TestPPUInspectorAccessors tests the read-only LCDC, STAT, LY and OAM accessors.
+func TestPPUInspectorAccessors(t *testing.T) {
+	ppu := New(nil)
+
+	ppu.WriteRegister(0xFF40, 0x83)
+	if got := ppu.LCDC(); got != 0x83 {
+		t.Errorf("LCDC() = 0x%02X, want 0x83", got)
+	}
+
+	// STAT is returned raw, without the always-set bit 7
+	ppu.WriteRegister(0xFF41, 0x40)
+	if got, want := ppu.STAT(), ppu.ReadRegister(0xFF41)&0x7F; got != want {
+		t.Errorf("STAT() = 0x%02X, want 0x%02X", got, want)
+	}
+	if got := ppu.STAT(); got&0x80 != 0 {
+		t.Errorf("STAT() = 0x%02X, want bit 7 clear", got)
+	}
+
+	stepMany(ppu, DotsPerScanline*4)
+	if got, want := ppu.LY(), ppu.ReadRegister(0xFF44); got != want || got == 0 {
+		t.Errorf("LY() = %d, want %d (non-zero)", got, want)
+	}
+
+	// The snapshot is readable even when the CPU is blocked from OAM
+	ppu.mode = ModeHBlank
+	ppu.WriteOAM(0x00, 0x10)
+	ppu.WriteOAM(OAMSize-1, 0x20)
+	ppu.mode = ModeDrawing
+	oam := ppu.OAMSnapshot()
+	if oam[0] != 0x10 || oam[OAMSize-1] != 0x20 {
+		t.Errorf("OAMSnapshot() [0] = 0x%02X, [%d] = 0x%02X, want 0x10, 0x20", oam[0], OAMSize-1, oam[OAMSize-1])
+	}
+
+	// The snapshot is a copy
+	oam[0] = 0xFF
+	if got := ppu.OAMSnapshot()[0]; got != 0x10 {
+		t.Errorf("OAMSnapshot()[0] after modifying copy = 0x%02X, want 0x10", got)
+	}
+}
+
+// TestPPUVRAMSnapshot tests that the VRAM snapshot matches bytes written in H-Blank.
+func TestPPUVRAMSnapshot(t *testing.T) {
+	ppu := New(nil)
+
+	// Run to the first H-Blank, when the CPU can write VRAM
+	for ppu.Mode() != ModeHBlank {
+		ppu.Step(4)
+	}
+	ppu.WriteVRAM(0x0000, 0x12)
+	ppu.WriteVRAM(0x1234, 0x56)
+	ppu.WriteVRAM(VRAMSize-1, 0x78)
+
+	// The snapshot is readable even when the CPU is blocked from VRAM
+	ppu.mode = ModeDrawing
+	vram := ppu.VRAMSnapshot()
+	if len(vram) != VRAMSize {
+		t.Fatalf("len(VRAMSnapshot()) = %d, want %d", len(vram), VRAMSize)
+	}
+	for addr, want := range map[int]uint8{0x0000: 0x12, 0x1234: 0x56, VRAMSize - 1: 0x78, 0x0001: 0x00} {
+		if vram[addr] != want {
+			t.Errorf("VRAMSnapshot()[0x%04X] = 0x%02X, want 0x%02X", addr, vram[addr], want)
+		}
+	}
+
+	// The snapshot is a copy
+	vram[0] = 0xFF
+	if got := ppu.VRAMSnapshot()[0]; got != 0x12 {
+		t.Errorf("VRAMSnapshot()[0] after modifying copy = 0x%02X, want 0x12", got)
+	}
+
+	// CGB mode adds bank 1 after bank 0
+	ppu.SetCGBMode(true)
+	if got := len(ppu.VRAMSnapshot()); got != 2*VRAMSize {
+		t.Errorf("len(VRAMSnapshot()) in CGB mode = %d, want %d", got, 2*VRAMSize)
+	}
+}
+
+// TestPPURegisterAccessors tests that the accessors reflect register writes.
+func TestPPURegisterAccessors(t *testing.T) {
+	ppu := New(nil)
+
+	tests := []struct {
+		name  string
+		addr  uint16
+		value uint8
+		get   func() uint8
+	}{
+		{"ScrollY", 0xFF42, 0x12, ppu.ScrollY},
+		{"ScrollX", 0xFF43, 0x34, ppu.ScrollX},
+		{"BGP", 0xFF47, 0xE4, ppu.BGP},
+		{"OBP0", 0xFF48, 0xD2, ppu.OBP0},
+		{"OBP1", 0xFF49, 0xA0, ppu.OBP1},
+		{"WindowY", 0xFF4A, 0x50, ppu.WindowY},
+		{"WindowX", 0xFF4B, 0x07, ppu.WindowX},
+	}
+
+	for _, tt := range tests {
+		ppu.WriteRegister(tt.addr, tt.value)
+		if got := tt.get(); got != tt.value {
+			t.Errorf("%s() = 0x%02X, want 0x%02X", tt.name, got, tt.value)
+		}
+	}
+
+	// Mode matches the STAT mode bits
+	if got := ppu.Mode(); got != ModeOAMScan {
+		t.Errorf("Mode() = %d, want %d (OAM Scan)", got, ModeOAMScan)
+	}
+	stepMany(ppu, DotsOAMScan)
+	if got := ppu.Mode(); got != ModeDrawing || got != ppu.STAT()&STATModeMask {
+		t.Errorf("Mode() = %d, want %d (Drawing) matching STAT", got, ModeDrawing)
+	}
+}
+
 // TestPPULYCFlag tests LYC=LY flag and interrupt.
 func TestPPULYCFlag(t *testing.T) {
 	interruptCount := 0
@@ -306,6 +481,50 @@ func TestPPULYCFlag(t *testing.T) {
 	}
 }
 
+// TestPPUHBlankInterruptSCXSplit tests that the Mode 0 STAT interrupt fires
+// once each visible line has been rendered, so a handler changing SCX splits
+// the screen at the next line.
+func TestPPUHBlankInterruptSCXSplit(t *testing.T) {
+	for _, fifo := range []bool{false, true} {
+		var p *PPU
+		var lines []uint8
+		p = New(func(interrupt uint8) {
+			if interrupt != InterruptSTAT {
+				return
+			}
+			if p.mode != ModeHBlank {
+				t.Errorf("fifo=%v: STAT interrupt in mode %d, want H-Blank", fifo, p.mode)
+			}
+			lines = append(lines, p.ly)
+
+			// The handler runs as soon as the interrupt is requested
+			p.WriteRegister(0xFF43, (p.ly+1)%4)
+		})
+		p.SetFIFORenderer(fifo)
+		setupFIFOBackground(p)
+		p.stat = STATMode0Interrupt
+
+		for p.FrameCount() == 0 {
+			p.Step(4)
+		}
+
+		if len(lines) != ScanlinesVisible {
+			t.Fatalf("fifo=%v: %d H-Blank interrupts, want %d", fifo, len(lines), ScanlinesVisible)
+		}
+		for line := range ScanlinesVisible {
+			if lines[line] != uint8(line) { //nolint:gosec // G115: line < 144
+				t.Errorf("fifo=%v: interrupt %d on LY %d, want %d", fifo, line, lines[line], line)
+			}
+
+			// Line n was drawn with SCX = n%4, set in the previous line's
+			// H-Blank, so its first pixel shows color n%4
+			if got, want := p.framebuffer[line*ScreenWidth], uint8(line%4); got != want { //nolint:gosec // G115: line%4 fits in a byte
+				t.Errorf("fifo=%v: line %d first pixel = %d, want %d", fifo, line, got, want)
+			}
+		}
+	}
+}
+
 // TestPPUReset tests PPU reset functionality.
 func TestPPUReset(t *testing.T) {
 	ppu := New(nil)
@@ -363,11 +582,11 @@ func TestGetTilePixel(t *testing.T) {
 	// Tile at address 0x0000 in VRAM
 	// Each row is 2 bytes: byte1 (low bit) and byte2 (high bit)
 	// Pattern: alternating pixels (color 0 and color 3)
-	ppu.vram[0x0000] = 0xAA // 10101010
-	ppu.vram[0x0001] = 0xAA // 10101010 -> pixels: 3,0,3,0,3,0,3,0
+	ppu.vram[0][0x0000] = 0xAA // 10101010
+	ppu.vram[0][0x0001] = 0xAA // 10101010 -> pixels: 3,0,3,0,3,0,3,0
 
-	ppu.vram[0x0002] = 0x55 // 01010101
-	ppu.vram[0x0003] = 0x55 // 01010101 -> pixels: 0,3,0,3,0,3,0,3
+	ppu.vram[0][0x0002] = 0x55 // 01010101
+	ppu.vram[0][0x0003] = 0x55 // 01010101 -> pixels: 0,3,0,3,0,3,0,3
 
 	// Test first row (y=0)
 	tests := []struct {
@@ -470,3 +689,75 @@ func TestGetFramebuffer(t *testing.T) {
 		}
 	}
 }
+
+func TestVBKDMGMode(t *testing.T) {
+	ppu := New(nil)
+	ppu.SetModeForTesting(ModeHBlank)
+
+	ppu.WriteVBK(0x01)
+	if got := ppu.ReadVBK(); got != 0xFF {
+		t.Errorf("DMG VBK = 0x%02X, want 0xFF", got)
+	}
+
+	ppu.WriteVRAM(0x0010, 0x42)
+	if ppu.vram[0][0x0010] != 0x42 {
+		t.Errorf("vram[0][0x0010] = 0x%02X, want 0x42", ppu.vram[0][0x0010])
+	}
+	if ppu.vram[1][0x0010] != 0x00 {
+		t.Errorf("vram[1][0x0010] = 0x%02X, want 0x00", ppu.vram[1][0x0010])
+	}
+}
+
+func TestVBKBankSwitching(t *testing.T) {
+	ppu := New(nil)
+	ppu.SetCGBMode(true)
+	ppu.SetModeForTesting(ModeHBlank)
+
+	if got := ppu.ReadVBK(); got != 0xFE {
+		t.Errorf("VBK = 0x%02X, want 0xFE", got)
+	}
+
+	ppu.WriteVRAM(0x0010, 0x11)
+	ppu.WriteVBK(0xFF) // Only bit 0 is used
+	if got := ppu.ReadVBK(); got != 0xFF {
+		t.Errorf("VBK = 0x%02X, want 0xFF", got)
+	}
+	ppu.WriteVRAM(0x0010, 0x22)
+
+	if ppu.vram[0][0x0010] != 0x11 {
+		t.Errorf("vram[0][0x0010] = 0x%02X, want 0x11", ppu.vram[0][0x0010])
+	}
+	if ppu.vram[1][0x0010] != 0x22 {
+		t.Errorf("vram[1][0x0010] = 0x%02X, want 0x22", ppu.vram[1][0x0010])
+	}
+	if got := ppu.ReadVRAM(0x0010); got != 0x22 {
+		t.Errorf("ReadVRAM(0x0010) in bank 1 = 0x%02X, want 0x22", got)
+	}
+
+	ppu.WriteVBK(0x00)
+	if got := ppu.ReadVRAM(0x0010); got != 0x11 {
+		t.Errorf("ReadVRAM(0x0010) in bank 0 = 0x%02X, want 0x11", got)
+	}
+}
+
+func TestVBKRenderingUsesBank0(t *testing.T) {
+	ppu := New(nil)
+	ppu.SetCGBMode(true)
+	ppu.lcdc = LCDCLCDEnable | LCDCBGWindowEnable | LCDCBGTileData
+	ppu.bgp = 0xE4 // Identity palette
+	ppu.ly = 0
+
+	// Tile 0 is blank in bank 0 and solid in bank 1
+	for i := range 16 {
+		ppu.vram[1][i] = 0xFF
+	}
+	ppu.WriteVBK(0x01)
+
+	ppu.renderScanline()
+
+	for x := range ScreenWidth {
+		if got := ppu.framebuffer[x]; got != 0 {
+			t.Fatalf("framebuffer[%d] = %d, want 0 (bank 1 must not be rendered)", x, got)
+		}
+	}
+}
@@ -9,7 +9,7 @@ func (p *PPU) renderScanline() {
 	}
 
 	// Render background if enabled
-	if p.lcdc&LCDCBGWindowEnable != 0 {
+	if p.lcdc&LCDCBGWindowEnable != 0 && !p.hideBG {
 		p.renderBackground()
 	} else {
 		// If BG is disabled, fill with white (color 0)
@@ -17,12 +17,12 @@ func (p *PPU) renderScanline() {
 	}
 
 	// Render window if enabled
-	if p.lcdc&LCDCWindowEnable != 0 {
+	if p.lcdc&LCDCWindowEnable != 0 && !p.hideWindow {
 		p.renderWindow()
 	}
 
 	// Render sprites if enabled
-	if p.lcdc&LCDCOBJEnable != 0 {
+	if p.lcdc&LCDCOBJEnable != 0 && !p.hideSprites {
 		p.renderSprites()
 	}
 }
@@ -32,6 +32,7 @@ func (p *PPU) clearScanline() {
 	offset := int(p.ly) * ScreenWidth
 	for x := 0; x < ScreenWidth; x++ {
 		p.framebuffer[offset+x] = 0
+		p.bgColorIndex[x] = 0
 	}
 }
 
@@ -62,7 +63,7 @@ func (p *PPU) renderBackground() {
 
 		// Get tile index from tile map
 		tileMapAddr := tileMapBase + (tileRow * 32) + tileCol
-		tileIndex := p.vram[tileMapAddr]
+		tileIndex := p.vram[0][tileMapAddr]
 
 		// Calculate tile data address
 		tileAddr := p.getTileDataAddr(tileIndex, useSigned, tileDataBase)
@@ -77,15 +78,21 @@ func (p *PPU) renderBackground() {
 		// Apply background palette
 		color := p.applyPalette(colorIndex, p.bgp)
 
-		// Write to framebuffer
+		// Write to framebuffer, keeping the color index for sprite priority
 		p.framebuffer[int(p.ly)*ScreenWidth+int(x)] = color
+		p.bgColorIndex[x] = colorIndex
 	}
 }
 
+// windowXHidden is the lowest WX that places the window entirely off-screen.
+// WX 166 still shows the window's first column at screen X 159.
+const windowXHidden = 167
+
 // renderWindow renders the window layer for the current scanline.
+// WX 0-6 start the window left of the screen, hiding its first 7-WX columns.
 func (p *PPU) renderWindow() {
 	// Window must be visible on this scanline
-	if p.ly < p.wy {
+	if p.ly < p.wy || p.wx >= windowXHidden {
 		return
 	}
 
@@ -102,15 +109,13 @@ func (p *PPU) renderWindow() {
 		tileDataBase = 0x0800
 	}
 
-	// Calculate window Y coordinate (no scrolling)
-	windowY := uint16(p.ly) - uint16(p.wy)
+	// Window Y comes from the window line counter (no scrolling)
+	windowY := uint16(p.windowLine)
+	p.windowLine++
 	tileRow := (windowY / 8) % 32
 
-	// Window X position is offset by 7
+	// Window X position is offset by 7 (negative for WX 0-6)
 	windowXOffset := int16(p.wx) - 7
-	if windowXOffset < 0 {
-		windowXOffset = 0
-	}
 
 	// Render each pixel of the window on this scanline
 	for x := uint16(0); x < ScreenWidth; x++ {
@@ -124,7 +129,7 @@ func (p *PPU) renderWindow() {
 
 		// Get tile index from window tile map
 		tileMapAddr := tileMapBase + (tileRow * 32) + tileCol
-		tileIndex := p.vram[tileMapAddr]
+		tileIndex := p.vram[0][tileMapAddr]
 
 		// Calculate tile data address
 		tileAddr := p.getTileDataAddr(tileIndex, useSigned, tileDataBase)
@@ -139,8 +144,9 @@ func (p *PPU) renderWindow() {
 		// Apply background palette
 		color := p.applyPalette(colorIndex, p.bgp)
 
-		// Write to framebuffer
+		// Write to framebuffer, keeping the color index for sprite priority
 		p.framebuffer[int(p.ly)*ScreenWidth+int(x)] = color
+		p.bgColorIndex[x] = colorIndex
 	}
 }
 
@@ -153,62 +159,16 @@ func (p *PPU) renderSprites() {
 		spriteHeight = 16
 	}
 
-	// Reset sprite buffer (reuse allocation to reduce GC pressure)
-	p.spriteBuffer = p.spriteBuffer[:0]
-
-	// Scan OAM for sprites on this scanline
-	for i := 0; i < 40; i++ {
-		oamAddr := i * 4
-
-		y := int16(p.oam[oamAddr]) - 16
-		x := int16(p.oam[oamAddr+1]) - 8
-		tileIndex := p.oam[oamAddr+2]
-		attrs := p.oam[oamAddr+3]
-
-		// Check if sprite is on this scanline
-		scanline := int16(p.ly)
-		if scanline >= y && scanline < y+int16(spriteHeight) { //nolint:gosec // Intentional conversion
-			p.spriteBuffer = append(p.spriteBuffer, sprite{
-				x:         x,
-				y:         y,
-				tileIndex: tileIndex,
-				attrs:     attrs,
-				oamIndex:  i,
-			})
-
-			// Max 10 sprites per scanline
-			if len(p.spriteBuffer) >= 10 {
-				break
-			}
-		}
-	}
-
-	// Render sprites in reverse order (higher priority last)
-	for i := len(p.spriteBuffer) - 1; i >= 0; i-- {
-		spr := p.spriteBuffer[i]
-
-		// Calculate which line of the sprite to render
-		spriteLine := uint16(int16(p.ly) - spr.y) //nolint:gosec // Intentional conversion
+	p.scanSprites(spriteHeight)
 
-		// Apply Y flip
-		if spr.attrs&SpriteAttrYFlip != 0 {
-			spriteLine = spriteHeight - 1 - spriteLine
-		}
+	// Pixels already claimed by a higher-priority sprite
+	var claimed [ScreenWidth]bool
 
-		// For 8x16 sprites, use two tiles
-		tileIndex := uint16(spr.tileIndex)
-		if spriteHeight == 16 {
-			// In 8x16 mode, bit 0 is ignored
-			tileIndex &= 0xFE
-			// Use second tile for bottom half
-			if spriteLine >= 8 {
-				tileIndex++
-				spriteLine -= 8
-			}
-		}
+	// Render sprites in priority order; the first opaque sprite pixel wins,
+	// even when its BG priority flag then hides it behind the background
+	for _, spr := range p.spriteBuffer {
 
-		// Get tile data address (sprites always use 0x8000 addressing)
-		tileAddr := tileIndex * 16
+		tileAddr, spriteLine := p.spriteRow(spr, spriteHeight)
 
 		// Render each pixel of the sprite
 		for x := uint16(0); x < 8; x++ {
@@ -229,13 +189,13 @@ func (p *PPU) renderSprites() {
 			colorIndex := p.getTilePixel(tileAddr, tileX, spriteLine)
 
 			// Color 0 is transparent for sprites
-			if colorIndex == 0 {
+			if colorIndex == 0 || claimed[pixelX] {
 				continue
 			}
+			claimed[pixelX] = true
 
-			// Check sprite priority
-			bgColor := p.framebuffer[int(p.ly)*ScreenWidth+int(pixelX)]
-			if spr.attrs&SpriteAttrPriority != 0 && bgColor != 0 {
+			// Check sprite priority against the BG color index, not the shade
+			if spr.attrs&SpriteAttrPriority != 0 && p.bgColorIndex[pixelX] != 0 {
 				// Sprite is behind BG colors 1-3
 				continue
 			}
@@ -253,6 +213,65 @@ func (p *PPU) renderSprites() {
 	}
 }
 
+// spriteRow returns the tile data address and tile row of spr to draw on the
+// current scanline, applying Y flip and 8x16 tile selection.
+func (p *PPU) spriteRow(spr sprite, spriteHeight uint16) (tileAddr, spriteLine uint16) {
+	// Calculate which line of the sprite to render
+	spriteLine = uint16(int16(p.ly) - spr.y) //nolint:gosec // Intentional conversion
+
+	// Apply Y flip
+	if spr.attrs&SpriteAttrYFlip != 0 {
+		spriteLine = spriteHeight - 1 - spriteLine
+	}
+
+	// For 8x16 sprites, use two tiles
+	tileIndex := uint16(spr.tileIndex)
+	if spriteHeight == 16 {
+		// In 8x16 mode, bit 0 is ignored
+		tileIndex &= 0xFE
+		// Use second tile for bottom half
+		if spriteLine >= 8 {
+			tileIndex++
+			spriteLine -= 8
+		}
+	}
+
+	// Get tile data address (sprites always use 0x8000 addressing)
+	return tileIndex * 16, spriteLine
+}
+
+// scanSprites fills the sprite buffer with the sprites on the current scanline,
+// in OAM order, up to the sprite limit (see SetSpriteLimit). Sprites off the
+// screen horizontally still take a slot, as on hardware; the FIFO renderer
+// drops them and reorders the rest by X when it fetches.
+func (p *PPU) scanSprites(spriteHeight uint16) {
+	// Reset sprite buffer (reuse allocation to reduce GC pressure)
+	p.spriteBuffer = p.spriteBuffer[:0]
+
+	// Scan OAM for sprites on this scanline
+	for i := 0; i < oamSpriteCount; i++ {
+		entry := p.oamEntry(i)
+		y := entry.ScreenY()
+
+		// Check if sprite is on this scanline
+		scanline := int16(p.ly)
+		if scanline >= y && scanline < y+int16(spriteHeight) { //nolint:gosec // Intentional conversion
+			p.spriteBuffer = append(p.spriteBuffer, sprite{
+				x:         entry.ScreenX(),
+				y:         y,
+				tileIndex: entry.Tile,
+				attrs:     entry.Attrs,
+				oamIndex:  i,
+			})
+
+			// Stop at the sprite limit; later OAM entries are not selected
+			if p.spriteLimit > 0 && len(p.spriteBuffer) >= p.spriteLimit {
+				break
+			}
+		}
+	}
+}
+
 // getTileDataAddr calculates the address of tile data.
 func (p *PPU) getTileDataAddr(tileIndex uint8, useSigned bool, base uint16) uint16 {
 	if useSigned {
@@ -271,8 +290,8 @@ func (p *PPU) getTilePixel(tileAddr, x, y uint16) uint8 {
 	lineAddr := tileAddr + (y * 2)
 
 	// Get the two bytes for this line
-	byte1 := p.vram[lineAddr]
-	byte2 := p.vram[lineAddr+1]
+	byte1 := p.vram[0][lineAddr]
+	byte2 := p.vram[0][lineAddr+1]
 
 	// Extract the bit for this pixel (bit 7 is pixel 0, bit 0 is pixel 7)
 	bitPos := 7 - x
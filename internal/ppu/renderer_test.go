@@ -0,0 +1,322 @@
+package ppu
+
+import "testing"
+
+// setupSpriteLine fills tile 1 with color 3 and places count 8x8 sprites on
+// scanline 0, side by side starting at screen X 0.
+func setupSpriteLine(p *PPU, count int) {
+	for i := 16; i < 32; i++ {
+		p.vram[0][i] = 0xFF // Tile 1: all pixels color 3
+	}
+
+	p.lcdc = LCDCLCDEnable | LCDCOBJEnable
+	p.obp0 = 0xE4 // Identity palette
+	p.ly = 0
+
+	for i := 0; i < count; i++ {
+		oamAddr := i * 4
+		p.oam[oamAddr] = 16               // Y = 0 on screen
+		p.oam[oamAddr+1] = uint8(8 + i*8) //nolint:gosec // Test values are small
+		p.oam[oamAddr+2] = 1              // Tile 1
+		p.oam[oamAddr+3] = 0
+	}
+}
+
+// countDrawnSprites returns how many of count side-by-side sprites were drawn on scanline 0.
+func countDrawnSprites(p *PPU, count int) int {
+	drawn := 0
+	for i := 0; i < count; i++ {
+		if p.framebuffer[i*8] == 3 {
+			drawn++
+		}
+	}
+	return drawn
+}
+
+// TestSpriteLimitDefault tests that only the first 10 sprites in OAM order are drawn.
+func TestSpriteLimitDefault(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 11)
+
+	ppu.renderScanline()
+
+	if got := countDrawnSprites(ppu, 11); got != DefaultSpriteLimit {
+		t.Errorf("Drawn sprites = %d, want %d", got, DefaultSpriteLimit)
+	}
+
+	// The 11th sprite in OAM order is the one dropped
+	if got := ppu.framebuffer[10*8]; got != 0 {
+		t.Errorf("Pixel of 11th sprite = %d, want 0 (not drawn)", got)
+	}
+}
+
+// TestSpriteLimitUnlimited tests that a limit of 0 draws every sprite on the line.
+func TestSpriteLimitUnlimited(t *testing.T) {
+	ppu := New(nil)
+	ppu.SetSpriteLimit(0)
+	setupSpriteLine(ppu, 20)
+
+	ppu.renderScanline()
+
+	if got := countDrawnSprites(ppu, 20); got != 20 {
+		t.Errorf("Drawn sprites = %d, want 20", got)
+	}
+}
+
+// TestSpriteLimitCustom tests that a custom limit is honored.
+func TestSpriteLimitCustom(t *testing.T) {
+	ppu := New(nil)
+	ppu.SetSpriteLimit(3)
+	setupSpriteLine(ppu, 11)
+
+	ppu.renderScanline()
+
+	if got := countDrawnSprites(ppu, 11); got != 3 {
+		t.Errorf("Drawn sprites = %d, want 3", got)
+	}
+}
+
+// TestSpriteOAMPriority tests that overlapping sprites are drawn with the lower OAM index on top.
+func TestSpriteOAMPriority(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 2)
+	ppu.obp1 = 0x54 // Maps color 3 to shade 1
+
+	// Stack both sprites at X 0; sprite 0 uses OBP0, sprite 1 uses OBP1
+	ppu.oam[1] = 8
+	ppu.oam[5] = 8
+	ppu.oam[7] = SpriteAttrPalette
+
+	ppu.renderScanline()
+
+	if got := ppu.framebuffer[0]; got != 3 {
+		t.Errorf("Overlapping pixel = %d, want 3 (OAM sprite 0 on top)", got)
+	}
+}
+
+// TestSpriteBGPriorityUsesColorIndex tests that the sprite-behind-BG check uses
+// the BG color index rather than the shade BGP maps it to.
+func TestSpriteBGPriorityUsesColorIndex(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 1)
+	ppu.lcdc |= LCDCBGWindowEnable | LCDCBGTileData
+	ppu.bgp = 0x03  // Maps BG color 0 (blank tile 0) to shade 3
+	ppu.obp0 = 0x40 // Maps sprite color 3 to shade 1
+	ppu.oam[3] = SpriteAttrPriority
+
+	ppu.renderScanline()
+
+	if got := ppu.framebuffer[0]; got != 1 {
+		t.Errorf("Sprite over BG color 0 = %d, want 1 (sprite drawn)", got)
+	}
+	if got := ppu.framebuffer[8]; got != 3 {
+		t.Errorf("BG pixel = %d, want 3", got)
+	}
+}
+
+// TestBGColorIndexBuffer tests that the background and window record their
+// pre-palette color indices for the scanline.
+func TestBGColorIndexBuffer(t *testing.T) {
+	ppu := New(nil)
+	// Tile 0 row 0: pixels 0-3 color 1, pixels 4-7 color 2
+	ppu.vram[0][0] = 0xF0
+	ppu.vram[0][1] = 0x0F
+	// Tile 1: all pixels color 3
+	for i := 16; i < 32; i++ {
+		ppu.vram[0][i] = 0xFF
+	}
+	for i := range 32 {
+		ppu.vram[0][0x1C00+i] = 1
+	}
+	ppu.lcdc = LCDCLCDEnable | LCDCBGWindowEnable | LCDCBGTileData |
+		LCDCWindowEnable | LCDCWindowTileMap
+	ppu.bgp = 0x1B // Reverses the shades
+	ppu.wy = 0
+	ppu.wx = 7 + 80
+
+	ppu.renderScanline()
+
+	tests := []struct {
+		x         int
+		wantIndex uint8
+		wantShade uint8
+	}{
+		{0, 1, 2},
+		{4, 2, 1},
+		{80, 3, 0}, // Window
+	}
+	for _, tt := range tests {
+		if got := ppu.bgColorIndex[tt.x]; got != tt.wantIndex {
+			t.Errorf("bgColorIndex[%d] = %d, want %d", tt.x, got, tt.wantIndex)
+		}
+		if got := ppu.framebuffer[tt.x]; got != tt.wantShade {
+			t.Errorf("framebuffer[%d] = %d, want %d", tt.x, got, tt.wantShade)
+		}
+	}
+}
+
+// TestSpriteOverlapBGPriority tests that a higher-priority sprite hidden behind
+// the background still masks lower-priority sprites under it.
+func TestSpriteOverlapBGPriority(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 2)
+	for i := 32; i < 48; i++ {
+		ppu.vram[0][i] = 0xFF // Tile 2: all pixels color 3
+	}
+	ppu.vram[0][0x1800] = 2 // BG tile at X 0-7 is solid color 3
+	ppu.lcdc |= LCDCBGWindowEnable | LCDCBGTileData
+	ppu.bgp = 0x54  // Maps BG color 3 to shade 1
+	ppu.obp0 = 0xE4 // Sprite 0 draws shade 3
+	ppu.obp1 = 0x80 // Sprite 1 draws shade 2
+
+	// Stack both sprites at X 0; sprite 0 is behind BG, sprite 1 is not
+	ppu.oam[1] = 8
+	ppu.oam[3] = SpriteAttrPriority
+	ppu.oam[5] = 8
+	ppu.oam[7] = SpriteAttrPalette
+
+	ppu.renderScanline()
+
+	if got := ppu.framebuffer[0]; got != 1 {
+		t.Errorf("Overlapping pixel = %d, want 1 (BG, sprite 1 masked by sprite 0)", got)
+	}
+}
+
+// TestSpriteWindowPriorityUsesColorIndex tests that sprites behind the window
+// only hide under window colors 1-3, whatever shade they map to.
+func TestSpriteWindowPriorityUsesColorIndex(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 2)
+	for i := 32; i < 48; i++ {
+		ppu.vram[0][i] = 0xFF // Tile 2: all pixels color 3
+	}
+	ppu.vram[0][0x1C00+1] = 2 // Window tile at X 8-15 is solid color 3
+	ppu.lcdc |= LCDCBGWindowEnable | LCDCBGTileData | LCDCWindowEnable | LCDCWindowTileMap
+	ppu.bgp = 0x03  // Maps color 0 to shade 3 and color 3 to shade 0
+	ppu.obp0 = 0x80 // Maps sprite color 3 to shade 2
+	ppu.wy = 0
+	ppu.wx = 7
+	ppu.oam[3] = SpriteAttrPriority
+	ppu.oam[7] = SpriteAttrPriority
+
+	ppu.renderScanline()
+
+	if got := ppu.framebuffer[0]; got != 2 {
+		t.Errorf("Sprite over window color 0 = %d, want 2 (sprite drawn)", got)
+	}
+	if got := ppu.framebuffer[8]; got != 0 {
+		t.Errorf("Sprite over window color 3 = %d, want 0 (window shade)", got)
+	}
+}
+
+// TestWindowXEdgeCases tests the first visible window column for WX values
+// left of, at and right of the screen edges.
+func TestWindowXEdgeCases(t *testing.T) {
+	tests := []struct {
+		wx        uint8
+		want      map[int]uint8 // Screen X -> color index
+		wantLines uint8         // Window line counter after the scanline
+	}{
+		{0, map[int]uint8{0: 3, 1: 0, 159: 0}, 1},     // Window column 7 at X 0
+		{7, map[int]uint8{0: 0, 7: 3, 8: 0}, 1},       // Window column 0 at X 0
+		{166, map[int]uint8{0: 1, 158: 1, 159: 0}, 1}, // Window column 0 at X 159
+		{167, map[int]uint8{0: 1, 158: 1, 159: 1}, 0}, // Off-screen
+	}
+
+	for _, tt := range tests {
+		ppu := New(nil)
+		// Tile 1 (window): only pixel 7 is color 3; tile 2 (background): solid color 1
+		for row := 0; row < 8; row++ {
+			ppu.vram[0][16+row*2] = 0x01
+			ppu.vram[0][16+row*2+1] = 0x01
+			ppu.vram[0][32+row*2] = 0xFF
+		}
+		for i := range 32 {
+			ppu.vram[0][0x1800+i] = 2
+			ppu.vram[0][0x1C00+i] = 1
+		}
+		ppu.lcdc = LCDCLCDEnable | LCDCBGWindowEnable | LCDCBGTileData |
+			LCDCWindowEnable | LCDCWindowTileMap
+		ppu.bgp = 0xE4
+		ppu.wx = tt.wx
+
+		ppu.renderScanline()
+
+		for x, want := range tt.want {
+			if got := ppu.framebuffer[x]; got != want {
+				t.Errorf("WX=%d: pixel %d = %d, want %d", tt.wx, x, got, want)
+			}
+		}
+		if ppu.windowLine != tt.wantLines {
+			t.Errorf("WX=%d: window line = %d, want %d", tt.wx, ppu.windowLine, tt.wantLines)
+		}
+	}
+}
+
+// TestLayerMaskSprites tests that hidden sprites are not drawn.
+func TestLayerMaskSprites(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 1)
+
+	ppu.SetLayerMask(true, true, false)
+	ppu.renderScanline()
+	for x := range 8 {
+		if got := ppu.framebuffer[x]; got != 0 {
+			t.Errorf("hidden sprite pixel %d = %d, want 0", x, got)
+		}
+	}
+
+	ppu.SetLayerMask(true, true, true)
+	ppu.renderScanline()
+	if got := ppu.framebuffer[0]; got != 3 {
+		t.Errorf("sprite pixel 0 = %d, want 3", got)
+	}
+}
+
+// TestLayerMaskWindow tests that with the window hidden only the background
+// and sprites are drawn.
+func TestLayerMaskWindow(t *testing.T) {
+	ppu := New(nil)
+	setupSpriteLine(ppu, 1) // Sprite at X 0-7 using solid tile 1
+
+	// Background uses blank tile 0; the window (from X 80) uses tile 1
+	for i := range 32 {
+		ppu.vram[0][0x1C00+i] = 1
+	}
+	ppu.lcdc = LCDCLCDEnable | LCDCBGWindowEnable | LCDCBGTileData |
+		LCDCWindowEnable | LCDCWindowTileMap | LCDCOBJEnable
+	ppu.bgp = 0xE4
+	ppu.wy = 0
+	ppu.wx = 7 + 80
+
+	ppu.SetLayerMask(true, false, true)
+	ppu.renderScanline()
+	if got := ppu.framebuffer[100]; got != 0 {
+		t.Errorf("hidden window pixel = %d, want 0 (background)", got)
+	}
+	if got := ppu.framebuffer[0]; got != 3 {
+		t.Errorf("sprite pixel = %d, want 3", got)
+	}
+
+	ppu.SetLayerMask(true, true, true)
+	ppu.renderScanline()
+	if got := ppu.framebuffer[100]; got != 3 {
+		t.Errorf("window pixel = %d, want 3", got)
+	}
+}
+
+// TestLayerMaskBackground tests that a hidden background renders as color 0.
+func TestLayerMaskBackground(t *testing.T) {
+	ppu := New(nil)
+	for i := range 16 {
+		ppu.vram[0][i] = 0xFF // Tile 0: all pixels color 3
+	}
+	ppu.lcdc = LCDCLCDEnable | LCDCBGWindowEnable | LCDCBGTileData
+	ppu.bgp = 0xE4
+
+	ppu.SetLayerMask(false, true, true)
+	ppu.renderScanline()
+	if got := ppu.framebuffer[0]; got != 0 {
+		t.Errorf("hidden background pixel = %d, want 0", got)
+	}
+}
@@ -0,0 +1,185 @@
+package ppu
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrInvalidState indicates saved PPU state is inconsistent.
+var ErrInvalidState = errors.New("invalid PPU state")
+
+// ppuState is the PPU state written by SaveState.
+type ppuState struct {
+	VRAM [2][VRAMSize]uint8
+	OAM  [OAMSize]uint8
+	VBK  uint8
+
+	LCDC, STAT, SCY, SCX, LY, LYC uint8
+	BGP, OBP0, OBP1, WY, WX       uint8
+
+	Mode        uint8
+	Dots        uint16
+	DrawingDots uint16
+	HBlankDots  uint16
+	WindowLine  uint8
+
+	Framebuffer  [ScreenWidth * ScreenHeight]uint8
+	BGColorIndex [ScreenWidth]uint8
+	FrameCount   uint64
+
+	FIFO fifoState
+}
+
+// fifoState is the pixel FIFO renderer's per-scanline state. The sprites on
+// the line follow it, SpriteCount records long.
+type fifoState struct {
+	BG        [8]uint8
+	BGLen     int32
+	OBJColor  [8]uint8
+	OBJAttrs  [8]uint8
+	OBJLen    int32
+	LX        int32
+	Discard   int32
+	FetchX    uint16
+	FetchDots int32
+	Window    bool
+	Stall     int32
+
+	SpriteCount uint8
+	NextSprite  int32
+	Dots        uint16
+}
+
+// spriteState is a sprite on the current line of the FIFO renderer.
+type spriteState struct {
+	X, Y      int16
+	TileIndex uint8
+	Attrs     uint8
+	OAMIndex  uint8
+}
+
+// SaveState writes video memory, the registers and the rendering state to w.
+// Configuration (renderer choice, sprite limit, layer mask, access blocking
+// and CGB mode) is not saved.
+func (p *PPU) SaveState(w io.Writer) error {
+	f := &p.fifo
+	s := ppuState{
+		VRAM: p.vram,
+		OAM:  p.oam,
+		VBK:  p.vbk,
+
+		LCDC: p.lcdc, STAT: p.stat, SCY: p.scy, SCX: p.scx, LY: p.ly, LYC: p.lyc,
+		BGP: p.bgp, OBP0: p.obp0, OBP1: p.obp1, WY: p.wy, WX: p.wx,
+
+		Mode:        p.mode,
+		Dots:        p.dots,
+		DrawingDots: p.drawingDots,
+		HBlankDots:  p.hblankDots,
+		WindowLine:  p.windowLine,
+
+		Framebuffer:  p.framebuffer,
+		BGColorIndex: p.bgColorIndex,
+		FrameCount:   p.frameCount,
+
+		FIFO: fifoState{
+			BG:          f.bg,
+			BGLen:       int32(f.bgLen),   //nolint:gosec // G115: At most 8
+			OBJLen:      int32(f.objLen),  //nolint:gosec // G115: At most 8
+			LX:          int32(f.lx),      //nolint:gosec // G115: At most ScreenWidth
+			Discard:     int32(f.discard), //nolint:gosec // G115: At most 7
+			FetchX:      f.fetchX,
+			FetchDots:   int32(f.fetchDots), //nolint:gosec // G115: Small dot count
+			Window:      f.window,
+			Stall:       int32(f.stall),        //nolint:gosec // G115: Small dot count
+			SpriteCount: uint8(len(f.sprites)), //nolint:gosec // G115: At most 40 (one per OAM entry)
+			NextSprite:  int32(f.nextSprite),   //nolint:gosec // G115: At most 40
+			Dots:        f.dots,
+		},
+	}
+	for i, px := range f.obj {
+		s.FIFO.OBJColor[i] = px.color
+		s.FIFO.OBJAttrs[i] = px.attrs
+	}
+	if err := binary.Write(w, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to write PPU state: %w", err)
+	}
+
+	sprites := make([]spriteState, len(f.sprites))
+	for i, spr := range f.sprites {
+		sprites[i] = spriteState{
+			X:         spr.x,
+			Y:         spr.y,
+			TileIndex: spr.tileIndex,
+			Attrs:     spr.attrs,
+			OAMIndex:  uint8(spr.oamIndex), //nolint:gosec // G115: OAM index is below 40
+		}
+	}
+	if err := binary.Write(w, binary.LittleEndian, sprites); err != nil {
+		return fmt.Errorf("failed to write PPU state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState. Configuration and the
+// interrupt callback are kept.
+func (p *PPU) LoadState(r io.Reader) error {
+	var s ppuState
+	if err := binary.Read(r, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to read PPU state: %w", err)
+	}
+	if s.FIFO.SpriteCount > oamSpriteCount || s.FIFO.BGLen > 8 || s.FIFO.OBJLen > 8 || s.FIFO.BGLen < 0 || s.FIFO.OBJLen < 0 {
+		return fmt.Errorf("%w: FIFO lengths out of range", ErrInvalidState)
+	}
+	sprites := make([]spriteState, s.FIFO.SpriteCount)
+	if err := binary.Read(r, binary.LittleEndian, sprites); err != nil {
+		return fmt.Errorf("failed to read PPU state: %w", err)
+	}
+
+	p.vram = s.VRAM
+	p.oam = s.OAM
+	p.vbk = s.VBK
+
+	p.lcdc, p.stat, p.scy, p.scx, p.ly, p.lyc = s.LCDC, s.STAT, s.SCY, s.SCX, s.LY, s.LYC
+	p.bgp, p.obp0, p.obp1, p.wy, p.wx = s.BGP, s.OBP0, s.OBP1, s.WY, s.WX
+
+	p.mode = s.Mode
+	p.dots = s.Dots
+	p.drawingDots = s.DrawingDots
+	p.hblankDots = s.HBlankDots
+	p.windowLine = s.WindowLine
+
+	p.framebuffer = s.Framebuffer
+	p.bgColorIndex = s.BGColorIndex
+	p.frameCount = s.FrameCount
+
+	f := &p.fifo
+	*f = pixelFIFO{
+		bg:         s.FIFO.BG,
+		bgLen:      int(s.FIFO.BGLen),
+		objLen:     int(s.FIFO.OBJLen),
+		lx:         int(s.FIFO.LX),
+		discard:    int(s.FIFO.Discard),
+		fetchX:     s.FIFO.FetchX,
+		fetchDots:  int(s.FIFO.FetchDots),
+		window:     s.FIFO.Window,
+		stall:      int(s.FIFO.Stall),
+		sprites:    f.sprites[:0],
+		nextSprite: int(s.FIFO.NextSprite),
+		dots:       s.FIFO.Dots,
+	}
+	for i := range f.obj {
+		f.obj[i] = objPixel{color: s.FIFO.OBJColor[i], attrs: s.FIFO.OBJAttrs[i]}
+	}
+	for _, spr := range sprites {
+		f.sprites = append(f.sprites, sprite{
+			x:         spr.X,
+			y:         spr.Y,
+			tileIndex: spr.TileIndex,
+			attrs:     spr.Attrs,
+			oamIndex:  int(spr.OAMIndex),
+		})
+	}
+	return nil
+}
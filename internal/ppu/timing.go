@@ -0,0 +1,77 @@
+package ppu
+
+// Mode 3 timing for the scanline renderer.
+//
+// The scanline renderer draws a whole line at once, so it cannot derive the
+// Mode 3 duration from the pixel pipeline like the FIFO renderer does.
+// Instead the duration is estimated at the start of Mode 3 from the same
+// penalties (see Pan Docs, "Mode 3 length"):
+//   - SCX%8 dots for the pixels discarded by fine scrolling.
+//   - 6-11 dots per sprite fetch, depending on how long the fetch waits for
+//     the background fetcher.
+//
+// H-Blank is shortened by the same amount so the scanline stays 456 dots.
+
+const (
+	// spriteFetchPenalty is the minimum number of dots a sprite fetch adds to Mode 3.
+	spriteFetchPenalty = 6
+	// spriteLeftEdgePenalty is the penalty of a sprite at OAM X 0.
+	spriteLeftEdgePenalty = 11
+	// maxSpriteWaitPenalty is the most a sprite fetch waits for a background fetch.
+	maxSpriteWaitPenalty = 5
+	// maxDrawingDots keeps Mode 3 within the scanline.
+	maxDrawingDots = DotsPerScanline - DotsOAMScan
+)
+
+// mode3Length estimates the Mode 3 duration of the current scanline.
+func (p *PPU) mode3Length() uint16 {
+	length := uint16(DotsDrawing) + uint16(p.scx%8)
+
+	if p.lcdc&LCDCOBJEnable != 0 {
+		length += p.spritePenalty()
+	}
+
+	if length > maxDrawingDots {
+		length = maxDrawingDots
+	}
+	return length
+}
+
+// spritePenalty returns the dots added to Mode 3 by the sprites on the current scanline.
+func (p *PPU) spritePenalty() uint16 {
+	spriteHeight := uint16(8)
+	if p.lcdc&LCDCOBJSize != 0 {
+		spriteHeight = 16
+	}
+	p.scanSprites(spriteHeight)
+
+	// The background fetcher only has to be waited for once per tile
+	var tileSeen [64]bool
+
+	penalty := uint16(0)
+	for _, spr := range p.spriteBuffer {
+		oamX := int(spr.x) + 8
+
+		// Sprites past the right edge are never fetched
+		if oamX >= ScreenWidth+8 {
+			continue
+		}
+
+		if oamX == 0 {
+			penalty += spriteLeftEdgePenalty
+			continue
+		}
+
+		penalty += spriteFetchPenalty
+
+		pos := oamX + int(p.scx%8)
+		tile := pos / 8
+		if !tileSeen[tile] {
+			tileSeen[tile] = true
+			if wait := maxSpriteWaitPenalty - pos%8; wait > 0 {
+				penalty += uint16(wait) //nolint:gosec // wait is 1-5
+			}
+		}
+	}
+	return penalty
+}
@@ -0,0 +1,88 @@
+package ppu
+
+import "testing"
+
+// TestMode3LengthSCX tests that fine scrolling lengthens Mode 3 by SCX%8 dots.
+func TestMode3LengthSCX(t *testing.T) {
+	for scx := uint8(0); scx < 16; scx++ {
+		ppu := New(nil)
+		ppu.scx = scx
+
+		mode3, hblank := measureLine(t, ppu)
+
+		if want := DotsDrawing + int(scx%8); mode3 != want {
+			t.Errorf("SCX=%d: Mode 3 length = %d, want %d", scx, mode3, want)
+		}
+		if want := DotsHBlank - int(scx%8); hblank != want {
+			t.Errorf("SCX=%d: H-Blank length = %d, want %d", scx, hblank, want)
+		}
+	}
+}
+
+// TestMode3LengthSprites tests that more sprites lengthen Mode 3 and shorten H-Blank.
+func TestMode3LengthSprites(t *testing.T) {
+	prevMode3, prevHBlank := 0, 0
+	for count := 0; count <= DefaultSpriteLimit; count++ {
+		ppu := New(nil)
+		ppu.lcdc |= LCDCOBJEnable
+		for i := 0; i < count; i++ {
+			ppu.oam[i*4] = 16                // Y = 0 on screen
+			ppu.oam[i*4+1] = uint8(8 + i*16) //nolint:gosec // Test values are small
+		}
+
+		mode3, hblank := measureLine(t, ppu)
+
+		if mode3+hblank != DotsPerScanline-DotsOAMScan {
+			t.Errorf("%d sprites: Mode 3 + H-Blank = %d, want %d", count, mode3+hblank, DotsPerScanline-DotsOAMScan)
+		}
+		if count > 0 {
+			if penalty := mode3 - prevMode3; penalty < spriteFetchPenalty || penalty > spriteLeftEdgePenalty {
+				t.Errorf("%d sprites: sprite penalty = %d dots, want 6-11", count, penalty)
+			}
+			if hblank >= prevHBlank {
+				t.Errorf("%d sprites: H-Blank = %d, want less than %d", count, hblank, prevHBlank)
+			}
+		}
+		prevMode3, prevHBlank = mode3, hblank
+	}
+}
+
+// TestMode3LengthSpritePenalties tests individual sprite penalties.
+func TestMode3LengthSpritePenalties(t *testing.T) {
+	tests := []struct {
+		name string
+		oamX uint8
+		want int
+	}{
+		{"Left edge (X=0)", 0, DotsDrawing + 11},
+		{"Tile aligned (X=8)", 8, DotsDrawing + 11},
+		{"Mid tile (X=11)", 11, DotsDrawing + 8},
+		{"Late in tile (X=13)", 13, DotsDrawing + 6},
+		{"Off right edge (X=168)", 168, DotsDrawing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ppu := New(nil)
+			ppu.lcdc |= LCDCOBJEnable
+			ppu.oam[0] = 16
+			ppu.oam[1] = tt.oamX
+
+			if mode3, _ := measureLine(t, ppu); mode3 != tt.want {
+				t.Errorf("Mode 3 length = %d, want %d", mode3, tt.want)
+			}
+		})
+	}
+}
+
+// TestMode3LengthSpritesDisabled tests that sprites add no penalty when OBJ is disabled.
+func TestMode3LengthSpritesDisabled(t *testing.T) {
+	ppu := New(nil)
+	ppu.lcdc &^= LCDCOBJEnable
+	ppu.oam[0] = 16
+	ppu.oam[1] = 8
+
+	if mode3, _ := measureLine(t, ppu); mode3 != DotsDrawing {
+		t.Errorf("Mode 3 length = %d, want %d", mode3, DotsDrawing)
+	}
+}
@@ -0,0 +1,70 @@
+// Package romdb identifies known Game Boy ROMs by their global checksum and size.
+//
+// Some homebrew and hacked ROMs have blank or wrong header titles; the
+// database provides a canonical name for known games.
+package romdb
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidEntry indicates a malformed database line.
+var ErrInvalidEntry = errors.New("invalid ROM database entry")
+
+//go:embed romdb.tsv
+var data string
+
+// key identifies a ROM in the database.
+type key struct {
+	checksum uint16
+	size     int
+}
+
+// entries is the parsed database, loaded on first use.
+var entries = sync.OnceValue(func() map[key]string {
+	db, err := parse(data)
+	if err != nil {
+		panic(err) // The embedded database is validated by tests
+	}
+	return db
+})
+
+// Lookup returns the canonical name of the ROM with the given global checksum
+// (header bytes 0x014E-0x014F, big-endian) and size in bytes.
+func Lookup(globalChecksum uint16, size int) (name string, ok bool) {
+	name, ok = entries()[key{globalChecksum, size}]
+	return name, ok
+}
+
+// parse parses tab-separated "checksum size name" lines, where checksum is
+// hexadecimal. Blank lines and lines starting with '#' are ignored.
+func parse(text string) (map[key]string, error) {
+	db := make(map[key]string)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 || fields[2] == "" {
+			return nil, fmt.Errorf("%w: line %d: want 3 tab-separated fields", ErrInvalidEntry, i+1)
+		}
+		checksum, err := strconv.ParseUint(fields[0], 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: checksum %q", ErrInvalidEntry, i+1, fields[0])
+		}
+		size, err := strconv.Atoi(fields[1])
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("%w: line %d: size %q", ErrInvalidEntry, i+1, fields[1])
+		}
+
+		db[key{uint16(checksum), size}] = fields[2] //nolint:gosec // G115: ParseUint limits checksum to 16 bits
+	}
+	return db, nil
+}
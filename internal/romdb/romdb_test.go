@@ -0,0 +1,54 @@
+package romdb
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		checksum uint16
+		size     int
+		wantName string
+		wantOK   bool
+	}{
+		{"Tetris", 0x16BF, 32 * 1024, "Tetris (World)", true},
+		{"Pokemon Red", 0x91E6, 1024 * 1024, "Pokemon - Red Version (USA, Europe)", true},
+		{"Unknown checksum", 0x1234, 32 * 1024, "", false},
+		{"Size mismatch", 0x16BF, 64 * 1024, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ok := Lookup(tt.checksum, tt.size)
+			if name != tt.wantName || ok != tt.wantOK {
+				t.Errorf("Lookup(0x%04X, %d) = (%q, %v), want (%q, %v)",
+					tt.checksum, tt.size, name, ok, tt.wantName, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEmbeddedDatabaseParses(t *testing.T) {
+	db, err := parse(data)
+	if err != nil {
+		t.Fatalf("parse(embedded) error = %v", err)
+	}
+	if len(db) == 0 {
+		t.Error("embedded database is empty")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, line := range []string{
+		"16BF\t32768",
+		"XYZ\t32768\tName",
+		"16BF\tbig\tName",
+		"16BF\t0\tName",
+	} {
+		if _, err := parse(line); !errors.Is(err, ErrInvalidEntry) {
+			t.Errorf("parse(%q) error = %v, want ErrInvalidEntry", line, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package testrom
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Subtest is the outcome of one numbered test in a Blargg suite ROM.
+type Subtest struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+}
+
+// blarggSubtest matches a "NN:result" token, such as "01:ok" or "02:05"
+// (a failure code).
+var blarggSubtest = regexp.MustCompile(`^(\d{2}):(\w+)$`)
+
+// BlarggOutput is serial output from a Blargg ROM parsed into a structured result.
+type BlarggOutput struct {
+	Passed   bool
+	Failed   bool
+	Subtests []Subtest
+}
+
+// ParseBlargg parses the serial output of a Blargg test ROM. Suite ROMs such
+// as cpu_instrs print one "NN:ok" token per subtest followed by "Passed" or
+// "Failed"; single-test ROMs print only the final verdict. A failing subtest
+// marks the whole output as failed. If the output is truncated, neither Passed
+// nor Failed is set and Subtests holds the subtests completed so far.
+func ParseBlargg(output string) BlarggOutput {
+	var parsed BlarggOutput
+	tokens := strings.Fields(output)
+	// A final token with no whitespace after it may have been cut off by a
+	// timeout, so it is not mistaken for a result
+	if last, _ := utf8.DecodeLastRuneInString(output); len(tokens) > 0 && !unicode.IsSpace(last) {
+		tokens = tokens[:len(tokens)-1]
+	}
+	for _, token := range tokens {
+		match := blarggSubtest.FindStringSubmatch(token)
+		if match == nil {
+			continue
+		}
+		subtest := Subtest{Name: match[1], Passed: match[2] == "ok"}
+		if !subtest.Passed {
+			parsed.Failed = true
+		}
+		parsed.Subtests = append(parsed.Subtests, subtest)
+	}
+
+	// Check "Failed" first to avoid ambiguity if both strings are present
+	if strings.Contains(output, "Failed") {
+		parsed.Failed = true
+	}
+	parsed.Passed = strings.Contains(output, "Passed") && !parsed.Failed
+	return parsed
+}
@@ -0,0 +1,77 @@
+package testrom
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseBlarggAllOK(t *testing.T) {
+	output := "cpu_instrs\n\n01:ok  02:ok  03:ok  04:ok  05:ok  06:ok  07:ok  08:ok  09:ok  10:ok  11:ok  \n\nPassed all tests\n"
+
+	parsed := ParseBlargg(output)
+	if !parsed.Passed || parsed.Failed {
+		t.Errorf("Passed = %v, Failed = %v, want true, false", parsed.Passed, parsed.Failed)
+	}
+	if len(parsed.Subtests) != 11 {
+		t.Fatalf("len(Subtests) = %d, want 11", len(parsed.Subtests))
+	}
+	for i, subtest := range parsed.Subtests {
+		if !subtest.Passed {
+			t.Errorf("Subtests[%d] = %+v, want passed", i, subtest)
+		}
+	}
+	if got := parsed.Subtests[10].Name; got != "11" {
+		t.Errorf("Subtests[10].Name = %q, want %q", got, "11")
+	}
+}
+
+func TestParseBlarggOneFailing(t *testing.T) {
+	output := "cpu_instrs\n\n01:ok  02:04  03:ok  \n\nFailed 1 tests.\n"
+
+	parsed := ParseBlargg(output)
+	if parsed.Passed || !parsed.Failed {
+		t.Errorf("Passed = %v, Failed = %v, want false, true", parsed.Passed, parsed.Failed)
+	}
+	want := []Subtest{{"01", true}, {"02", false}, {"03", true}}
+	if !slices.Equal(parsed.Subtests, want) {
+		t.Errorf("Subtests = %+v, want %+v", parsed.Subtests, want)
+	}
+}
+
+func TestParseBlarggSeparators(t *testing.T) {
+	want := []Subtest{{"01", true}, {"02", true}, {"03", true}, {"04", true}}
+	for _, output := range []string{
+		"01:ok 02:ok 03:ok 04:ok \nPassed all tests\n",
+		"01:ok\n02:ok\n03:ok\n04:ok\n\nPassed all tests\n",
+		"01:ok 02:ok\n03:ok\t04:ok\n",
+	} {
+		parsed := ParseBlargg(output)
+		if !slices.Equal(parsed.Subtests, want) {
+			t.Errorf("ParseBlargg(%q).Subtests = %+v, want %+v", output, parsed.Subtests, want)
+		}
+	}
+}
+
+func TestParseBlarggTruncated(t *testing.T) {
+	// A timeout cut the output off in the middle of subtest 03
+	output := "cpu_instrs\n\n01:ok  02:ok  03:o"
+
+	parsed := ParseBlargg(output)
+	if parsed.Passed || parsed.Failed {
+		t.Errorf("Passed = %v, Failed = %v, want false, false", parsed.Passed, parsed.Failed)
+	}
+	want := []Subtest{{"01", true}, {"02", true}}
+	if !slices.Equal(parsed.Subtests, want) {
+		t.Errorf("Subtests = %+v, want %+v", parsed.Subtests, want)
+	}
+}
+
+func TestParseBlarggSingleTest(t *testing.T) {
+	parsed := ParseBlargg("01-special\n\n\nPassed\n")
+	if !parsed.Passed {
+		t.Error("Passed = false, want true")
+	}
+	if len(parsed.Subtests) != 0 {
+		t.Errorf("Subtests = %+v, want none", parsed.Subtests)
+	}
+}
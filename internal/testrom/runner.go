@@ -4,6 +4,7 @@ package testrom
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -13,16 +14,59 @@ import (
 
 // Result represents the result of running a test ROM.
 type Result struct {
-	Output  string
-	Passed  bool
-	Failed  bool
-	Timeout bool
-	Error   error
+	Output   string
+	Passed   bool
+	Failed   bool
+	Timeout  bool
+	Error    error
+	Duration time.Duration
+
+	// Subtests lists the numbered subtests reported by Blargg suite ROMs.
+	Subtests []Subtest
+}
+
+// Report is a machine-readable summary of a test ROM result.
+type Report struct {
+	ROM        string `json:"rom"`
+	Result     string `json:"result"`
+	Output     string `json:"output"`
+	DurationMS int64  `json:"duration_ms"`
+
+	Subtests []Subtest `json:"subtests,omitempty"`
+}
+
+// Options configures optional test ROM runner behavior.
+type Options struct {
+	// DoctorLog receives a Gameboy Doctor compatible CPU log (nil = disabled).
+	DoctorLog io.Writer
+
+	// Markers are extra serial output substrings that end the run and count as a pass.
+	// The default "Passed"/"Failed" markers always apply.
+	Markers []string
+
+	// StableDuration overrides how long output must be unchanged before the run ends
+	// (0 = emulator default).
+	StableDuration time.Duration
+
+	// StopOnHalt ends the run as soon as the CPU halts.
+	StopOnHalt bool
+
+	// FIFORenderer selects the pixel FIFO renderer for accurate PPU timing.
+	FIFORenderer bool
 }
 
 // Run executes a test ROM and returns the result.
 func Run(romPath string, timeout time.Duration) *Result {
+	return RunWithOptions(romPath, timeout, Options{})
+}
+
+// RunWithOptions executes a test ROM with the given options and returns the result.
+func RunWithOptions(romPath string, timeout time.Duration, opts Options) *Result {
 	result := &Result{}
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
 
 	// Read ROM file
 	// #nosec G304 - romPath is provided by the user via CLI argument
@@ -32,16 +76,30 @@ func Run(romPath string, timeout time.Duration) *Result {
 		return result
 	}
 
-	// Create emulator
-	emu, err := emulator.New(data)
+	// Create emulator; test ROMs run in DMG mode whatever their CGB flag
+	emu, err := emulator.NewWithOptions(data, emulator.Options{ForceDMG: true})
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create emulator: %w", err)
 		return result
 	}
 
+	if opts.DoctorLog != nil {
+		emu.SetDoctorLog(opts.DoctorLog)
+	}
+	emu.PPU.SetFIFORenderer(opts.FIFORenderer)
+
 	// Run until output or timeout
-	output, err := emu.RunUntilOutput(timeout)
+	runOpts := emulator.DefaultRunOptions()
+	runOpts.Markers = append(runOpts.Markers, opts.Markers...)
+	if opts.StableDuration > 0 {
+		runOpts.StableDuration = opts.StableDuration
+	}
+	runOpts.StopOnHalt = opts.StopOnHalt
+
+	output, err := emu.RunUntilOutputWithOptions(timeout, runOpts)
 	result.Output = output
+	parsed := ParseBlargg(output)
+	result.Subtests = parsed.Subtests
 
 	if err != nil {
 		if errors.Is(err, emulator.ErrTimeout) {
@@ -51,14 +109,37 @@ func Run(romPath string, timeout time.Duration) *Result {
 		return result
 	}
 
-	// Parse output for pass/fail
-	// Check "Failed" first to avoid ambiguity if both strings are present
-	result.Failed = strings.Contains(output, "Failed")
-	result.Passed = strings.Contains(output, "Passed") && !result.Failed
+	// Mooneye test ROMs report through registers rather than serial text
+	switch emu.MooneyeResult() {
+	case emulator.MooneyePassed:
+		result.Passed = true
+		return result
+	case emulator.MooneyeFailed:
+		result.Failed = true
+		return result
+	case emulator.MooneyeNone:
+	}
+
+	// Parse output for pass/fail; a failing subtest fails the whole ROM
+	result.Failed = parsed.Failed
+	result.Passed = containsAny(output, "Passed", opts.Markers...) && !result.Failed
 
 	return result
 }
 
+// containsAny reports whether output contains marker or any of the extra markers.
+func containsAny(output, marker string, extra ...string) bool {
+	if strings.Contains(output, marker) {
+		return true
+	}
+	for _, m := range extra {
+		if strings.Contains(output, m) {
+			return true
+		}
+	}
+	return false
+}
+
 // String returns a human-readable representation of the result.
 func (r *Result) String() string {
 	if r.Error != nil && !r.Timeout {
@@ -80,6 +161,28 @@ func (r *Result) String() string {
 	return "UNKNOWN"
 }
 
+// Report returns a machine-readable summary of the result for the given ROM path.
+func (r *Result) Report(romPath string) Report {
+	return Report{
+		ROM:        romPath,
+		Result:     r.String(),
+		Output:     r.Output,
+		DurationMS: r.Duration.Milliseconds(),
+		Subtests:   r.Subtests,
+	}
+}
+
+// FailedSubtests returns the names of the subtests that failed.
+func (r *Result) FailedSubtests() []string {
+	var names []string
+	for _, subtest := range r.Subtests {
+		if !subtest.Passed {
+			names = append(names, subtest.Name)
+		}
+	}
+	return names
+}
+
 // IsSuccess returns true if the test passed.
 func (r *Result) IsSuccess() bool {
 	return r.Passed && !r.Failed && r.Error == nil
@@ -0,0 +1,52 @@
+package timer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// timerState is the timer state written by SaveState.
+type timerState struct {
+	DIVCounter  uint16
+	TIMA        uint8
+	TMA         uint8
+	TAC         uint8
+	Enabled     bool
+	ClockSelect uint8
+	DoubleSpeed bool
+}
+
+// SaveState writes the timer registers and internal counter to w.
+func (t *Timer) SaveState(w io.Writer) error {
+	s := timerState{
+		DIVCounter:  t.divCounter,
+		TIMA:        t.tima,
+		TMA:         t.tma,
+		TAC:         t.tac,
+		Enabled:     t.enabled,
+		ClockSelect: t.clockSelect,
+		DoubleSpeed: t.doubleSpeed,
+	}
+	if err := binary.Write(w, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to write timer state: %w", err)
+	}
+	return nil
+}
+
+// LoadState restores state written by SaveState. Callbacks are kept.
+func (t *Timer) LoadState(r io.Reader) error {
+	var s timerState
+	if err := binary.Read(r, binary.LittleEndian, &s); err != nil {
+		return fmt.Errorf("failed to read timer state: %w", err)
+	}
+
+	t.divCounter = s.DIVCounter
+	t.tima = s.TIMA
+	t.tma = s.TMA
+	t.tac = s.TAC
+	t.enabled = s.Enabled
+	t.clockSelect = s.ClockSelect
+	t.doubleSpeed = s.DoubleSpeed
+	return nil
+}
@@ -8,11 +8,20 @@
 //
 // The timer uses falling edge detection on specific bits of the internal
 // DIV counter to increment TIMA at the selected frequency.
+//
+// The APU frame sequencer is also clocked from the DIV counter: each falling
+// edge of bit 12 (DIV bit 4) is one 512 Hz frame sequencer step. Because a DIV
+// write resets the counter, it can produce an extra step and shifts the
+// timing of all following steps. In CGB double-speed mode the counter runs
+// twice as fast, so bit 13 (DIV bit 5) is used instead to keep 512 Hz.
 package timer
 
 // InterruptCallback is the function type for timer interrupt requests.
 type InterruptCallback func()
 
+// FrameSequencerCallback is the function type for APU frame sequencer clocks.
+type FrameSequencerCallback func()
+
 // Timer represents the Game Boy timer system.
 type Timer struct {
 	divCounter uint16 // Internal 16-bit counter (DIV is upper 8 bits)
@@ -25,6 +34,12 @@ type Timer struct {
 
 	// Callback for timer interrupt
 	requestInterrupt InterruptCallback
+
+	// Callback for APU frame sequencer clocks (nil = disabled)
+	clockFrameSequencer FrameSequencerCallback
+
+	// CGB double-speed mode (selects the DIV-APU bit)
+	doubleSpeed bool
 }
 
 // Register addresses.
@@ -41,6 +56,12 @@ const (
 	tacClockMask = 0x03 // Bits 1-0: Clock select
 )
 
+// DIV-APU counter bits whose falling edge clocks the APU frame sequencer.
+const (
+	divAPUBit            = 12
+	divAPUBitDoubleSpeed = 13
+)
+
 // New creates a new Timer with the given interrupt callback.
 func New(requestInterrupt InterruptCallback) *Timer {
 	return &Timer{
@@ -48,6 +69,26 @@ func New(requestInterrupt InterruptCallback) *Timer {
 	}
 }
 
+// SetFrameSequencerCallback sets the callback invoked on each falling edge of
+// DIV bit 4 (internal counter bit 12), used to clock the APU frame sequencer.
+func (t *Timer) SetFrameSequencerCallback(callback FrameSequencerCallback) {
+	t.clockFrameSequencer = callback
+}
+
+// SetDoubleSpeed sets CGB double-speed mode. The timer itself is clocked by the
+// CPU, so only the DIV-APU bit changes.
+func (t *Timer) SetDoubleSpeed(doubleSpeed bool) {
+	t.doubleSpeed = doubleSpeed
+}
+
+// divAPUBit returns the counter bit whose falling edge clocks the APU.
+func (t *Timer) divAPUBit() uint {
+	if t.doubleSpeed {
+		return divAPUBitDoubleSpeed
+	}
+	return divAPUBit
+}
+
 // Read reads a timer register.
 func (t *Timer) Read(addr uint16) uint8 {
 	switch addr {
@@ -72,6 +113,10 @@ func (t *Timer) Write(addr uint16, value uint8) {
 		if t.enabled {
 			t.checkFallingEdge(t.divCounter, 0)
 		}
+		// Resetting the counter while the DIV-APU bit is set is also a DIV-APU falling edge
+		if t.clockFrameSequencer != nil && t.divCounter&(1<<t.divAPUBit()) != 0 {
+			t.clockFrameSequencer()
+		}
 		t.divCounter = 0
 
 	case TIMA:
@@ -107,6 +152,10 @@ func (t *Timer) Write(addr uint16, value uint8) {
 
 // Update advances the timer by the given number of CPU cycles.
 func (t *Timer) Update(cycles uint16) {
+	if t.clockFrameSequencer != nil {
+		t.updateFrameSequencer(cycles)
+	}
+
 	if !t.enabled {
 		// Timer disabled, only update DIV
 		t.divCounter += cycles
@@ -123,6 +172,18 @@ func (t *Timer) Update(cycles uint16) {
 	}
 }
 
+// updateFrameSequencer clocks the APU frame sequencer once for every falling
+// edge of the DIV-APU bit in the next cycles increments of the DIV counter.
+func (t *Timer) updateFrameSequencer(cycles uint16) {
+	// The bit falls each time the counter crosses a multiple of twice its value
+	period := uint32(1) << (t.divAPUBit() + 1)
+	start := uint32(t.divCounter)
+	edges := (start+uint32(cycles))/period - start/period
+	for range edges {
+		t.clockFrameSequencer()
+	}
+}
+
 // checkFallingEdge checks if a falling edge occurred on the selected timer bit.
 func (t *Timer) checkFallingEdge(oldDiv, newDiv uint16) {
 	oldBit := t.getTimerBit(oldDiv, t.enabled, t.clockSelect)
@@ -176,4 +237,5 @@ func (t *Timer) Reset() {
 	t.tac = 0
 	t.enabled = false
 	t.clockSelect = 0
+	t.doubleSpeed = false
 }
@@ -285,6 +285,88 @@ func TestDIVWriteFallingEdge(t *testing.T) {
 	}
 }
 
+func TestFrameSequencerClock(t *testing.T) {
+	timer := New(nil)
+	clocks := 0
+	timer.SetFrameSequencerCallback(func() { clocks++ })
+
+	// Bit 12 first falls when the counter reaches 8192
+	timer.Update(8191)
+	if clocks != 0 {
+		t.Errorf("Frame sequencer clocks after 8191 cycles = %d, want 0", clocks)
+	}
+
+	timer.Update(1)
+	if clocks != 1 {
+		t.Errorf("Frame sequencer clocks after 8192 cycles = %d, want 1", clocks)
+	}
+
+	// Large updates and the 16-bit wraparound are both counted
+	timer.Update(0xFFFF)
+	timer.Update(8193)
+	if clocks != 10 {
+		t.Errorf("Frame sequencer clocks after 0x20000 cycles = %d, want 10", clocks)
+	}
+}
+
+func TestDIVWriteFrameSequencerTiming(t *testing.T) {
+	timer := New(nil)
+	clocks := 0
+	timer.SetFrameSequencerCallback(func() { clocks++ })
+
+	// Just before the scheduled step, bit 12 is set
+	timer.Update(8000)
+
+	// Resetting DIV drops bit 12, which is an immediate frame sequencer clock
+	timer.Write(DIV, 0x00)
+	if clocks != 1 {
+		t.Errorf("Frame sequencer clocks after DIV write = %d, want 1", clocks)
+	}
+
+	// The originally scheduled step (192 cycles later) no longer happens
+	timer.Update(192)
+	if clocks != 1 {
+		t.Errorf("Frame sequencer clocks at old step time = %d, want 1", clocks)
+	}
+
+	// The next step is a full period after the DIV reset
+	timer.Update(8192 - 192)
+	if clocks != 2 {
+		t.Errorf("Frame sequencer clocks 8192 cycles after DIV write = %d, want 2", clocks)
+	}
+}
+
+func TestDIVWriteNoFrameSequencerClock(t *testing.T) {
+	timer := New(nil)
+	clocks := 0
+	timer.SetFrameSequencerCallback(func() { clocks++ })
+
+	// Bit 12 clear: resetting DIV is not a falling edge
+	timer.Update(4000)
+	timer.Write(DIV, 0x00)
+	if clocks != 0 {
+		t.Errorf("Frame sequencer clocks after DIV write = %d, want 0", clocks)
+	}
+}
+
+func TestFrameSequencerClockDoubleSpeed(t *testing.T) {
+	timer := New(nil)
+	timer.SetDoubleSpeed(true)
+	clocks := 0
+	timer.SetFrameSequencerCallback(func() { clocks++ })
+
+	// Bit 13 falls every 16384 counter increments
+	timer.Update(16383)
+	if clocks != 0 {
+		t.Errorf("Frame sequencer clocks after 16383 cycles = %d, want 0", clocks)
+	}
+
+	timer.Update(1)
+	if clocks != 1 {
+		t.Errorf("Frame sequencer clocks after 16384 cycles = %d, want 1", clocks)
+	}
+}
+
 func TestTACChangeFallingEdge(t *testing.T) {
 	timer := New(nil)
 